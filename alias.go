@@ -0,0 +1,111 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"sort"
+	"time"
+)
+
+// Alias will make `alias` encode to the same code as
+// `canonical`, so that known synonyms of a value share
+// a code while Decode returns the canonical value.
+// The canonical value is encoded if it has no code yet.
+// Aliasing a value that already has a different code
+// returns an `ErrDuplicateValue` error.
+func (e *Ordinal) Alias(canonical, alias string) error {
+	e.lock()
+	defer e.Unlock()
+
+	code, ok := e.encoder[hashString(e.prepare(canonical))]
+	if !ok {
+		code = e.encode(canonical)
+		// aliasing is not an observation of the value
+		delete(e.counts, code)
+	}
+
+	alias = e.prepare(alias)
+	hashedKey := hashString(alias)
+	if current, ok := e.encoder[hashedKey]; ok {
+		if current != code {
+			return ErrDuplicateValue
+		}
+		return nil
+	}
+
+	if e.aliases == nil {
+		e.aliases = make(map[string]string)
+	}
+	e.aliases[alias] = e.decode(code)
+	e.encoder[hashedKey] = code
+	e.updated = time.Now()
+
+	return nil
+}
+
+// Aliases will return a copy of every alias
+// and the canonical value it encodes as.
+func (e *Ordinal) Aliases() map[string]string {
+	e.RLock()
+	defer e.RUnlock()
+
+	aliases := make(map[string]string, len(e.aliases))
+	for alias, canonical := range e.aliases {
+		aliases[alias] = canonical
+	}
+
+	return aliases
+}
+
+// sortedAliases will return every alias in sorted
+// order so that serialized output is stable.
+func (e *Ordinal) sortedAliases() []string {
+	aliases := make([]string, 0, len(e.aliases))
+	for alias := range e.aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	return aliases
+}
+
+// restoreAliases will point every alias at the code of its
+// canonical value after the encoder has been loaded. Aliases
+// whose canonical value has no code are dropped.
+func (e *Ordinal) restoreAliases(aliases map[string]string) {
+	e.aliases = nil
+	for alias, canonical := range aliases {
+		code, ok := e.encoder[hashString(canonical)]
+		if !ok {
+			continue
+		}
+
+		if e.aliases == nil {
+			e.aliases = make(map[string]string)
+		}
+		e.aliases[alias] = canonical
+		e.encoder[hashString(alias)] = code
+	}
+}
+
+// dropAliases will remove every alias of the given codes.
+func (e *Ordinal) dropAliases(codes map[uint64]bool) {
+	for alias := range e.aliases {
+		hashedKey := hashString(alias)
+		if codes[e.encoder[hashedKey]] {
+			delete(e.encoder, hashedKey)
+			delete(e.aliases, alias)
+		}
+	}
+}
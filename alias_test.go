@@ -0,0 +1,81 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+)
+
+func TestOrdinalAlias(t *testing.T) {
+	encoder := NewOrdinal(false)
+	encoder.Encode("USA")
+	if err := encoder.Alias("USA", "U.S.A."); err != nil {
+		t.Fatalf("alias error: %+v", err)
+	}
+	if err := encoder.Alias("U.S.A.", "United States"); err != nil {
+		t.Fatalf("alias of alias error: %+v", err)
+	}
+
+	code := encoder.Encode("United States")
+	if code != 0 || encoder.Encode("U.S.A.") != 0 || encoder.Decode(code) != "USA" {
+		t.Error("aliases did not encode to the canonical code")
+	}
+	if encoder.Length() != 1 {
+		t.Errorf("aliases should not add codes, length %d", encoder.Length())
+	}
+	if encoder.Aliases()["United States"] != "USA" {
+		t.Error("alias table does not hold the canonical value")
+	}
+
+	encoder.Encode("Canada")
+	if err := encoder.Alias("USA", "Canada"); err != ErrDuplicateValue {
+		t.Error("expected duplicate value error")
+	}
+}
+
+func TestOrdinalAliasSerialization(t *testing.T) {
+	encoder := NewOrdinal(false)
+	encoder.Encode("USA")
+	encoder.Encode("Canada")
+	encoder.Alias("USA", "U.S.A.")
+
+	check := func(name string, loaded *Ordinal) {
+		if code, ok := loaded.Snapshot().Lookup("U.S.A."); !ok || code != 0 {
+			t.Errorf("%s: alias was not restored", name)
+		}
+		if loaded.Aliases()["U.S.A."] != "USA" || loaded.Length() != 2 {
+			t.Errorf("%s: alias table was not restored", name)
+		}
+	}
+
+	b, err := json.Marshal(encoder)
+	if err != nil {
+		t.Fatalf("json marshal error: %+v", err)
+	}
+	fromJSON := NewOrdinal(false)
+	if err := json.Unmarshal(b, fromJSON); err != nil {
+		t.Fatalf("json unmarshal error: %+v", err)
+	}
+	check("json", fromJSON)
+
+	b, err = encoder.MarshalCSV()
+	if err != nil {
+		t.Fatalf("csv marshal error: %+v", err)
+	}
+	fromCSV := NewOrdinal(false)
+	if err := fromCSV.UnmarshalCSV(b); err != nil {
+		t.Fatalf("csv unmarshal error: %+v", err)
+	}
+	check("csv", fromCSV)
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(encoder); err != nil {
+		t.Fatalf("gob encode error: %+v", err)
+	}
+	fromGob := NewOrdinal(false)
+	if err := gob.NewDecoder(&buf).Decode(fromGob); err != nil {
+		t.Fatalf("gob decode error: %+v", err)
+	}
+	check("gob", fromGob)
+}
@@ -0,0 +1,175 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"github.com/humilityai/sam"
+)
+
+// arena is an append-only list of strings stored
+// in a single byte buffer, so that a vocabulary of
+// millions of values is held in two allocations
+// instead of one string (and string header) per value.
+type arena struct {
+	data []byte
+	ends []int
+	// overrides holds the strings set in place
+	// of those in the buffer, see set.
+	overrides map[int]string
+}
+
+func newArena(values []string) *arena {
+	var size int
+	for _, v := range values {
+		size += len(v)
+	}
+
+	a := &arena{
+		data: make([]byte, 0, size),
+		ends: make([]int, 0, len(values)),
+	}
+	for _, v := range values {
+		a.append(v)
+	}
+
+	return a
+}
+
+// append will add the string to the end
+// of the arena and return its index.
+func (a *arena) append(s string) int {
+	a.data = append(a.data, s...)
+	a.ends = append(a.ends, len(a.data))
+	return len(a.ends) - 1
+}
+
+// get will return the string at index i.
+func (a *arena) get(i int) string {
+	if s, ok := a.overrides[i]; ok {
+		return s
+	}
+
+	start := 0
+	if i > 0 {
+		start = a.ends[i-1]
+	}
+
+	return string(a.data[start:a.ends[i]])
+}
+
+// len will return the number of strings in the arena.
+func (a *arena) len() int {
+	return len(a.ends)
+}
+
+// strings will return a copy of every string in the arena.
+func (a *arena) strings() sam.SliceString {
+	s := make(sam.SliceString, len(a.ends), len(a.ends))
+	for i := range s {
+		s[i] = a.get(i)
+	}
+
+	return s
+}
+
+// sizeBytes will return the memory held by the arena.
+func (a *arena) sizeBytes() int {
+	size := cap(a.data) + cap(a.ends)*intBytes
+	for _, s := range a.overrides {
+		size += mapEntryBytes + len(s)
+	}
+
+	return size
+}
+
+// view will return an arena sharing the strings
+// currently in the arena. The buffer is append-only
+// and the overrides are copied, so the view is
+// unaffected by later appends and sets.
+func (a *arena) view() *arena {
+	return &arena{
+		data:      a.data[:len(a.data):len(a.data)],
+		ends:      a.ends[:len(a.ends):len(a.ends)],
+		overrides: a.copyOverrides(),
+	}
+}
+
+// clone will return a copy of the arena.
+func (a *arena) clone() *arena {
+	c := &arena{
+		data:      make([]byte, len(a.data), cap(a.data)),
+		ends:      make([]int, len(a.ends), cap(a.ends)),
+		overrides: a.copyOverrides(),
+	}
+	copy(c.data, a.data)
+	copy(c.ends, a.ends)
+
+	return c
+}
+
+// flat will return the arena with every override
+// written into the buffer, for callers that read
+// the buffer directly.
+func (a *arena) flat() *arena {
+	if len(a.overrides) == 0 {
+		return a
+	}
+
+	return a.clear(nil)
+}
+
+func (a *arena) copyOverrides() map[int]string {
+	if a.overrides == nil {
+		return nil
+	}
+
+	overrides := make(map[int]string, len(a.overrides))
+	for i, s := range a.overrides {
+		overrides[i] = s
+	}
+
+	return overrides
+}
+
+// clear will return a copy of the arena with the strings
+// at the given indexes replaced by the empty string, so
+// that their bytes are released without moving the
+// indexes of any other string.
+func (a *arena) clear(indexes map[uint64]bool) *arena {
+	c := &arena{
+		data: make([]byte, 0, len(a.data)),
+		ends: make([]int, 0, len(a.ends)),
+	}
+	for i := 0; i < a.len(); i++ {
+		if indexes[uint64(i)] {
+			c.append("")
+			continue
+		}
+		c.append(a.get(i))
+	}
+
+	return c
+}
+
+// set will replace the string at index i. The buffer
+// is shared with views, so rather than being rewritten
+// the string is held as an override of its index.
+func (a *arena) set(i int, s string) {
+	if a.overrides == nil {
+		a.overrides = make(map[int]string)
+	}
+
+	a.overrides[i] = s
+}
@@ -0,0 +1,234 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// Package batch encodes the string columns of Arrow record
+// batches, so that Arrow-native pipelines can encode their
+// columns without copying them into Go slices and back.
+package batch
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/humilityai/encoder"
+)
+
+// ErrColumn is returned when a column with an
+// encoder is not in the schema of a batch.
+var ErrColumn = errors.New("encoded column is not in the schema")
+
+// Coder is implemented by encoders that encode every value
+// as a single code, such as Ordinal, Hash and BoundedOrdinal.
+// Their columns are encoded as uint64 codes.
+type Coder interface {
+	Encode(s string) uint64
+}
+
+// featureNamer is implemented by encoders that
+// name each of the features of their encoding.
+type featureNamer interface {
+	FeatureNames(column string) []string
+}
+
+// stringArray is implemented by the Arrow string arrays.
+type stringArray interface {
+	arrow.Array
+	Value(i int) string
+}
+
+// Transformer replaces the string columns of record
+// batches with the columns of their encoded features.
+type Transformer struct {
+	columns map[string]encoder.Transformer
+	mem     memory.Allocator
+}
+
+// New will create a transformer that encodes every named
+// column with its encoder, allocating the encoded columns
+// from `mem`, or the default allocator if it is nil.
+// OneHot encoders are frozen for the transformer, as in
+// `encoder.TransformCSV`, so that every batch has the same
+// schema: values they have not seen are encoded as all zeros.
+func New(columns map[string]encoder.Transformer, mem memory.Allocator) *Transformer {
+	if mem == nil {
+		mem = memory.DefaultAllocator
+	}
+
+	fixed := make(map[string]encoder.Transformer, len(columns))
+	for name, e := range columns {
+		if onehot, ok := e.(*encoder.OneHot); ok {
+			e = onehot.Snapshot()
+		}
+		fixed[name] = e
+	}
+
+	return &Transformer{
+		columns: fixed,
+		mem:     mem,
+	}
+}
+
+// Schema will return the schema of the batches transformed from
+// batches of schema `in`. Every encoded column is replaced, in
+// place, by a uint64 column of codes if its encoder is a Coder,
+// and otherwise by a float64 column for every feature, named by
+// the encoder's feature names if it has them. An `ErrColumn`
+// error is returned if an encoded column is not in the schema,
+// and an `encoder.ErrDType` error if it is not a string column.
+func (t *Transformer) Schema(in *arrow.Schema) (*arrow.Schema, error) {
+	for name := range t.columns {
+		if !in.HasField(name) {
+			return nil, ErrColumn
+		}
+	}
+
+	fields := make([]arrow.Field, 0, in.NumFields())
+	for _, field := range in.Fields() {
+		e, ok := t.columns[field.Name]
+		if !ok {
+			fields = append(fields, field)
+			continue
+		}
+
+		switch field.Type.ID() {
+		case arrow.STRING, arrow.LARGE_STRING, arrow.STRING_VIEW:
+		default:
+			return nil, encoder.ErrDType
+		}
+
+		if _, ok := e.(Coder); ok {
+			fields = append(fields, arrow.Field{Name: field.Name, Type: arrow.PrimitiveTypes.Uint64})
+			continue
+		}
+		for _, name := range featureNames(field.Name, e) {
+			fields = append(fields, arrow.Field{Name: name, Type: arrow.PrimitiveTypes.Float64})
+		}
+	}
+
+	metadata := in.Metadata()
+	return arrow.NewSchema(fields, &metadata), nil
+}
+
+// Transform will return a new batch with every encoded column
+// of `rec` replaced as described by Schema. Other columns are
+// shared with `rec`, not copied. Null values are encoded as the
+// empty string. The returned batch must be released by the
+// caller; `rec` is not released.
+func (t *Transformer) Transform(rec arrow.RecordBatch) (arrow.RecordBatch, error) {
+	schema, err := t.Schema(rec.Schema())
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]arrow.Array, 0, schema.NumFields())
+	defer func() {
+		for _, column := range columns {
+			column.Release()
+		}
+	}()
+
+	for i, field := range rec.Schema().Fields() {
+		column := rec.Column(i)
+		e, ok := t.columns[field.Name]
+		if !ok {
+			column.Retain()
+			columns = append(columns, column)
+			continue
+		}
+
+		values, ok := column.(stringArray)
+		if !ok {
+			return nil, encoder.ErrDType
+		}
+		columns = append(columns, t.encode(e, values, len(featureNames(field.Name, e)))...)
+	}
+
+	return array.NewRecordBatch(schema, columns, rec.NumRows()), nil
+}
+
+// encode will return the columns of the encoded values,
+// one for the codes of a Coder and one for every feature
+// of any other encoder.
+func (t *Transformer) encode(e encoder.Transformer, values stringArray, width int) []arrow.Array {
+	if c, ok := e.(Coder); ok {
+		b := array.NewUint64Builder(t.mem)
+		defer b.Release()
+
+		b.Reserve(values.Len())
+		for i := 0; i < values.Len(); i++ {
+			b.UnsafeAppend(c.Encode(value(values, i)))
+		}
+
+		return []arrow.Array{b.NewArray()}
+	}
+
+	builders := make([]*array.Float64Builder, width, width)
+	for j := range builders {
+		builders[j] = array.NewFloat64Builder(t.mem)
+		builders[j].Reserve(values.Len())
+		defer builders[j].Release()
+	}
+
+	for i := 0; i < values.Len(); i++ {
+		features := e.Transform(value(values, i))
+		for j, b := range builders {
+			if j < len(features) {
+				b.UnsafeAppend(features[j])
+			} else {
+				b.UnsafeAppend(0)
+			}
+		}
+	}
+
+	columns := make([]arrow.Array, width, width)
+	for j, b := range builders {
+		columns[j] = b.NewArray()
+	}
+
+	return columns
+}
+
+// featureNames will return the names of the features of the
+// column: the encoder's own names if it has them, the name of
+// the column suffixed by the index of every feature for fixed
+// width encoders, and the name of the column otherwise.
+func featureNames(name string, e encoder.Transformer) []string {
+	if namer, ok := e.(featureNamer); ok {
+		return namer.FeatureNames(name)
+	}
+
+	vector, ok := e.(encoder.Vector)
+	if !ok {
+		return []string{name}
+	}
+
+	names := make([]string, vector.Dimension(), vector.Dimension())
+	for i := range names {
+		names[i] = name + "_" + strconv.Itoa(i)
+	}
+
+	return names
+}
+
+// value will return the string at index i,
+// or the empty string if it is null.
+func value(values stringArray, i int) string {
+	if values.IsNull(i) {
+		return ""
+	}
+
+	return values.Value(i)
+}
@@ -0,0 +1,92 @@
+package batch
+
+import (
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/humilityai/encoder"
+)
+
+func testBatch(mem memory.Allocator) arrow.RecordBatch {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "color", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "size", Type: arrow.BinaryTypes.String},
+	}, nil)
+
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+
+	b.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2, 3}, nil)
+	b.Field(1).(*array.StringBuilder).AppendValues([]string{"red", "", "blue"}, []bool{true, false, true})
+	b.Field(2).(*array.StringBuilder).AppendValues([]string{"small", "large", "small"}, nil)
+
+	return b.NewRecordBatch()
+}
+
+func TestTransform(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	onehot := encoder.NewOneHot()
+	onehot.Encode("red")
+	onehot.Encode("blue")
+	sizes := encoder.NewOrdinal(false)
+
+	rec := testBatch(mem)
+	defer rec.Release()
+
+	tr := New(map[string]encoder.Transformer{"color": onehot, "size": sizes}, mem)
+	out, err := tr.Transform(rec)
+	if err != nil {
+		t.Fatalf("transform error: %+v", err)
+	}
+	defer out.Release()
+
+	expected := []string{"id", "color=", "color=red", "color=blue", "size"}
+	if int(out.NumCols()) != len(expected) {
+		t.Fatalf("batch had %d columns and not %d", out.NumCols(), len(expected))
+	}
+	for i, name := range expected {
+		if out.ColumnName(i) != name {
+			t.Errorf("column %d was %s and not %s", i, out.ColumnName(i), name)
+		}
+	}
+
+	red := out.Column(2).(*array.Float64)
+	if red.Value(0) != 1 || red.Value(1) != 0 || red.Value(2) != 0 {
+		t.Errorf("red column was %v", red)
+	}
+	null := out.Column(1).(*array.Float64)
+	if null.Value(1) != 1 {
+		t.Errorf("null value was not encoded as the empty string: %v", null)
+	}
+
+	codes := out.Column(4).(*array.Uint64)
+	if codes.Value(0) != 0 || codes.Value(1) != 1 || codes.Value(2) != 0 {
+		t.Errorf("size codes were %v", codes)
+	}
+	if out.Column(0) != rec.Column(0) {
+		t.Error("unencoded column was copied")
+	}
+}
+
+func TestTransformSchema(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	rec := testBatch(mem)
+	defer rec.Release()
+
+	_, err := New(map[string]encoder.Transformer{"shape": encoder.NewOrdinal(false)}, mem).Transform(rec)
+	if err != ErrColumn {
+		t.Errorf("error was %+v and not a column error", err)
+	}
+
+	_, err = New(map[string]encoder.Transformer{"id": encoder.NewOrdinal(false)}, mem).Transform(rec)
+	if err != encoder.ErrDType {
+		t.Errorf("error was %+v and not an element type error", err)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// Package flightserver serves encoders over Arrow Flight. Clients
+// stream record batches to the server with DoExchange and receive
+// the batches back with their string columns encoded, so that
+// jobs that can not embed Go, such as Spark or Polars jobs, can
+// encode at high throughput:
+//
+//	srv := flight.NewServerWithMiddleware(nil)
+//	srv.RegisterFlightService(flightserver.New(transformers))
+//	srv.Init("0.0.0.0:8815")
+//	srv.Serve()
+package flightserver
+
+import (
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/humilityai/encoder/arrow/batch"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server is a Flight service that encodes the
+// record batches exchanged with it.
+type Server struct {
+	flight.BaseFlightServer
+	transformers map[string]*batch.Transformer
+}
+
+// New will create a service that encodes the batches of every
+// exchange with the transformer named by its descriptor: the
+// command of a command descriptor, or the elements of a path
+// descriptor joined by "/". Exchanges may run concurrently, so
+// the encoders of the transformers must be safe for concurrent
+// use, as Ordinal and every read-only encoder are.
+func New(transformers map[string]*batch.Transformer) *Server {
+	return &Server{
+		transformers: transformers,
+	}
+}
+
+// DoExchange will read the record batches sent by the client and
+// write each of them back encoded, in order, as soon as it is read.
+// The stream is answered with a NotFound status if its descriptor
+// does not name a transformer, and an InvalidArgument status if its
+// schema does not have the columns the transformer encodes.
+func (s *Server) DoExchange(stream flight.FlightService_DoExchangeServer) error {
+	reader, err := flight.NewRecordReader(stream)
+	if err != nil {
+		return err
+	}
+	defer reader.Release()
+
+	t, ok := s.transformers[transformerName(reader.LatestFlightDescriptor())]
+	if !ok {
+		return status.Error(codes.NotFound, "no transformer is named by the descriptor")
+	}
+
+	schema, err := t.Schema(reader.Schema())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	writer := flight.NewRecordWriter(stream, ipc.WithSchema(schema))
+	defer writer.Close()
+
+	for reader.Next() {
+		rec, err := t.Transform(reader.RecordBatch())
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		err = writer.Write(rec)
+		rec.Release()
+		if err != nil {
+			return err
+		}
+	}
+
+	return reader.Err()
+}
+
+// transformerName will return the name
+// of the transformer of the descriptor.
+func transformerName(descriptor *flight.FlightDescriptor) string {
+	if descriptor == nil {
+		return ""
+	}
+	if descriptor.Type == flight.DescriptorCMD {
+		return string(descriptor.Cmd)
+	}
+
+	return strings.Join(descriptor.Path, "/")
+}
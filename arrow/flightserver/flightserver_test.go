@@ -0,0 +1,112 @@
+package flightserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/humilityai/encoder"
+	"github.com/humilityai/encoder/arrow/batch"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func testServer(t *testing.T) flight.Client {
+	transformers := map[string]*batch.Transformer{
+		"colors": batch.New(map[string]encoder.Transformer{"color": encoder.NewOrdinal(false)}, nil),
+	}
+
+	srv := flight.NewServerWithMiddleware(nil)
+	srv.RegisterFlightService(New(transformers))
+	err := srv.Init("localhost:0")
+	if err != nil {
+		t.Fatalf("server init error: %+v", err)
+	}
+	go srv.Serve()
+	t.Cleanup(srv.Shutdown)
+
+	client, err := flight.NewClientWithMiddleware(srv.Addr().String(), nil, nil, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("client error: %+v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+func exchange(t *testing.T, client flight.Client, path string, values ...[]string) ([]arrow.RecordBatch, error) {
+	stream, err := client.DoExchange(context.Background())
+	if err != nil {
+		t.Fatalf("exchange error: %+v", err)
+	}
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "color", Type: arrow.BinaryTypes.String}}, nil)
+	writer := flight.NewRecordWriter(stream, ipc.WithSchema(schema))
+	writer.SetFlightDescriptor(&flight.FlightDescriptor{Type: flight.DescriptorPATH, Path: []string{path}})
+	for _, v := range values {
+		b := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+		b.Field(0).(*array.StringBuilder).AppendValues(v, nil)
+		rec := b.NewRecordBatch()
+		err = writer.Write(rec)
+		rec.Release()
+		b.Release()
+		if err != nil {
+			t.Fatalf("write error: %+v", err)
+		}
+	}
+	writer.Close()
+	stream.CloseSend()
+
+	reader, err := flight.NewRecordReader(stream)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Release()
+
+	var batches []arrow.RecordBatch
+	for reader.Next() {
+		rec := reader.RecordBatch()
+		rec.Retain()
+		batches = append(batches, rec)
+	}
+
+	return batches, reader.Err()
+}
+
+func TestDoExchange(t *testing.T) {
+	client := testServer(t)
+
+	batches, err := exchange(t, client, "colors", []string{"red", "blue"}, []string{"blue", "green"})
+	if err != nil {
+		t.Fatalf("exchange error: %+v", err)
+	}
+	if len(batches) != 2 {
+		t.Fatalf("received %d batches and not 2", len(batches))
+	}
+
+	expected := [][]uint64{{0, 1}, {1, 2}}
+	for i, rec := range batches {
+		codes := rec.Column(0).(*array.Uint64)
+		for j, code := range expected[i] {
+			if codes.Value(j) != code {
+				t.Errorf("batch %d was %v and not %v", i, codes, expected[i])
+			}
+		}
+		rec.Release()
+	}
+}
+
+func TestDoExchangeNotFound(t *testing.T) {
+	client := testServer(t)
+
+	_, err := exchange(t, client, "sizes", []string{"small"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("error was %+v and not a not found status", err)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// Package parquetfile encodes columns of Parquet files, so that
+// features can be generated offline from Parquet datasets
+// without Spark.
+package parquetfile
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/humilityai/encoder/arrow/batch"
+)
+
+// batchSize is the number of rows read, encoded
+// and written at a time.
+const batchSize = 64 * 1024
+
+// Encode will read the Parquet file from `r`, encode its columns
+// with the transformer, and write a new Parquet file with the
+// encoded columns to `w`, with the writer properties `props`, or
+// the defaults if it is nil. Rows are read, encoded and written a
+// batch at a time, so files larger than memory can be encoded, and
+// in order, so encoders that assign codes as values are seen
+// assign the same codes as they would encoding the file
+// sequentially. The Arrow schema is stored in the new file, so
+// that codes are read back as unsigned integers. `w` is not closed.
+func Encode(ctx context.Context, r parquet.ReaderAtSeeker, w io.Writer, t *batch.Transformer, props *parquet.WriterProperties) error {
+	pf, err := file.NewParquetReader(r)
+	if err != nil {
+		return err
+	}
+	defer pf.Close()
+
+	fr, err := pqarrow.NewFileReader(pf, pqarrow.ArrowReadProperties{BatchSize: batchSize}, memory.DefaultAllocator)
+	if err != nil {
+		return err
+	}
+
+	rows, err := fr.GetRecordReader(ctx, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer rows.Release()
+
+	schema, err := t.Schema(rows.Schema())
+	if err != nil {
+		return err
+	}
+
+	if props == nil {
+		props = parquet.NewWriterProperties()
+	}
+	// the file writer closes writers that are closers
+	fw, err := pqarrow.NewFileWriter(schema, struct{ io.Writer }{w}, props, pqarrow.NewArrowWriterProperties(pqarrow.WithStoreSchema()))
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		err = ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		rec, err := t.Transform(rows.RecordBatch())
+		if err != nil {
+			return err
+		}
+
+		err = fw.Write(rec)
+		rec.Release()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = rows.Err()
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	return fw.Close()
+}
+
+// EncodeFile will encode the Parquet file at the path `in` as
+// Encode does, writing the new file to the path `out`.
+func EncodeFile(ctx context.Context, in, out string, t *batch.Transformer, props *parquet.WriterProperties) error {
+	r, err := os.Open(in)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	w, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+
+	err = Encode(ctx, r, w, t, props)
+	if err != nil {
+		w.Close()
+		return err
+	}
+
+	return w.Close()
+}
@@ -0,0 +1,132 @@
+package parquetfile
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/humilityai/encoder"
+	"github.com/humilityai/encoder/arrow/batch"
+)
+
+func testFile(t *testing.T) []byte {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "color", Type: arrow.BinaryTypes.String},
+	}, nil)
+
+	b := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer b.Release()
+	b.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2, 3}, nil)
+	b.Field(1).(*array.StringBuilder).AppendValues([]string{"red", "blue", "red"}, nil)
+	rec := b.NewRecordBatch()
+	defer rec.Release()
+
+	var buf bytes.Buffer
+	fw, err := pqarrow.NewFileWriter(schema, &buf, parquet.NewWriterProperties(), pqarrow.DefaultWriterProps())
+	if err != nil {
+		t.Fatalf("file writer error: %+v", err)
+	}
+	err = fw.Write(rec)
+	if err != nil {
+		t.Fatalf("write error: %+v", err)
+	}
+	err = fw.Close()
+	if err != nil {
+		t.Fatalf("close error: %+v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func readTable(t *testing.T, data []byte) arrow.Table {
+	pf, err := file.NewParquetReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("parquet reader error: %+v", err)
+	}
+	defer pf.Close()
+
+	fr, err := pqarrow.NewFileReader(pf, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		t.Fatalf("file reader error: %+v", err)
+	}
+
+	table, err := fr.ReadTable(context.Background())
+	if err != nil {
+		t.Fatalf("read table error: %+v", err)
+	}
+
+	return table
+}
+
+func TestEncode(t *testing.T) {
+	colors := encoder.NewOrdinal(false)
+	tr := batch.New(map[string]encoder.Transformer{"color": colors}, nil)
+
+	var buf bytes.Buffer
+	err := Encode(context.Background(), bytes.NewReader(testFile(t)), &buf, tr, nil)
+	if err != nil {
+		t.Fatalf("encode error: %+v", err)
+	}
+
+	table := readTable(t, buf.Bytes())
+	defer table.Release()
+
+	if table.NumRows() != 3 || table.Schema().Field(1).Type.ID() != arrow.UINT64 {
+		t.Fatalf("encoded schema was %s with %d rows", table.Schema(), table.NumRows())
+	}
+
+	codes := table.Column(1).Data().Chunk(0).(*array.Uint64)
+	if codes.Value(0) != 0 || codes.Value(1) != 1 || codes.Value(2) != 0 {
+		t.Errorf("codes were %v", codes)
+	}
+	if colors.Decode(1) != "blue" {
+		t.Error("encoder did not learn the values of the file")
+	}
+}
+
+func TestEncodeFile(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "in.parquet")
+	out := filepath.Join(dir, "out.parquet")
+
+	tr := batch.New(map[string]encoder.Transformer{"color": encoder.NewOrdinal(false)}, nil)
+	err := EncodeFile(context.Background(), in, out, tr, nil)
+	if err == nil {
+		t.Error("expected an error for a missing file")
+	}
+
+	err = os.WriteFile(in, testFile(t), 0644)
+	if err != nil {
+		t.Fatalf("write file error: %+v", err)
+	}
+	err = EncodeFile(context.Background(), in, out, tr, nil)
+	if err != nil {
+		t.Fatalf("encode file error: %+v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read file error: %+v", err)
+	}
+	table := readTable(t, data)
+	defer table.Release()
+	if table.NumRows() != 3 || table.Schema().Field(1).Type.ID() != arrow.UINT64 {
+		t.Errorf("encoded schema was %s with %d rows", table.Schema(), table.NumRows())
+	}
+
+	shapes := batch.New(map[string]encoder.Transformer{"shape": encoder.NewOrdinal(false)}, nil)
+	var buf bytes.Buffer
+	err = Encode(context.Background(), bytes.NewReader(data), &buf, shapes, nil)
+	if err != batch.ErrColumn {
+		t.Errorf("error was %+v and not a column error", err)
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"encoding/json"
+)
+
+// BaseN will encode string values into the base `n` digits of
+// their ordinal code, most significant digit first, so that a
+// vocabulary of `k` values needs only about log_n(k) dimensions
+// instead of the `k` of a one-hot encoding. Base 2 is a binary
+// encoding. The empty string is ALWAYS the 0 value, encoded as
+// the all-zeros codeword.
+// The dimension increases as new values are encoded, so
+// codewords of a growing encoder should be compared at the
+// same dimension.
+type BaseN struct {
+	ordinal *Ordinal
+	base    int
+	gray    bool
+}
+
+// BaseNOption configures optional behaviour of
+// a BaseN encoder at construction time.
+type BaseNOption func(*BaseN)
+
+// WithGrayCode will encode the digits of every code as its
+// (modular) Gray code, so that the codewords of consecutive
+// codes differ in exactly one digit.
+func WithGrayCode() BaseNOption {
+	return func(e *BaseN) {
+		e.gray = true
+	}
+}
+
+// NewBaseN will create an encoder with digits in the
+// given base. An `ErrBase` error is returned if the base
+// is not between 2 and 256.
+func NewBaseN(base int, opts ...BaseNOption) (*BaseN, error) {
+	if base < 2 || base > 256 {
+		return &BaseN{}, ErrBase
+	}
+
+	e := &BaseN{
+		ordinal: NewOrdinal(true),
+		base:    base,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e, nil
+}
+
+// NewBinary will create a base 2 encoder.
+func NewBinary(opts ...BaseNOption) *BaseN {
+	e, _ := NewBaseN(2, opts...)
+	return e
+}
+
+// Base will return the base of the digits.
+func (e *BaseN) Base() int {
+	return e.base
+}
+
+// Gray will return whether the digits are Gray coded.
+func (e *BaseN) Gray() bool {
+	return e.gray
+}
+
+// Dimension will return the number of digits of the
+// codewords, enough to hold the largest code.
+func (e *BaseN) Dimension() int {
+	largest := uint64(e.ordinal.Length() - 1)
+
+	dimension := 1
+	for largest >= uint64(e.base) {
+		largest /= uint64(e.base)
+		dimension++
+	}
+
+	return dimension
+}
+
+// Encode will return the codeword of the given string,
+// assigning the string the next code if it has none.
+func (e *BaseN) Encode(s string) []uint8 {
+	code := e.ordinal.Encode(s)
+	return e.digits(code, e.Dimension())
+}
+
+// Decode will return the string of the codeword. An `ErrLength`
+// error is returned if the codeword is not `Dimension()` long and
+// an `ErrInvalidCodeword` error if it has a digit outside the base
+// or is not the codeword of an encoded value.
+func (e *BaseN) Decode(code []uint8) (string, error) {
+	if len(code) != e.Dimension() {
+		return "", ErrLength
+	}
+
+	var value, previous uint64
+	for _, digit := range code {
+		if int(digit) >= e.base {
+			return "", ErrInvalidCodeword
+		}
+
+		d := uint64(digit)
+		if e.gray {
+			d = (d + previous) % uint64(e.base)
+			previous = d
+		}
+		value = value*uint64(e.base) + d
+	}
+
+	s, err := e.ordinal.DecodeChecked(value)
+	if err != nil {
+		return "", ErrInvalidCodeword
+	}
+
+	return s, nil
+}
+
+// Contains will return whether or not a string
+// has been assigned a code or not.
+func (e *BaseN) Contains(s string) bool {
+	return e.ordinal.Contains(s)
+}
+
+// Transform will encode the string, adding it to the
+// encoder if needed, and return its digits as features.
+func (e *BaseN) Transform(s string) []float64 {
+	code := e.Encode(s)
+
+	features := make([]float64, len(code), len(code))
+	for i, v := range code {
+		features[i] = float64(v)
+	}
+
+	return features
+}
+
+// digits will return the digits of the code, most
+// significant first, Gray coded if the encoder is.
+func (e *BaseN) digits(code uint64, dimension int) []uint8 {
+	digits := make([]uint8, dimension, dimension)
+	for i := dimension - 1; i >= 0; i-- {
+		digits[i] = uint8(code % uint64(e.base))
+		code /= uint64(e.base)
+	}
+
+	if e.gray {
+		// every digit becomes its difference from the one
+		// before it, so a carry changes a single digit
+		for i := dimension - 1; i > 0; i-- {
+			digits[i] = uint8((int(digits[i]) - int(digits[i-1]) + e.base) % e.base)
+		}
+	}
+
+	return digits
+}
+
+// baseNJSON is the JSON form of a BaseN encoder.
+type baseNJSON struct {
+	Base   int      `json:"base"`
+	Gray   bool     `json:"gray,omitempty"`
+	Values *Ordinal `json:"values"`
+}
+
+// MarshalJSON will encode the base, whether the
+// digits are Gray coded, and the values by code.
+func (e *BaseN) MarshalJSON() ([]byte, error) {
+	return json.Marshal(baseNJSON{
+		Base:   e.base,
+		Gray:   e.gray,
+		Values: e.ordinal,
+	})
+}
+
+// UnmarshalJSON will return an `ErrBase` error
+// if the base is not between 2 and 256.
+func (e *BaseN) UnmarshalJSON(data []byte) error {
+	o := baseNJSON{Values: NewOrdinal(false)}
+	err := json.Unmarshal(data, &o)
+	if err != nil {
+		return err
+	}
+	if o.Base < 2 || o.Base > 256 {
+		return ErrBase
+	}
+
+	e.ordinal, e.base, e.gray = o.Values, o.Base, o.Gray
+	return nil
+}
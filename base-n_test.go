@@ -0,0 +1,86 @@
+package encoder
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBaseN(t *testing.T) {
+	e := NewBinary()
+	for _, v := range []string{"a", "b", "c", "d", "e"} {
+		e.Encode(v)
+	}
+
+	code := e.Encode("c")
+	if len(code) != 3 || code[0] != 0 || code[1] != 1 || code[2] != 1 {
+		t.Errorf("codeword was %v and not [0 1 1]", code)
+	}
+	if v, err := e.Decode(code); err != nil || v != "c" {
+		t.Errorf("codeword decoded to %s: %+v", v, err)
+	}
+	if _, err := e.Decode([]uint8{1, 1, 1}); err != ErrInvalidCodeword {
+		t.Error("expected invalid codeword error")
+	}
+	if _, err := e.Decode([]uint8{1}); err != ErrLength {
+		t.Error("expected length error")
+	}
+
+	ternary, err := NewBaseN(3)
+	if err != nil {
+		t.Fatalf("create error: %+v", err)
+	}
+	ternary.Encode("a")
+	if code := ternary.Encode("b"); len(code) != 1 || code[0] != 2 {
+		t.Errorf("codeword was %v and not [2]", code)
+	}
+	if _, err := NewBaseN(1); err != ErrBase {
+		t.Error("expected base error")
+	}
+}
+
+func TestBaseNGrayCode(t *testing.T) {
+	for _, base := range []int{2, 3} {
+		e, _ := NewBaseN(base, WithGrayCode())
+		values := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+		for _, v := range values {
+			e.Encode(v)
+		}
+
+		previous := e.Encode("")
+		for _, v := range values {
+			code := e.Encode(v)
+			changed := 0
+			for i := range code {
+				if code[i] != previous[i] {
+					changed++
+				}
+			}
+			if changed != 1 {
+				t.Errorf("base %d codewords %v and %v differ in %d digits", base, previous, code, changed)
+			}
+			if decoded, err := e.Decode(code); err != nil || decoded != v {
+				t.Errorf("base %d codeword %v decoded to %s: %+v", base, code, decoded, err)
+			}
+			previous = code
+		}
+	}
+
+	// the binary reflected Gray code of 5 is 111
+	e := NewBinary(WithGrayCode())
+	e.ordinal.EncodeSlice([]string{"a", "b", "c", "d", "e"})
+	if code := e.Encode("e"); code[0] != 1 || code[1] != 1 || code[2] != 1 {
+		t.Errorf("codeword was %v and not [1 1 1]", code)
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	var loaded BaseN
+	if err := json.Unmarshal(b, &loaded); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	if v, err := loaded.Decode([]uint8{1, 1, 1}); err != nil || v != "e" || !loaded.Gray() {
+		t.Errorf("loaded encoder decoded %s: %+v", v, err)
+	}
+}
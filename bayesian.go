@@ -0,0 +1,201 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+// BayesianClassification is a one way encoder.
+// You cannot decode BayesianClassification values
+// as some values may be encoded with the same
+// numerical code.
+// BayesianClassification is a target-based encoder for
+// binary targets that encodes every category with the
+// posterior mean of its positive rate under a Beta prior
+// centred on the positive rate of the whole target.
+type BayesianClassification struct {
+	encoder map[string]float64
+	prior   float64
+	missing MissingPolicy
+}
+
+// BayesianRegression is a one way encoder.
+// You cannot decode BayesianRegression values
+// as some values may be encoded with the same
+// numerical code.
+// BayesianRegression is a target-based encoder for
+// continuous targets that encodes every category with the
+// posterior mean of its target mean under a
+// Normal-Inverse-Gamma prior centred on the mean and
+// variance of the whole target.
+type BayesianRegression struct {
+	encoder   map[string]float64
+	variances map[string]float64
+	prior     float64
+	variance  float64
+	missing   MissingPolicy
+}
+
+// NewBayesianClassification will create a BayesianClassification
+// encoder. The `strength` of the prior is its number of
+// pseudo-observations: the Beta prior has parameters
+// `strength * p` and `strength * (1 - p)`, where `p` is the
+// positive rate of the whole target. An `ErrPriorStrength`
+// error is returned if the strength is negative.
+func NewBayesianClassification(values []string, target []bool, strength float64, opts ...TargetOption) (*BayesianClassification, error) {
+	if len(target) != len(values) {
+		return &BayesianClassification{}, ErrTargetLength
+	}
+	if strength < 0 {
+		return &BayesianClassification{}, ErrPriorStrength
+	}
+	o := newTargetOptions(opts)
+	values = o.categories(values)
+
+	counts, positives := weightBinaryTarget(values, target, nil)
+
+	var totalPositives float64
+	for _, n := range positives {
+		totalPositives += n
+	}
+
+	var prior float64
+	if len(target) > 0 {
+		prior = totalPositives / float64(len(target))
+	}
+
+	encoder := make(map[string]float64)
+	for k, n := range counts {
+		encoder[k] = (strength*prior + positives[k]) / (strength + n)
+	}
+
+	return &BayesianClassification{
+		encoder: encoder,
+		prior:   prior,
+		missing: o.missing,
+	}, nil
+}
+
+// NewBayesianRegression will create a BayesianRegression encoder.
+// The `strength` of the prior is its number of pseudo-observations
+// of the mean (κ₀), and twice the number of pseudo-observations of
+// the variance (α₀ = 1 + strength/2, with β₀ chosen so the prior
+// mean of the variance is that of the whole target). An
+// `ErrPriorStrength` error is returned if the strength is negative.
+func NewBayesianRegression(values []string, target []float64, strength float64, opts ...TargetOption) (*BayesianRegression, error) {
+	if len(target) != len(values) {
+		return &BayesianRegression{}, ErrTargetLength
+	}
+	if strength < 0 {
+		return &BayesianRegression{}, ErrPriorStrength
+	}
+	o := newTargetOptions(opts)
+	values = o.categories(values)
+
+	mean, variance := meanVariance(target)
+	alpha0 := 1 + strength/2
+	beta0 := variance * strength / 2
+
+	groups := make(map[string][]float64)
+	for i := 0; i < len(values); i++ {
+		groups[values[i]] = append(groups[values[i]], target[i])
+	}
+
+	encoder := make(map[string]float64)
+	variances := make(map[string]float64)
+	for k, group := range groups {
+		n := float64(len(group))
+		groupMean, groupVariance := meanVariance(group)
+
+		kappa := strength + n
+		encoder[k] = (strength*mean + n*groupMean) / kappa
+
+		alpha := alpha0 + n/2
+		beta := beta0 + n*groupVariance/2 + strength*n*(groupMean-mean)*(groupMean-mean)/(2*kappa)
+		variances[k] = beta / (alpha - 1)
+	}
+
+	return &BayesianRegression{
+		encoder:   encoder,
+		variances: variances,
+		prior:     mean,
+		variance:  variance,
+		missing:   o.missing,
+	}, nil
+}
+
+// Get will retrieve the code for the given categorical value.
+// Unseen values receive the prior mean.
+func (e *BayesianClassification) Get(s string) (float64, bool) {
+	v, ok := e.encoder[missingKey(e.missing, s)]
+	if !ok {
+		return e.prior, false
+	}
+
+	return v, true
+}
+
+// Transform will return the code of the string as a
+// single feature. Unseen strings receive the prior mean.
+func (e *BayesianClassification) Transform(s string) []float64 {
+	v, _ := e.Get(s)
+	return []float64{v}
+}
+
+// Get will retrieve the code for the given categorical value.
+// Unseen values receive the prior mean.
+func (e *BayesianRegression) Get(s string) (float64, bool) {
+	v, ok := e.encoder[missingKey(e.missing, s)]
+	if !ok {
+		return e.prior, false
+	}
+
+	return v, true
+}
+
+// Variance will retrieve the posterior mean of the target
+// variance of the given categorical value. Unseen values
+// receive the prior mean.
+func (e *BayesianRegression) Variance(s string) (float64, bool) {
+	v, ok := e.variances[missingKey(e.missing, s)]
+	if !ok {
+		return e.variance, false
+	}
+
+	return v, true
+}
+
+// Transform will return the code of the string as a
+// single feature. Unseen strings receive the prior mean.
+func (e *BayesianRegression) Transform(s string) []float64 {
+	v, _ := e.Get(s)
+	return []float64{v}
+}
+
+// meanVariance will return the mean and the
+// (population) variance of the values.
+func meanVariance(values []float64) (mean, variance float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	variance /= float64(len(values))
+
+	return
+}
@@ -0,0 +1,53 @@
+package encoder
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBayesianClassification(t *testing.T) {
+	values := []string{"a", "a", "b", "b", "b", "b"}
+	target := []bool{true, true, false, false, true, false}
+
+	encoder, err := NewBayesianClassification(values, target, 3)
+	if err != nil {
+		t.Fatalf("encoder error: %+v", err)
+	}
+	// prior rate 1/2 with 3 pseudo-observations
+	if v, ok := encoder.Get("a"); !ok || math.Abs(v-3.5/5) > 1e-9 {
+		t.Errorf("posterior mean was %f and not 0.7", v)
+	}
+	if v, ok := encoder.Get("c"); ok || v != 0.5 {
+		t.Errorf("unseen value should receive the prior, got %f", v)
+	}
+
+	raw, _ := NewBayesianClassification(values, target, 0)
+	if v, _ := raw.Get("b"); v != 0.25 {
+		t.Errorf("prior without strength should give the rate, got %f", v)
+	}
+
+	if _, err := NewBayesianClassification(values, target, -1); err != ErrPriorStrength {
+		t.Error("expected prior strength error")
+	}
+}
+
+func TestBayesianRegression(t *testing.T) {
+	values := []string{"a", "a", "b", "b"}
+	target := []float64{1, 3, 5, 7}
+
+	encoder, err := NewBayesianRegression(values, target, 2)
+	if err != nil {
+		t.Fatalf("encoder error: %+v", err)
+	}
+	// (2 * 4 + 2 * 2) / 4
+	if v, ok := encoder.Get("a"); !ok || math.Abs(v-3) > 1e-9 {
+		t.Errorf("posterior mean was %f and not 3", v)
+	}
+	if v, ok := encoder.Get("c"); ok || v != 4 {
+		t.Errorf("unseen value should receive the prior mean, got %f", v)
+	}
+	// beta = 5*2/2 + 2*1/2 + 2*2*4/8 = 8, alpha = 3
+	if v, _ := encoder.Variance("a"); math.Abs(v-4) > 1e-9 {
+		t.Errorf("posterior variance was %f and not 4", v)
+	}
+}
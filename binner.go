@@ -0,0 +1,215 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+)
+
+// Binner discretizes numeric values into bins between fitted
+// edges. Bin `i` holds the values in [edges[i], edges[i+1]),
+// except that the last bin also holds the last edge; values
+// outside the edges fall into the first or last bin. Bins can
+// be mapped back to their value range with DecodeRange, or to
+// their midpoint with Inverse.
+type Binner struct {
+	edges []float64
+}
+
+// NewBinner will create a Binner with the given edges, which
+// must number at least two and be finite and strictly
+// increasing; otherwise an `ErrBins` error is returned.
+func NewBinner(edges []float64) (*Binner, error) {
+	if !validEdges(edges) {
+		return &Binner{}, ErrBins
+	}
+
+	return &Binner{
+		edges: append([]float64{}, edges...),
+	}, nil
+}
+
+// NewUniformBinner will fit `bins` bins of equal width between
+// the minimum and maximum of the values. An `ErrNoData` error
+// is returned if there are no values, and an `ErrBins` error if
+// `bins` is not positive or the values are not finite.
+func NewUniformBinner(values []float64, bins int) (*Binner, error) {
+	if len(values) == 0 {
+		return &Binner{}, ErrNoData
+	}
+	if bins < 1 {
+		return &Binner{}, ErrBins
+	}
+
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		min, max = math.Min(min, v), math.Max(max, v)
+	}
+	if min == max {
+		// a single bin around a constant column
+		return NewBinner([]float64{min - 0.5, max + 0.5})
+	}
+
+	edges := make([]float64, bins+1, bins+1)
+	for i := range edges {
+		edges[i] = min + (max-min)*float64(i)/float64(bins)
+	}
+	edges[bins] = max
+
+	return NewBinner(edges)
+}
+
+// NewQuantileBinner will fit at most `bins` bins holding roughly
+// the same number of values, with edges at evenly spaced quantiles
+// of the values. Quantiles that coincide, as they do for values
+// repeated many times, are merged, so fewer bins may be fitted.
+// Errors are returned as for NewUniformBinner.
+func NewQuantileBinner(values []float64, bins int) (*Binner, error) {
+	if len(values) == 0 {
+		return &Binner{}, ErrNoData
+	}
+	if bins < 1 {
+		return &Binner{}, ErrBins
+	}
+
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	if sorted[0] == sorted[len(sorted)-1] {
+		return NewBinner([]float64{sorted[0] - 0.5, sorted[0] + 0.5})
+	}
+
+	edges := make([]float64, 0, bins+1)
+	for i := 0; i <= bins; i++ {
+		edge := quantile(sorted, float64(i)/float64(bins))
+		if len(edges) == 0 || edge > edges[len(edges)-1] {
+			edges = append(edges, edge)
+		}
+	}
+
+	return NewBinner(edges)
+}
+
+// Encode will return the bin of the value, or -1 for NaN.
+func (b *Binner) Encode(x float64) int {
+	if math.IsNaN(x) {
+		return -1
+	}
+
+	// the index of the first edge above x
+	i := sort.Search(len(b.edges), func(i int) bool {
+		return b.edges[i] > x
+	})
+
+	bin := i - 1
+	if bin < 0 {
+		return 0
+	}
+	if bin > b.Bins()-1 {
+		return b.Bins() - 1
+	}
+
+	return bin
+}
+
+// EncodeSlice will return the bin of every value.
+func (b *Binner) EncodeSlice(values []float64) []int {
+	bins := make([]int, len(values), len(values))
+	for i, v := range values {
+		bins[i] = b.Encode(v)
+	}
+
+	return bins
+}
+
+// DecodeRange will return the lower and upper edges of the
+// bin, or NaN for both if the bin does not exist.
+func (b *Binner) DecodeRange(bin int) (lo, hi float64) {
+	if bin < 0 || bin >= b.Bins() {
+		return math.NaN(), math.NaN()
+	}
+
+	return b.edges[bin], b.edges[bin+1]
+}
+
+// Inverse will return the midpoint of the bin,
+// or NaN if the bin does not exist.
+func (b *Binner) Inverse(bin int) float64 {
+	lo, hi := b.DecodeRange(bin)
+	return lo + (hi-lo)/2
+}
+
+// InverseSlice will return the midpoint of every bin.
+func (b *Binner) InverseSlice(bins []int) []float64 {
+	values := make([]float64, len(bins), len(bins))
+	for i, bin := range bins {
+		values[i] = b.Inverse(bin)
+	}
+
+	return values
+}
+
+// Bins will return the number of bins.
+func (b *Binner) Bins() int {
+	return len(b.edges) - 1
+}
+
+// Edges will return a copy of the bin edges.
+func (b *Binner) Edges() []float64 {
+	return append([]float64{}, b.edges...)
+}
+
+// MarshalJSON will encode the bin edges.
+func (b *Binner) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Edges []float64 `json:"edges"`
+	}{
+		Edges: b.edges,
+	})
+}
+
+// UnmarshalJSON will return an `ErrBins` error
+// if the edges are not valid. See NewBinner.
+func (b *Binner) UnmarshalJSON(data []byte) error {
+	var fitted struct {
+		Edges []float64 `json:"edges"`
+	}
+	err := json.Unmarshal(data, &fitted)
+	if err != nil {
+		return err
+	}
+	if !validEdges(fitted.Edges) {
+		return ErrBins
+	}
+
+	b.edges = fitted.Edges
+	return nil
+}
+
+func validEdges(edges []float64) bool {
+	if len(edges) < 2 {
+		return false
+	}
+	for i, edge := range edges {
+		if math.IsNaN(edge) || math.IsInf(edge, 0) {
+			return false
+		}
+		if i > 0 && edge <= edges[i-1] {
+			return false
+		}
+	}
+
+	return true
+}
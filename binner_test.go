@@ -0,0 +1,67 @@
+package encoder
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestBinner(t *testing.T) {
+	b, err := NewUniformBinner([]float64{0, 2, 5, 10}, 4)
+	if err != nil {
+		t.Fatalf("fit error: %+v", err)
+	}
+
+	bins := b.EncodeSlice([]float64{-3, 0, 2.5, 9.9, 10, 42, math.NaN()})
+	expected := []int{0, 0, 1, 3, 3, 3, -1}
+	for i := range expected {
+		if bins[i] != expected[i] {
+			t.Errorf("bins were %v and not %v", bins, expected)
+			break
+		}
+	}
+
+	if lo, hi := b.DecodeRange(1); lo != 2.5 || hi != 5 {
+		t.Errorf("range of bin 1 was [%f, %f) and not [2.5, 5)", lo, hi)
+	}
+	if lo, _ := b.DecodeRange(4); !math.IsNaN(lo) {
+		t.Error("expected NaN range for a bin that does not exist")
+	}
+	if mid := b.InverseSlice([]int{0, 3}); mid[0] != 1.25 || mid[1] != 8.75 {
+		t.Errorf("midpoints were %v and not [1.25 8.75]", mid)
+	}
+
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	var loaded Binner
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	if loaded.Bins() != 4 || loaded.Encode(6) != 2 {
+		t.Error("edges did not round trip")
+	}
+
+	if _, err := NewBinner([]float64{0, 0, 1}); err != ErrBins {
+		t.Error("expected bins error for repeated edges")
+	}
+	if _, err := NewUniformBinner(nil, 3); err != ErrNoData {
+		t.Error("expected no data error")
+	}
+}
+
+func TestQuantileBinner(t *testing.T) {
+	b, err := NewQuantileBinner([]float64{1, 1, 1, 1, 2, 3, 4, 100}, 4)
+	if err != nil {
+		t.Fatalf("fit error: %+v", err)
+	}
+
+	// the repeated 1s merge the first quantiles
+	if b.Bins() != 3 {
+		t.Errorf("fitted %d bins with edges %v and not 3", b.Bins(), b.Edges())
+	}
+	if b.Encode(100) != b.Bins()-1 || b.Encode(1) != 0 {
+		t.Errorf("unexpected bins for the extremes of %v", b.Edges())
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"bytes"
+	"io/ioutil"
+	"sync"
+	"unsafe"
+)
+
+// defaultBlockSize is the number of values
+// in every block of a BlockDecoder.
+const defaultBlockSize = 256
+
+// BlockDecoder is a read-only decoder that holds values in
+// compressed blocks, trading a little decode latency for much
+// less memory on vocabularies that are rarely decoded. Decoding
+// a value decompresses its block; the last block decompressed
+// is kept so decoding nearby codes is fast.
+type BlockDecoder struct {
+	blocks    [][]byte
+	blockSize int
+	length    int
+	codec     Codec
+
+	mu     sync.Mutex
+	cached int
+	values []string
+}
+
+// NewBlockDecoder will compress the values, indexed by code,
+// into blocks of `blockSize` values (256 if it is not positive)
+// with the codec (Gzip if it is nil). Codecs such as snappy or
+// zstd can be used by implementing Codec.
+func NewBlockDecoder(values []string, blockSize int, codec Codec) (*BlockDecoder, error) {
+	if blockSize < 1 {
+		blockSize = defaultBlockSize
+	}
+	if codec == nil {
+		codec = Gzip
+	}
+
+	d := &BlockDecoder{
+		blockSize: blockSize,
+		length:    len(values),
+		codec:     codec,
+		cached:    -1,
+	}
+	for start := 0; start < len(values); start += blockSize {
+		end := start + blockSize
+		if end > len(values) {
+			end = len(values)
+		}
+
+		var block bytes.Buffer
+		for _, v := range values[start:end] {
+			writeString(&block, v)
+		}
+
+		var compressed bytes.Buffer
+		err := writeCompressed(&compressed, codec, block.Bytes())
+		if err != nil {
+			return &BlockDecoder{}, err
+		}
+		d.blocks = append(d.blocks, compressed.Bytes())
+	}
+
+	return d, nil
+}
+
+// BlockDecoder will return a BlockDecoder of the
+// values of the encoder. See NewBlockDecoder.
+func (e *Ordinal) BlockDecoder(blockSize int, codec Codec) (*BlockDecoder, error) {
+	return NewBlockDecoder(e.List(), blockSize, codec)
+}
+
+// Decode will return an empty string if supplied integer
+// argument is not a valid code or its block cannot be
+// decompressed.
+func (d *BlockDecoder) Decode(i uint64) string {
+	value, _ := d.DecodeChecked(i)
+	return value
+}
+
+// DecodeChecked will return the string for the given code,
+// or an `ErrBounds` error if the code is not a valid code.
+func (d *BlockDecoder) DecodeChecked(i uint64) (string, error) {
+	if i >= uint64(d.length) {
+		return "", ErrBounds
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	block := int(i) / d.blockSize
+	if block != d.cached {
+		values, err := d.decompress(block)
+		if err != nil {
+			return "", err
+		}
+		d.cached, d.values = block, values
+	}
+
+	return d.values[int(i)%d.blockSize], nil
+}
+
+func (d *BlockDecoder) decompress(block int) ([]string, error) {
+	cr, err := d.codec.NewReader(bytes.NewReader(d.blocks[block]))
+	if err != nil {
+		return nil, err
+	}
+	defer cr.Close()
+
+	data, err := ioutil.ReadAll(cr)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]string, 0, d.blockSize)
+	r := &compactReader{data: data}
+	for r.pos < len(data) && r.err == nil {
+		values = append(values, string(r.bytes()))
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return values, nil
+}
+
+// Length will return the number of values.
+func (d *BlockDecoder) Length() int {
+	return d.length
+}
+
+// SizeBytes will return an estimate of the memory held by
+// the decoder in bytes, including the last block decompressed.
+func (d *BlockDecoder) SizeBytes() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	size := cap(d.blocks) * int(unsafe.Sizeof(d.blocks[:0]))
+	for _, block := range d.blocks {
+		size += cap(block)
+	}
+	for _, v := range d.values {
+		size += stringHeaderBytes + len(v)
+	}
+
+	return size
+}
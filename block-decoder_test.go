@@ -0,0 +1,32 @@
+package encoder
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestBlockDecoder(t *testing.T) {
+	e := NewOrdinal(true)
+	for i := 0; i < 5000; i++ {
+		e.Encode("https://example.com/products/" + strconv.Itoa(i))
+	}
+
+	d, err := e.BlockDecoder(128, nil)
+	if err != nil {
+		t.Fatalf("create error: %+v", err)
+	}
+	if d.Length() != e.Length() {
+		t.Errorf("length was %d and not %d", d.Length(), e.Length())
+	}
+	for _, code := range []uint64{0, 1, 127, 128, 4999, 5000, 3} {
+		if got := d.Decode(code); got != e.Decode(code) {
+			t.Errorf("code %d decoded to %q and not %q", code, got, e.Decode(code))
+		}
+	}
+	if _, err := d.DecodeChecked(5001); err != ErrBounds {
+		t.Error("expected bounds error")
+	}
+	if d.SizeBytes()*3 > e.decoder.sizeBytes() {
+		t.Errorf("compressed decoder of %d bytes is not much smaller than %d", d.SizeBytes(), e.decoder.sizeBytes())
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"hash/fnv"
+	"math"
+)
+
+// BloomFilter is a probabilistic set of strings.
+// Contains will never return false for a value that
+// was added, but may return true for a value that
+// was not added (a false positive).
+type BloomFilter struct {
+	bits   []uint64
+	size   uint64
+	hashes uint64
+}
+
+// NewBloomFilter will create a bloom filter sized
+// to hold `n` values with the given false positive rate.
+func NewBloomFilter(n int, fpRate float64) (*BloomFilter, error) {
+	if fpRate <= 0 || fpRate >= 1 {
+		return &BloomFilter{}, ErrFalsePositiveRate
+	}
+
+	if n < 1 {
+		n = 1
+	}
+
+	size := uint64(math.Ceil(-float64(n) * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	hashes := uint64(math.Max(1, math.Round(float64(size)/float64(n)*math.Ln2)))
+
+	return &BloomFilter{
+		bits:   make([]uint64, (size+63)/64),
+		size:   size,
+		hashes: hashes,
+	}, nil
+}
+
+// BloomFilter will return a bloom filter containing
+// every value in the encoders vocabulary and every
+// alias of those values.
+func (e *Ordinal) BloomFilter(fpRate float64) (*BloomFilter, error) {
+	e.RLock()
+	defer e.RUnlock()
+
+	f, err := NewBloomFilter(e.decoder.len()+len(e.aliases), fpRate)
+	if err != nil {
+		return f, err
+	}
+
+	for code := 0; code < e.decoder.len(); code++ {
+		f.Add(e.decoder.get(code))
+	}
+	for alias := range e.aliases {
+		f.Add(alias)
+	}
+
+	return f, nil
+}
+
+// Add will add the string to the bloom filter.
+func (f *BloomFilter) Add(s string) {
+	h1, h2 := bloomHashes(s)
+	for i := uint64(0); i < f.hashes; i++ {
+		bit := (h1 + i*h2) % f.size
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// Contains will return whether or not the string
+// may have been added to the bloom filter.
+func (f *BloomFilter) Contains(s string) bool {
+	if f.size == 0 {
+		return false
+	}
+
+	h1, h2 := bloomHashes(s)
+	for i := uint64(0); i < f.hashes; i++ {
+		bit := (h1 + i*h2) % f.size
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Size will return the number of bits in the bloom filter.
+func (f *BloomFilter) Size() uint64 {
+	return f.size
+}
+
+// Hashes will return the number of hash functions
+// used for every value.
+func (f *BloomFilter) Hashes() uint64 {
+	return f.hashes
+}
+
+type bloomFilterCopy struct {
+	Bits   []uint64 `json:"bits"`
+	Size   uint64   `json:"size"`
+	Hashes uint64   `json:"hashes"`
+}
+
+// MarshalJSON ...
+func (f *BloomFilter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(bloomFilterCopy{
+		Bits:   f.bits,
+		Size:   f.size,
+		Hashes: f.hashes,
+	})
+}
+
+// UnmarshalJSON ...
+func (f *BloomFilter) UnmarshalJSON(data []byte) error {
+	var fCopy bloomFilterCopy
+	err := json.Unmarshal(data, &fCopy)
+	if err != nil {
+		return err
+	}
+
+	return f.set(fCopy)
+}
+
+// GobEncode ...
+func (f *BloomFilter) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+
+	enc := gob.NewEncoder(&buf)
+	err := enc.Encode(bloomFilterCopy{
+		Bits:   f.bits,
+		Size:   f.size,
+		Hashes: f.hashes,
+	})
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// GobDecode ...
+func (f *BloomFilter) GobDecode(data []byte) error {
+	var fCopy bloomFilterCopy
+
+	dec := gob.NewDecoder(bytes.NewReader(data))
+	err := dec.Decode(&fCopy)
+	if err != nil {
+		return err
+	}
+
+	return f.set(fCopy)
+}
+
+func (f *BloomFilter) set(fCopy bloomFilterCopy) error {
+	if uint64(len(fCopy.Bits)) != (fCopy.Size+63)/64 || fCopy.Hashes == 0 {
+		return ErrLength
+	}
+
+	f.bits = fCopy.Bits
+	f.size = fCopy.Size
+	f.hashes = fCopy.Hashes
+
+	return nil
+}
+
+// bloomHashes will return the two base hashes used
+// for double hashing. The second hash is forced odd
+// so that it is never 0.
+func bloomHashes(s string) (uint64, uint64) {
+	h2 := fnv.New64()
+	h2.Write([]byte(s))
+
+	return hashString(s), h2.Sum64() | 1
+}
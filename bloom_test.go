@@ -0,0 +1,40 @@
+package encoder
+
+import (
+	"testing"
+)
+
+func TestOrdinalBloomFilter(t *testing.T) {
+	encoder := NewOrdinal(true)
+	values := []string{"red", "green", "blue"}
+	for _, v := range values {
+		encoder.Encode(v)
+	}
+
+	f, err := encoder.BloomFilter(0.01)
+	if err != nil {
+		t.Fatalf("bloom filter error: %+v", err)
+	}
+
+	data, err := f.MarshalJSON()
+	if err != nil {
+		t.Errorf("json marshal error: %+v", err)
+	}
+
+	newFilter := &BloomFilter{}
+	err = newFilter.UnmarshalJSON(data)
+	if err != nil {
+		t.Errorf("json unmarshal error: %+v", err)
+	}
+
+	for _, v := range append(values, "") {
+		if !newFilter.Contains(v) {
+			t.Errorf("bloom filter did not contain %q", v)
+		}
+	}
+
+	_, err = encoder.BloomFilter(1)
+	if err != ErrFalsePositiveRate {
+		t.Error("expected false positive rate error")
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"container/list"
+	"sync"
+)
+
+// BoundedOrdinal will encode string values into
+// a unique integer value, like Ordinal, but holds at
+// most a fixed number of values. When it is full, the
+// least recently encoded value is evicted and its code
+// is reassigned to the new value. Reassignments are
+// recorded in an eviction log, holding the most recent
+// `capacity` of them, so that consumers of the codes
+// know which codes were recycled.
+type BoundedOrdinal struct {
+	capacity  int
+	encoder   map[string]*list.Element
+	decoder   []string
+	recency   *list.List
+	evictions []Eviction
+	next      int
+	onEvict   EvictionFunc
+	*sync.Mutex
+}
+
+// EvictionFunc is called with every code
+// reassignment a BoundedOrdinal makes.
+type EvictionFunc func(eviction Eviction)
+
+// Eviction records that a code was reassigned
+// from one value to another.
+type Eviction struct {
+	Code     uint64 `json:"code"`
+	Evicted  string `json:"evicted"`
+	Replaced string `json:"replaced"`
+}
+
+type boundedEntry struct {
+	value string
+	code  uint64
+}
+
+// NewBoundedOrdinal will create a BoundedOrdinal encoder
+// holding at most `capacity` values.
+func NewBoundedOrdinal(capacity int) (*BoundedOrdinal, error) {
+	if capacity < 1 {
+		return &BoundedOrdinal{}, ErrCapacity
+	}
+
+	return &BoundedOrdinal{
+		capacity:  capacity,
+		encoder:   make(map[string]*list.Element),
+		decoder:   make([]string, 0),
+		recency:   list.New(),
+		evictions: make([]Eviction, 0),
+		Mutex:     &sync.Mutex{},
+	}, nil
+}
+
+// Encode will return the code of the given string, assigning
+// it a code if it has none. If the encoder is full the least
+// recently encoded value is evicted and its code reassigned.
+func (e *BoundedOrdinal) Encode(s string) uint64 {
+	e.Lock()
+	defer e.Unlock()
+
+	if element, ok := e.encoder[s]; ok {
+		e.recency.MoveToFront(element)
+		return element.Value.(*boundedEntry).code
+	}
+
+	if len(e.decoder) < e.capacity {
+		code := uint64(len(e.decoder))
+		e.decoder = append(e.decoder, s)
+		e.encoder[s] = e.recency.PushFront(&boundedEntry{value: s, code: code})
+		return code
+	}
+
+	element := e.recency.Back()
+	entry := element.Value.(*boundedEntry)
+	e.logEviction(Eviction{
+		Code:     entry.code,
+		Evicted:  entry.value,
+		Replaced: s,
+	})
+
+	delete(e.encoder, entry.value)
+	entry.value = s
+	e.decoder[entry.code] = s
+	e.encoder[s] = element
+	e.recency.MoveToFront(element)
+
+	return entry.code
+}
+
+// Decode will return an empty string if supplied integer
+// argument is not a valid code.
+func (e *BoundedOrdinal) Decode(i uint64) string {
+	e.Lock()
+	defer e.Unlock()
+
+	if i >= uint64(len(e.decoder)) {
+		return ""
+	}
+
+	return e.decoder[i]
+}
+
+// Contains will return whether or not a string
+// currently has a code. It does not count as a
+// use of the value for eviction.
+func (e *BoundedOrdinal) Contains(s string) bool {
+	e.Lock()
+	defer e.Unlock()
+
+	_, ok := e.encoder[s]
+	return ok
+}
+
+// Length will return the number of values held.
+func (e *BoundedOrdinal) Length() int {
+	e.Lock()
+	defer e.Unlock()
+
+	return len(e.decoder)
+}
+
+// Capacity will return the maximum number of
+// values the encoder holds.
+func (e *BoundedOrdinal) Capacity() int {
+	return e.capacity
+}
+
+// Evictions will return the code reassignments since the
+// last call to Evictions, in order, and clear the log. The
+// log holds at most `Capacity()` reassignments, dropping the
+// oldest; use OnEviction to observe every reassignment.
+func (e *BoundedOrdinal) Evictions() []Eviction {
+	e.Lock()
+	defer e.Unlock()
+
+	evictions := make([]Eviction, 0, len(e.evictions))
+	evictions = append(evictions, e.evictions[e.next:]...)
+	evictions = append(evictions, e.evictions[:e.next]...)
+
+	e.evictions = make([]Eviction, 0)
+	e.next = 0
+	return evictions
+}
+
+// OnEviction will register a callback that is called with
+// every code reassignment, replacing any previously registered
+// callback. The callback is called while the encoder is locked,
+// so it must not call the encoder. A nil callback removes the
+// callback.
+func (e *BoundedOrdinal) OnEviction(f EvictionFunc) {
+	e.Lock()
+	defer e.Unlock()
+
+	e.onEvict = f
+}
+
+// logEviction will record the reassignment in the eviction
+// log, overwriting the oldest record once the log is full.
+func (e *BoundedOrdinal) logEviction(eviction Eviction) {
+	if e.onEvict != nil {
+		e.onEvict(eviction)
+	}
+
+	if len(e.evictions) < e.capacity {
+		e.evictions = append(e.evictions, eviction)
+		return
+	}
+
+	e.evictions[e.next] = eviction
+	e.next = (e.next + 1) % e.capacity
+}
@@ -0,0 +1,61 @@
+package encoder
+
+import (
+	"testing"
+)
+
+func TestBoundedOrdinal(t *testing.T) {
+	encoder, err := NewBoundedOrdinal(2)
+	if err != nil {
+		t.Fatalf("bounded ordinal error: %+v", err)
+	}
+
+	red := encoder.Encode("red")
+	green := encoder.Encode("green")
+	encoder.Encode("red")
+
+	blue := encoder.Encode("blue")
+	if blue != green {
+		t.Errorf("code %d was not the recycled code %d of green", blue, green)
+	}
+	if encoder.Contains("green") || encoder.Decode(red) != "red" || encoder.Decode(blue) != "blue" {
+		t.Error("least recently used value was not evicted")
+	}
+
+	evictions := encoder.Evictions()
+	if len(evictions) != 1 || evictions[0] != (Eviction{Code: green, Evicted: "green", Replaced: "blue"}) {
+		t.Errorf("evictions were %v", evictions)
+	}
+	if len(encoder.Evictions()) != 0 {
+		t.Error("eviction log was not cleared")
+	}
+
+	_, err = NewBoundedOrdinal(0)
+	if err != ErrCapacity {
+		t.Error("expected capacity error")
+	}
+}
+
+func TestBoundedOrdinalEvictionLog(t *testing.T) {
+	encoder, err := NewBoundedOrdinal(2)
+	if err != nil {
+		t.Fatalf("bounded ordinal error: %+v", err)
+	}
+
+	var observed int
+	encoder.OnEviction(func(eviction Eviction) {
+		observed++
+	})
+
+	for _, v := range []string{"a", "b", "c", "d", "e"} {
+		encoder.Encode(v)
+	}
+
+	evictions := encoder.Evictions()
+	if len(evictions) != 2 || evictions[0].Replaced != "d" || evictions[1].Replaced != "e" {
+		t.Errorf("evictions were %v and not the two most recent", evictions)
+	}
+	if observed != 3 {
+		t.Errorf("observed %d evictions and not 3", observed)
+	}
+}
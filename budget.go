@@ -0,0 +1,177 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"sync"
+)
+
+// Sizer is implemented by encoders that can report an
+// estimate of the memory they hold, such as Ordinal,
+// OneHot and the frequency encoders.
+type Sizer interface {
+	SizeBytes() int
+}
+
+// MemoryBudget enforces a limit on the combined
+// memory held by a set of encoders.
+// When the limit is exceeded the budget will either
+// return an `ErrMemoryBudget` error or, if created
+// with eviction enabled, evict the oldest encoders
+// until the set fits within the limit. An encoder
+// being added is never evicted to make room for itself.
+type MemoryBudget struct {
+	limit    int
+	evict    bool
+	names    []string
+	encoders map[string]Sizer
+	*sync.Mutex
+}
+
+// NewMemoryBudget will create a memory budget
+// of `limit` bytes.
+func NewMemoryBudget(limit int, evict bool) *MemoryBudget {
+	return &MemoryBudget{
+		limit:    limit,
+		evict:    evict,
+		names:    make([]string, 0),
+		encoders: make(map[string]Sizer),
+		Mutex:    &sync.Mutex{},
+	}
+}
+
+// Add will add the encoder to the budget under the given name,
+// replacing any encoder previously added with the same name.
+// The budget is enforced after the encoder is added, evicting
+// the oldest of the other encoders when eviction is enabled.
+// If the limit can not be met without evicting the encoder
+// itself, or is exceeded without eviction, an `ErrMemoryBudget`
+// error is returned, nothing is evicted, the encoder is not
+// added and any encoder it replaced is kept.
+func (b *MemoryBudget) Add(name string, e Sizer) ([]string, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	names := append([]string{}, b.names...)
+	previous, replaced := b.encoders[name]
+
+	b.remove(name)
+	b.names = append(b.names, name)
+	b.encoders[name] = e
+
+	evicted, err := b.enforce(name)
+	if err != nil {
+		b.names = names
+		delete(b.encoders, name)
+		if replaced {
+			b.encoders[name] = previous
+		}
+	}
+
+	return evicted, err
+}
+
+// Remove will remove the named encoder from the budget.
+func (b *MemoryBudget) Remove(name string) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.remove(name)
+}
+
+// Enforce will check the combined size of the encoders against
+// the limit, as encoders may have grown since they were added.
+// If the limit is exceeded and eviction is enabled, the names
+// of the evicted encoders are returned, oldest first; otherwise
+// an `ErrMemoryBudget` error is returned.
+func (b *MemoryBudget) Enforce() ([]string, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	return b.enforce("")
+}
+
+// SizeBytes will return the combined estimated
+// size of every encoder in the budget.
+func (b *MemoryBudget) SizeBytes() int {
+	b.Lock()
+	defer b.Unlock()
+
+	return b.size()
+}
+
+// Limit will return the limit, in bytes,
+// used when creating the MemoryBudget.
+func (b *MemoryBudget) Limit() int {
+	return b.limit
+}
+
+// enforce will evict the oldest encoders, other than
+// the one named `keep`, until the set fits within the limit.
+func (b *MemoryBudget) enforce(keep string) ([]string, error) {
+	size := b.size()
+	if size <= b.limit {
+		return []string{}, nil
+	}
+
+	if !b.evict {
+		return []string{}, ErrMemoryBudget
+	}
+
+	// the kept encoder must fit within the limit on its
+	// own, otherwise evicting the others is pointless
+	if e, ok := b.encoders[keep]; ok && e.SizeBytes() > b.limit {
+		return []string{}, ErrMemoryBudget
+	}
+
+	evicted := make([]string, 0)
+	for _, name := range append([]string{}, b.names...) {
+		if size <= b.limit {
+			break
+		}
+		if name == keep {
+			continue
+		}
+
+		size -= b.encoders[name].SizeBytes()
+		b.remove(name)
+		evicted = append(evicted, name)
+	}
+
+	return evicted, nil
+}
+
+func (b *MemoryBudget) size() int {
+	var size int
+	for _, e := range b.encoders {
+		size += e.SizeBytes()
+	}
+
+	return size
+}
+
+func (b *MemoryBudget) remove(name string) {
+	if _, ok := b.encoders[name]; !ok {
+		return
+	}
+
+	delete(b.encoders, name)
+	for i, n := range b.names {
+		if n == name {
+			b.names = append(b.names[:i], b.names[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package encoder
+
+import (
+	"testing"
+)
+
+func TestMemoryBudget(t *testing.T) {
+	first := NewOrdinal(true)
+	second := NewOrdinal(true)
+	second.Encode("hello world")
+
+	limit := first.SizeBytes() + second.SizeBytes()
+
+	b := NewMemoryBudget(limit, false)
+	b.Add("first", first)
+	b.Add("second", second)
+
+	second.Encode("goodbye world")
+	_, err := b.Enforce()
+	if err != ErrMemoryBudget {
+		t.Error("expected memory budget error")
+	}
+
+	b = NewMemoryBudget(second.SizeBytes(), true)
+	b.Add("first", first)
+	evicted, err := b.Add("second", second)
+	if err != nil {
+		t.Errorf("enforce error: %+v", err)
+	}
+	if len(evicted) != 1 || evicted[0] != "first" {
+		t.Errorf("evicted encoders were %v and not [first]", evicted)
+	}
+	if b.SizeBytes() != second.SizeBytes() {
+		t.Error("budget size did not match remaining encoder")
+	}
+}
+
+func TestMemoryBudgetAddRollback(t *testing.T) {
+	small := NewOrdinal(true)
+	large := NewOrdinal(true)
+	large.EncodeSlice([]string{"a", "b", "c", "d", "e", "f", "g", "h"})
+
+	b := NewMemoryBudget(small.SizeBytes(), false)
+	if _, err := b.Add("column", small); err != nil {
+		t.Fatalf("add error: %+v", err)
+	}
+	if _, err := b.Add("column", large); err != ErrMemoryBudget {
+		t.Fatal("expected memory budget error")
+	}
+	if b.SizeBytes() != small.SizeBytes() {
+		t.Error("rejected encoder was kept in the budget")
+	}
+	if _, err := b.Add("other", large); err != ErrMemoryBudget || b.SizeBytes() != small.SizeBytes() {
+		t.Error("rejected encoder was kept in the budget")
+	}
+
+	b = NewMemoryBudget(small.SizeBytes(), true)
+	b.Add("column", small)
+	evicted, err := b.Add("other", large)
+	if err != ErrMemoryBudget || len(evicted) != 0 {
+		t.Errorf("adding an encoder over the limit evicted %v with error %+v", evicted, err)
+	}
+	if b.SizeBytes() != small.SizeBytes() {
+		t.Error("an encoder was evicted to make room for one over the limit")
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import "sort"
+
+// WithCanonicalSerialization will make every serialized form of
+// the encoder byte-for-byte the same for the same vocabulary, so
+// that artifacts are reproducible and can be diffed. The JSON,
+// CSV and JSON Lines forms always are; this makes Gob snapshots
+// (and so MarshalBinary) write the code table and aliases as
+// sorted slices rather than maps. Canonical snapshots can only be
+// loaded by versions of the package that know the canonical form.
+// The option is serialized with the encoder, so encoders loaded
+// from a canonical JSON or Gob snapshot serialize canonically.
+func WithCanonicalSerialization() OrdinalOption {
+	return func(e *Ordinal) {
+		e.canonical = true
+	}
+}
+
+// canonicalize will replace the maps of the
+// snapshot with sorted slices of their entries.
+func (g *ordinalGob) canonicalize() {
+	hashes := make([]uint64, 0, len(g.Encoder))
+	for hash := range g.Encoder {
+		hashes = append(hashes, hash)
+	}
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+
+	g.Codes = make([]uint64, 0, 2*len(hashes))
+	for _, hash := range hashes {
+		g.Codes = append(g.Codes, hash, g.Encoder[hash])
+	}
+
+	aliases := make([]string, 0, len(g.Aliases))
+	for alias := range g.Aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	g.AliasPairs = make([]string, 0, 2*len(aliases))
+	for _, alias := range aliases {
+		g.AliasPairs = append(g.AliasPairs, alias, g.Aliases[alias])
+	}
+
+	g.Encoder, g.Aliases = nil, nil
+}
+
+// uncanonicalize will restore the maps of
+// a snapshot from its sorted slices.
+func (g *ordinalGob) uncanonicalize() {
+	if g.Encoder == nil {
+		g.Encoder = make(map[uint64]uint64, len(g.Codes)/2)
+	}
+	for i := 0; i+1 < len(g.Codes); i += 2 {
+		g.Encoder[g.Codes[i]] = g.Codes[i+1]
+	}
+
+	if g.Aliases == nil && len(g.AliasPairs) > 0 {
+		g.Aliases = make(map[string]string, len(g.AliasPairs)/2)
+	}
+	for i := 0; i+1 < len(g.AliasPairs); i += 2 {
+		g.Aliases[g.AliasPairs[i]] = g.AliasPairs[i+1]
+	}
+}
@@ -0,0 +1,71 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestCanonicalSerialization(t *testing.T) {
+	build := func() *Ordinal {
+		e := NewOrdinal(true, WithCanonicalSerialization())
+		e.EncodeSlice([]string{"red", "green", "blue", "yellow", "purple", "orange"})
+		e.Alias("red", "rouge")
+		e.Alias("blue", "bleu")
+		return e
+	}
+
+	first, err := build().MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	for i := 0; i < 20; i++ {
+		b, _ := build().MarshalBinary()
+		if !bytes.Equal(first, b) {
+			t.Fatal("canonical snapshots differ")
+		}
+	}
+
+	loaded := NewOrdinal(false)
+	if err := loaded.UnmarshalBinary(first); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	if loaded.Encode("bleu") != 3 || loaded.Length() != 7 || !loaded.Contains("purple") {
+		t.Error("canonical snapshot did not round trip")
+	}
+
+	// the option survives a round trip
+	again, err := loaded.MarshalBinary()
+	if err != nil || !bytes.Equal(first, again) {
+		t.Error("reloaded encoder did not serialize canonically")
+	}
+
+	data, err := json.Marshal(build())
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	loaded = NewOrdinal(false)
+	if err := json.Unmarshal(data, loaded); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	again, err = loaded.MarshalBinary()
+	if err != nil || !bytes.Equal(first, again) {
+		t.Error("encoder loaded from JSON did not serialize canonically")
+	}
+}
+
+func TestOneHotCSVOrder(t *testing.T) {
+	e := NewOneHot()
+	for _, v := range []string{"red", "green", "blue", "yellow", "purple"} {
+		e.Encode(v)
+	}
+
+	b, err := e.MarshalCSV()
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	expected := "value,code\n,1\nred,2\ngreen,3\nblue,4\nyellow,5\npurple,6\n"
+	if string(b) != expected {
+		t.Errorf("csv was %q and not %q", b, expected)
+	}
+}
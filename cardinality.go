@@ -0,0 +1,141 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"math"
+	"math/bits"
+)
+
+const (
+	minCardinalityPrecision = 4
+	maxCardinalityPrecision = 16
+)
+
+// CardinalityEstimator is a HyperLogLog sketch
+// that estimates the number of distinct values
+// it has been fed using a fixed amount of memory.
+// A CardinalityEstimator can be fed the values of
+// a column while streaming over a dataset, and the
+// estimate used to choose between encoders.
+type CardinalityEstimator struct {
+	precision uint8
+	registers []uint8
+}
+
+// NewCardinalityEstimator will create a HyperLogLog sketch with
+// 2^precision registers. The precision must be between 4 and 16;
+// the standard error of the estimate is about 1.04 / sqrt(2^precision).
+func NewCardinalityEstimator(precision uint8) (*CardinalityEstimator, error) {
+	if precision < minCardinalityPrecision || precision > maxCardinalityPrecision {
+		return &CardinalityEstimator{}, ErrPrecision
+	}
+
+	return &CardinalityEstimator{
+		precision: precision,
+		registers: make([]uint8, 1<<precision),
+	}, nil
+}
+
+// Add will feed a value to the estimator.
+func (c *CardinalityEstimator) Add(s string) {
+	h := mix64(hashString(s))
+	index := h >> (64 - c.precision)
+	w := h<<c.precision | 1<<(c.precision-1)
+	rank := uint8(bits.LeadingZeros64(w) + 1)
+
+	if rank > c.registers[index] {
+		c.registers[index] = rank
+	}
+}
+
+// AddSlice will feed every value in the slice to the estimator.
+func (c *CardinalityEstimator) AddSlice(s []string) {
+	for _, v := range s {
+		c.Add(v)
+	}
+}
+
+// Estimate will return the approximate number of
+// distinct values fed to the estimator.
+func (c *CardinalityEstimator) Estimate() uint64 {
+	m := float64(len(c.registers))
+	if m == 0 {
+		return 0
+	}
+
+	var sum float64
+	var zeros int
+	for _, r := range c.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	estimate := hllAlpha(len(c.registers)) * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+
+	return uint64(estimate + 0.5)
+}
+
+// Merge will combine the registers of another estimator
+// into this one, so that the estimate covers the values
+// fed to both. Both estimators must have the same precision.
+func (c *CardinalityEstimator) Merge(other *CardinalityEstimator) error {
+	if c.precision != other.precision {
+		return ErrPrecision
+	}
+
+	for i, r := range other.registers {
+		if r > c.registers[i] {
+			c.registers[i] = r
+		}
+	}
+
+	return nil
+}
+
+// Precision will return the precision used
+// when creating the CardinalityEstimator.
+func (c *CardinalityEstimator) Precision() uint8 {
+	return c.precision
+}
+
+func hllAlpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	}
+
+	return 0.7213 / (1 + 1.079/float64(m))
+}
+
+// mix64 is the 64-bit finalizer from MurmurHash3,
+// used to spread the bits of an FNV hash.
+func mix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
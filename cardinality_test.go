@@ -0,0 +1,27 @@
+package encoder
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestCardinalityEstimator(t *testing.T) {
+	c, err := NewCardinalityEstimator(14)
+	if err != nil {
+		t.Fatalf("cardinality estimator error: %+v", err)
+	}
+
+	for i := 0; i < 20000; i++ {
+		c.Add(strconv.Itoa(i % 10000))
+	}
+
+	estimate := c.Estimate()
+	if estimate < 9500 || estimate > 10500 {
+		t.Errorf("estimate was %d and not close to 10000", estimate)
+	}
+
+	_, err = NewCardinalityEstimator(20)
+	if err != ErrPrecision {
+		t.Error("expected precision error")
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+// Encoder names used by the Python `category_encoders` library.
+const (
+	CategoryEncodersJamesStein = "JamesSteinEncoder"
+	CategoryEncodersGLMM       = "GLMMEncoder"
+	CategoryEncodersTarget     = "TargetEncoder"
+	CategoryEncodersWOE        = "WOEEncoder"
+)
+
+// CategoryEncodersMapping is the JSON schema used to exchange fitted
+// target encoder mapping tables with the Python `category_encoders`
+// library, e.g.
+//
+//	{"encoder": "JamesSteinEncoder", "column": "color", "mapping": {"red": 0.42, "blue": 0.17}}
+//
+// `mapping` holds the code of every category keyed by the category
+// itself. In Python it is produced by joining a fitted encoder's
+// `ordinal_encoder.mapping` (category -> ordinal) with its `mapping`
+// (ordinal -> code) for the column. `prior` is used by the
+// `TargetEncoder`, where it holds the code of unseen categories
+// (the encoder's `_mean`), and by the `WOEEncoder`, where it holds
+// the regularized log-odds of the whole target,
+// ln((P + 2r) / (N + 2r)) for P positive and N negative training
+// rows and regularization r, which is not part of the fitted
+// Python encoder but is needed to turn weights of evidence back
+// into log-odds.
+type CategoryEncodersMapping struct {
+	Encoder string             `json:"encoder"`
+	Column  string             `json:"column"`
+	Mapping map[string]float64 `json:"mapping"`
+	Prior   float64            `json:"prior,omitempty"`
+}
+
+// CategoryEncodersMapping will export the encoder as the
+// mapping table of the given column of a `JamesSteinEncoder`.
+func (e *JamesSteinRegression) CategoryEncodersMapping(column string) *CategoryEncodersMapping {
+	return newCategoryEncodersMapping(CategoryEncodersJamesStein, column, e.encoder)
+}
+
+// CategoryEncodersMapping will export the encoder as the
+// mapping table of the given column of a `GLMMEncoder`.
+func (e *GLMMRegression) CategoryEncodersMapping(column string) *CategoryEncodersMapping {
+	return newCategoryEncodersMapping(CategoryEncodersGLMM, column, e.encoder)
+}
+
+// CategoryEncodersMapping will export the encoder as the
+// mapping table of the given column of a `GLMMEncoder`.
+func (e *GLMMClassification) CategoryEncodersMapping(column string) *CategoryEncodersMapping {
+	return newCategoryEncodersMapping(CategoryEncodersGLMM, column, e.encoder)
+}
+
+// CategoryEncodersMapping will export the encoder as the
+// mapping table of the given column of a binary `TargetEncoder`.
+func (e *BayesianClassification) CategoryEncodersMapping(column string) *CategoryEncodersMapping {
+	m := newCategoryEncodersMapping(CategoryEncodersTarget, column, e.encoder)
+	m.Prior = e.prior
+	return m
+}
+
+// CategoryEncodersMapping will export the encoder as the
+// mapping table of the given column of a `WOEEncoder` with
+// the smoothing as its regularization. The weight of evidence
+// of a category is its log-odds less the log-odds of the whole
+// target, ln((p + r) / (n + r)) - ln((P + 2r) / (N + 2r)), as
+// computed by `category_encoders`.
+func (e *LogOdds) CategoryEncodersMapping(column string) *CategoryEncodersMapping {
+	m := newCategoryEncodersMapping(CategoryEncodersWOE, column, e.encoder)
+	for k := range m.Mapping {
+		m.Mapping[k] -= e.overall
+	}
+	m.Prior = e.overall
+	return m
+}
+
+// JamesSteinRegression will create a JamesSteinRegression encoder
+// from the mapping table of a `JamesSteinEncoder`.
+func (m *CategoryEncodersMapping) JamesSteinRegression() (*JamesSteinRegression, error) {
+	if m.Encoder != CategoryEncodersJamesStein {
+		return &JamesSteinRegression{}, ErrUnsupported
+	}
+
+	return &JamesSteinRegression{
+		encoder: copyMapping(m.Mapping),
+	}, nil
+}
+
+// GLMMRegression will create a GLMMRegression encoder
+// from the mapping table of a `GLMMEncoder`.
+func (m *CategoryEncodersMapping) GLMMRegression() (*GLMMRegression, error) {
+	if m.Encoder != CategoryEncodersGLMM {
+		return &GLMMRegression{}, ErrUnsupported
+	}
+
+	return &GLMMRegression{
+		encoder: copyMapping(m.Mapping),
+	}, nil
+}
+
+// GLMMClassification will create a GLMMClassification encoder
+// from the mapping table of a binomial `GLMMEncoder`.
+func (m *CategoryEncodersMapping) GLMMClassification() (*GLMMClassification, error) {
+	if m.Encoder != CategoryEncodersGLMM {
+		return &GLMMClassification{}, ErrUnsupported
+	}
+
+	return &GLMMClassification{
+		encoder: copyMapping(m.Mapping),
+	}, nil
+}
+
+// BayesianClassification will create a BayesianClassification
+// encoder from the mapping table of a binary `TargetEncoder`.
+// Unseen categories are encoded as the prior.
+func (m *CategoryEncodersMapping) BayesianClassification() (*BayesianClassification, error) {
+	if m.Encoder != CategoryEncodersTarget {
+		return &BayesianClassification{}, ErrUnsupported
+	}
+
+	return &BayesianClassification{
+		encoder: copyMapping(m.Mapping),
+		prior:   m.Prior,
+	}, nil
+}
+
+// LogOdds will create a LogOdds encoder from the mapping
+// table of a `WOEEncoder` by adding the prior, the log-odds
+// of the whole target, to every weight of evidence. Without a
+// prior the codes are the weights of evidence themselves. The
+// encoder has the smoothing of the `WOEEncoder`'s default
+// regularization, 1. Unseen categories have log-odds 0, which
+// is not the weight of evidence 0 that Python gives them
+// unless the prior is 0.
+func (m *CategoryEncodersMapping) LogOdds() (*LogOdds, error) {
+	if m.Encoder != CategoryEncodersWOE {
+		return &LogOdds{}, ErrUnsupported
+	}
+
+	encoder := copyMapping(m.Mapping)
+	for k := range encoder {
+		encoder[k] += m.Prior
+	}
+
+	return &LogOdds{
+		encoder:   encoder,
+		smoothing: 1,
+		overall:   m.Prior,
+	}, nil
+}
+
+func newCategoryEncodersMapping(encoder, column string, mapping map[string]float64) *CategoryEncodersMapping {
+	return &CategoryEncodersMapping{
+		Encoder: encoder,
+		Column:  column,
+		Mapping: copyMapping(mapping),
+	}
+}
+
+func copyMapping(mapping map[string]float64) map[string]float64 {
+	m := make(map[string]float64, len(mapping))
+	for k, v := range mapping {
+		m[k] = v
+	}
+
+	return m
+}
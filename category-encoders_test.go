@@ -0,0 +1,80 @@
+package encoder
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestCategoryEncodersMapping(t *testing.T) {
+	encoder, err := NewJamesSteinRegression([]string{"a", "b"}, []float64{1, 2})
+	if err != nil {
+		t.Fatalf("james-stein error: %+v", err)
+	}
+
+	data, err := json.Marshal(encoder.CategoryEncodersMapping("letter"))
+	if err != nil {
+		t.Errorf("json marshal error: %+v", err)
+	}
+
+	var m CategoryEncodersMapping
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		t.Errorf("json unmarshal error: %+v", err)
+	}
+
+	newEncoder, err := m.JamesSteinRegression()
+	if err != nil {
+		t.Errorf("import error: %+v", err)
+	}
+	if v, _ := newEncoder.Get("b"); v != 2 {
+		t.Errorf("code was %f and not 2", v)
+	}
+
+	_, err = m.GLMMRegression()
+	if err != ErrUnsupported {
+		t.Error("expected unsupported error")
+	}
+}
+
+func TestCategoryEncodersTargetAndWOE(t *testing.T) {
+	data := []byte(`{"encoder": "TargetEncoder", "column": "color", "mapping": {"red": 0.8, "blue": 0.1}, "prior": 0.4}`)
+	var m CategoryEncodersMapping
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("json unmarshal error: %+v", err)
+	}
+
+	target, err := m.BayesianClassification()
+	if err != nil {
+		t.Fatalf("target import error: %+v", err)
+	}
+	if v := target.Transform("green")[0]; v != 0.4 {
+		t.Errorf("unseen code was %f and not the prior 0.4", v)
+	}
+	if exported := target.CategoryEncodersMapping("color"); exported.Prior != 0.4 || exported.Mapping["red"] != 0.8 {
+		t.Errorf("unexpected exported mapping %+v", exported)
+	}
+	if _, err := m.LogOdds(); err != ErrUnsupported {
+		t.Error("expected unsupported error")
+	}
+
+	encoder, err := NewLogOdds([]string{"a", "a", "b"}, []bool{true, false, false}, 1)
+	if err != nil {
+		t.Fatalf("log-odds error: %+v", err)
+	}
+	exported := encoder.CategoryEncodersMapping("letter")
+	// category_encoders: ln(((0+1)/(1+2)) / ((1+1)/(2+2)))
+	if v := exported.Mapping["b"]; math.Abs(v-math.Log(2.0/3)) > 1e-12 {
+		t.Errorf("weight of evidence was %f and not log(2/3)", v)
+	}
+	woe, err := exported.LogOdds()
+	if err != nil {
+		t.Fatalf("woe import error: %+v", err)
+	}
+	if v, _ := woe.Get("b"); math.Abs(v-math.Log(0.5)) > 1e-12 {
+		t.Errorf("code was %f and not log(0.5)", v)
+	}
+	if v := woe.Transform("c")[0]; v != 0 {
+		t.Errorf("unseen code was %f and not 0", v)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// ClassCounts is a one way encoder.
+// You cannot decode ClassCounts values
+// as some values may be encoded with the same
+// numerical codes.
+// ClassCounts is a target-based encoder for multiclass
+// targets that encodes every category with the raw count
+// of every class among its observations, one dimension
+// per class in sorted class order, as evidence for models
+// that apply their own smoothing.
+type ClassCounts struct {
+	classes []string
+	encoder map[string][]float64
+}
+
+// NewClassCounts will create a ClassCounts encoder.
+func NewClassCounts(values []string, target []string) (*ClassCounts, error) {
+	if len(target) != len(values) {
+		return &ClassCounts{}, ErrTargetLength
+	}
+
+	classes, encoder, _ := countClasses(values, target, nil)
+
+	return &ClassCounts{
+		classes: classes,
+		encoder: encoder,
+	}, nil
+}
+
+// Get will retrieve the class counts for the given
+// categorical value. Unseen values receive zero counts.
+func (e *ClassCounts) Get(s string) ([]float64, bool) {
+	counts, ok := e.encoder[s]
+	if !ok {
+		return make([]float64, len(e.classes), len(e.classes)), false
+	}
+
+	return append([]float64{}, counts...), true
+}
+
+// Count will return the number of observations of the
+// categorical value with the given class.
+func (e *ClassCounts) Count(s, class string) float64 {
+	i := sort.SearchStrings(e.classes, class)
+	counts, ok := e.encoder[s]
+	if !ok || i == len(e.classes) || e.classes[i] != class {
+		return 0
+	}
+
+	return counts[i]
+}
+
+// Transform will return the class counts of the string
+// as features. Unseen strings receive zero counts.
+func (e *ClassCounts) Transform(s string) []float64 {
+	counts, _ := e.Get(s)
+	return counts
+}
+
+// Classes will return the classes of the
+// target in the order of the dimensions.
+func (e *ClassCounts) Classes() []string {
+	return append([]string{}, e.classes...)
+}
+
+// FeatureNames will return a name for every dimension,
+// in order, of the form `column_class`.
+func (e *ClassCounts) FeatureNames(column string) []string {
+	names := make([]string, len(e.classes), len(e.classes))
+	for i, class := range e.classes {
+		names[i] = column + "_" + class
+	}
+
+	return names
+}
+
+// SizeBytes will return an estimate of the
+// memory held by the encoder in bytes.
+func (e *ClassCounts) SizeBytes() int {
+	var size int
+	for k := range e.encoder {
+		size += mapEntryBytes + len(k) + len(e.classes)*float64Bytes
+	}
+
+	return size
+}
+
+type classCountsJSON struct {
+	Classes []string             `json:"classes"`
+	Mapping map[string][]float64 `json:"mapping"`
+}
+
+// MarshalJSON will encode the classes and the
+// class counts of every category.
+func (e *ClassCounts) MarshalJSON() ([]byte, error) {
+	return json.Marshal(classCountsJSON{
+		Classes: e.classes,
+		Mapping: e.encoder,
+	})
+}
+
+// UnmarshalJSON will return an `ErrLength` error if a
+// vector of counts does not have one dimension per class,
+// and an `ErrFormat` error if the classes are not sorted.
+func (e *ClassCounts) UnmarshalJSON(data []byte) error {
+	var m classCountsJSON
+	err := json.Unmarshal(data, &m)
+	if err != nil {
+		return err
+	}
+	if !sort.StringsAreSorted(m.Classes) {
+		return ErrFormat
+	}
+	for _, counts := range m.Mapping {
+		if len(counts) != len(m.Classes) {
+			return ErrLength
+		}
+	}
+	if m.Mapping == nil {
+		m.Mapping = make(map[string][]float64)
+	}
+
+	e.classes, e.encoder = m.Classes, m.Mapping
+	return nil
+}
@@ -0,0 +1,43 @@
+package encoder
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestClassCounts(t *testing.T) {
+	values := []string{"a", "a", "a", "b", "b", "c"}
+	target := []string{"x", "x", "y", "z", "z", "x"}
+
+	encoder, err := NewClassCounts(values, target)
+	if err != nil {
+		t.Fatalf("encoder error: %+v", err)
+	}
+
+	a, ok := encoder.Get("a")
+	if !ok || len(a) != 3 || a[0] != 2 || a[1] != 1 || a[2] != 0 {
+		t.Errorf("unexpected class counts %v", a)
+	}
+	if unseen, ok := encoder.Get("d"); ok || unseen[0] != 0 || len(unseen) != 3 {
+		t.Errorf("unseen value should receive zero counts, got %v", unseen)
+	}
+	if n := encoder.Count("b", "z"); n != 2 {
+		t.Errorf("count was %f and not 2", n)
+	}
+
+	b, err := json.Marshal(encoder)
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	var loaded ClassCounts
+	if err := json.Unmarshal(b, &loaded); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	if loaded.Count("a", "y") != 1 || len(loaded.Classes()) != 3 {
+		t.Error("counts did not round trip")
+	}
+
+	if _, err := NewClassCounts(values, target[1:]); err != ErrTargetLength {
+		t.Error("expected target length error")
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"math"
+	"strconv"
+)
+
+// ColumnKind names the encoder applied to a column
+// by the StructEncoder, RecordEncoder and Dataset.
+type ColumnKind string
+
+// Column kinds.
+const (
+	// KindOrdinal encodes a column with its ordinal code.
+	// Values unseen when fitting are encoded as NaN.
+	KindOrdinal ColumnKind = "ordinal"
+	// KindOneHot expands a column into one feature per value
+	// seen when fitting. Unseen values are all zeros.
+	KindOneHot ColumnKind = "onehot"
+	// KindFrequency encodes a column with the number
+	// of times its value was seen when fitting.
+	KindFrequency ColumnKind = "frequency"
+	// KindMinMax scales a numeric column to [0, 1] by the
+	// minimum and maximum seen when fitting.
+	KindMinMax ColumnKind = "minmax"
+	// KindNumeric passes a numeric column through.
+	KindNumeric ColumnKind = "numeric"
+)
+
+// column is a fitted encoder for a column.
+type column struct {
+	kind      ColumnKind
+	ordinal   *ReadOnlyOrdinal
+	onehot    *OneHot
+	frequency *Frequency
+	min, max  float64
+}
+
+// fitColumn will fit an encoder of the given kind to the
+// values of a column. An `ErrUnsupported` error is returned
+// for an unknown kind, and the error of parsing any value
+// of a numeric column that is not a number.
+func fitColumn(kind ColumnKind, values []string) (*column, error) {
+	c := &column{
+		kind: kind,
+	}
+
+	switch kind {
+	case KindOrdinal:
+		e := NewOrdinal(false)
+		e.EncodeSlice(values)
+		c.ordinal = e.Snapshot()
+	case KindOneHot:
+		c.onehot = NewOneHot()
+		for _, v := range values {
+			c.onehot.Encode(v)
+		}
+		c.onehot.Freeze()
+	case KindFrequency:
+		c.frequency = NewFrequency(values)
+	case KindMinMax:
+		c.min, c.max = math.Inf(1), math.Inf(-1)
+		for _, v := range values {
+			x, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return c, err
+			}
+			c.min, c.max = math.Min(c.min, x), math.Max(c.max, x)
+		}
+	case KindNumeric:
+		for _, v := range values {
+			_, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return c, err
+			}
+		}
+	default:
+		return c, ErrUnsupported
+	}
+
+	return c, nil
+}
+
+// dimension will return the number of features of the column.
+func (c *column) dimension() int {
+	if c.kind == KindOneHot {
+		return c.onehot.Dimension()
+	}
+
+	return 1
+}
+
+// transform will append the features of the value to `dst`.
+// Values of numeric columns that are not numbers are NaN.
+func (c *column) transform(dst []float64, s string) []float64 {
+	switch c.kind {
+	case KindOrdinal:
+		return append(dst, c.ordinal.Transform(s)...)
+	case KindOneHot:
+		return append(dst, c.onehot.Transform(s)...)
+	case KindFrequency:
+		return append(dst, c.frequency.Transform(s)...)
+	}
+
+	x, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return append(dst, math.NaN())
+	}
+	if c.kind == KindMinMax {
+		if c.max == c.min {
+			return append(dst, 0)
+		}
+		x = (x - c.min) / (c.max - c.min)
+	}
+
+	return append(dst, x)
+}
+
+// featureNames will return the names of the features of
+// the column, `name=value` for every value of a one-hot
+// column and the name of the column otherwise.
+func (c *column) featureNames(name string) []string {
+	if c.kind == KindOneHot {
+		return c.onehot.FeatureNames(name)
+	}
+
+	return []string{name}
+}
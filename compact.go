@@ -0,0 +1,289 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"sync"
+)
+
+// compactMagic begins every compact snapshot. Version 1
+// snapshots only checksummed their values and are rejected.
+var compactMagic = []byte("ENCV\x02")
+
+// MarshalCompact will encode the encoder in a compact binary
+// form: every value as a varint length followed by its bytes, in
+// code order, then every alias with the varint code of its value,
+// the names of the preprocessors and a CRC-32 of everything
+// after the magic, so changes to any part are detected.
+// Codes are implied by the order of the values, so compact
+// snapshots are much smaller than Gob snapshots.
+func (e *Ordinal) MarshalCompact() ([]byte, error) {
+	e.RLock()
+	defer e.RUnlock()
+
+	var buf bytes.Buffer
+	buf.Write(compactMagic)
+
+	values := e.decoder.strings()
+	writeUvarint(&buf, uint64(len(values)))
+	for _, v := range values {
+		writeString(&buf, v)
+	}
+
+	aliases := e.sortedAliases()
+	writeUvarint(&buf, uint64(len(aliases)))
+	for _, alias := range aliases {
+		writeString(&buf, alias)
+		writeUvarint(&buf, e.encoder[hashString(alias)])
+	}
+
+	writeUvarint(&buf, uint64(len(e.preprocessNames)))
+	for _, name := range e.preprocessNames {
+		writeString(&buf, name)
+	}
+
+	var checksum [4]byte
+	binary.LittleEndian.PutUint32(checksum[:], crc32.ChecksumIEEE(buf.Bytes()[len(compactMagic):]))
+	buf.Write(checksum[:])
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalCompact will replace the contents of the encoder
+// with a compact snapshot, read in a single pass. An
+// `ErrFormat` error is returned if the data is not a compact
+// snapshot, an `ErrCorruptSnapshot` error if it is truncated
+// or does not match its checksum, and an `ErrPreprocessor`
+// error if the encoder uses a preprocessor that is not
+// registered.
+func (e *Ordinal) UnmarshalCompact(data []byte) error {
+	c, err := parseCompact(data)
+	if err != nil {
+		return err
+	}
+
+	preprocess, err := lookupPreprocessors(c.preprocessors)
+	if err != nil {
+		return err
+	}
+
+	values := make([]string, 0, len(c.ends)+len(c.aliases))
+	codes := make([]uint64, 0, len(c.ends)+len(c.aliases))
+	for code := range c.ends {
+		values = append(values, c.get(code))
+		codes = append(codes, uint64(code))
+	}
+	for _, alias := range c.aliases {
+		values = append(values, alias.Value)
+		codes = append(codes, alias.Code)
+	}
+
+	e.Lock()
+	defer e.Unlock()
+
+	err = e.loadRows(values, codes)
+	if err != nil {
+		return err
+	}
+
+	e.preprocessNames, e.preprocess = c.preprocessors, preprocess
+	return nil
+}
+
+// CompactView is a read-only encoder over a compact snapshot
+// that loads lazily: only the position of every value is read
+// when the view is created, values are copied out of the
+// snapshot as they are decoded, and the index used
+// for lookups is built on the first lookup. Views suit large
+// vocabularies that are mostly decoded, or only briefly used.
+// Preprocessors of the snapshot are not applied by the view.
+type CompactView struct {
+	*compactSnapshot
+	index map[uint64]uint64
+	once  sync.Once
+}
+
+// NewCompactView will create a view of a compact snapshot, which
+// must not be modified while the view is used. It returns the
+// same errors as UnmarshalCompact for invalid snapshots.
+func NewCompactView(data []byte) (*CompactView, error) {
+	c, err := parseCompact(data)
+	if err != nil {
+		return &CompactView{}, err
+	}
+
+	return &CompactView{
+		compactSnapshot: c,
+	}, nil
+}
+
+// Lookup will return the code of the given string
+// and whether or not the string has a code.
+func (v *CompactView) Lookup(s string) (uint64, bool) {
+	v.once.Do(func() {
+		v.index = make(map[uint64]uint64, len(v.ends)+len(v.aliases))
+		for code := range v.ends {
+			key := hashString(v.get(code))
+			if _, ok := v.index[key]; !ok {
+				v.index[key] = uint64(code)
+			}
+		}
+		for _, alias := range v.aliases {
+			v.index[hashString(alias.Value)] = alias.Code
+		}
+	})
+
+	code, ok := v.index[hashString(s)]
+	return code, ok
+}
+
+// Contains will return whether or not a string
+// has been assigned an ordinal code or not.
+func (v *CompactView) Contains(s string) bool {
+	_, ok := v.Lookup(s)
+	return ok
+}
+
+// Decode will return an empty string if supplied integer
+// argument is not a valid code.
+func (v *CompactView) Decode(i uint64) string {
+	value, _ := v.DecodeChecked(i)
+	return value
+}
+
+// DecodeChecked will return the string for the given code,
+// or an `ErrBounds` error if the code is not a valid code.
+func (v *CompactView) DecodeChecked(i uint64) (string, error) {
+	if i >= uint64(len(v.ends)) {
+		return "", ErrBounds
+	}
+
+	return v.get(int(i)), nil
+}
+
+// Length will return the number of encoded values.
+func (v *CompactView) Length() int {
+	return len(v.ends)
+}
+
+// compactSnapshot holds the positions of the
+// values of a compact snapshot within its data.
+type compactSnapshot struct {
+	data          []byte
+	starts        []int
+	ends          []int
+	aliases       []jsonlRow
+	preprocessors []string
+}
+
+func (c *compactSnapshot) get(i int) string {
+	return string(c.data[c.starts[i]:c.ends[i]])
+}
+
+// parseCompact will read the positions of
+// the values of a compact snapshot.
+func parseCompact(data []byte) (*compactSnapshot, error) {
+	if !bytes.HasPrefix(data, compactMagic) {
+		return nil, ErrFormat
+	}
+	r := &compactReader{data: data, pos: len(compactMagic)}
+
+	n := r.uvarint()
+	if n > uint64(len(data)) {
+		return nil, ErrCorruptSnapshot
+	}
+	c := &compactSnapshot{
+		data:   data,
+		starts: make([]int, 0, n),
+		ends:   make([]int, 0, n),
+	}
+	for i := uint64(0); i < n && r.err == nil; i++ {
+		v := r.bytes()
+		c.starts = append(c.starts, r.pos-len(v))
+		c.ends = append(c.ends, r.pos)
+	}
+
+	n = r.uvarint()
+	for i := uint64(0); i < n && r.err == nil; i++ {
+		alias := string(r.bytes())
+		c.aliases = append(c.aliases, jsonlRow{Value: alias, Code: r.uvarint()})
+	}
+
+	n = r.uvarint()
+	for i := uint64(0); i < n && r.err == nil; i++ {
+		c.preprocessors = append(c.preprocessors, string(r.bytes()))
+	}
+
+	if r.err != nil || len(data)-r.pos != 4 {
+		return nil, ErrCorruptSnapshot
+	}
+	if binary.LittleEndian.Uint32(data[r.pos:]) != crc32.ChecksumIEEE(data[len(compactMagic):r.pos]) {
+		return nil, ErrCorruptSnapshot
+	}
+
+	return c, nil
+}
+
+// compactReader reads varints and length-prefixed
+// strings, recording the first error.
+type compactReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *compactReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(r.data[r.pos:])
+	if n <= 0 {
+		r.err = ErrCorruptSnapshot
+		return 0
+	}
+	r.pos += n
+
+	return v
+}
+
+func (r *compactReader) bytes() []byte {
+	length := r.uvarint()
+	if r.err != nil {
+		return nil
+	}
+	if length > uint64(len(r.data)-r.pos) {
+		r.err = ErrCorruptSnapshot
+		return nil
+	}
+
+	b := r.data[r.pos : r.pos+int(length)]
+	r.pos += int(length)
+
+	return b
+}
+
+func writeUvarint(buf *bytes.Buffer, v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	buf.Write(b[:n])
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	writeUvarint(buf, uint64(len(s)))
+	buf.WriteString(s)
+}
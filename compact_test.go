@@ -0,0 +1,72 @@
+package encoder
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+)
+
+func TestOrdinalMarshalCompact(t *testing.T) {
+	e := NewOrdinal(true)
+	for i := 0; i < 1000; i++ {
+		e.Encode("category-" + strconv.Itoa(i))
+	}
+	e.Alias("category-7", "seven")
+
+	data, err := e.MarshalCompact()
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	snapshot, _ := e.GobEncode()
+	if len(data) >= len(snapshot) {
+		t.Errorf("compact snapshot of %d bytes is not smaller than gob's %d", len(data), len(snapshot))
+	}
+
+	loaded := NewOrdinal(false)
+	if err := loaded.UnmarshalCompact(data); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	if err := Compatible(e, loaded); err != nil || loaded.Length() != e.Length() || loaded.Encode("seven") != 8 {
+		t.Error("compact snapshot did not round trip")
+	}
+
+	view, err := NewCompactView(data)
+	if err != nil {
+		t.Fatalf("view error: %+v", err)
+	}
+	if view.Decode(8) != "category-7" || view.Length() != 1001 {
+		t.Error("unexpected view contents")
+	}
+	if code, ok := view.Lookup("seven"); !ok || code != 8 {
+		t.Error("view alias lookup failed")
+	}
+	if view.Contains("category-1000") {
+		t.Error("view contains an unseen value")
+	}
+
+	if err := loaded.UnmarshalCompact(data[:len(data)-10]); err != ErrCorruptSnapshot {
+		t.Errorf("expected corrupt snapshot error for truncated data, got %v", err)
+	}
+	corrupt := append([]byte{}, data...)
+	corrupt[20] ^= 0xff
+	if err := loaded.UnmarshalCompact(corrupt); err != ErrCorruptSnapshot {
+		t.Errorf("expected corrupt snapshot error for modified data, got %v", err)
+	}
+	corrupt = append([]byte{}, data...)
+	corrupt[len(data)-6] ^= 0xff
+	if err := loaded.UnmarshalCompact(corrupt); err != ErrCorruptSnapshot {
+		t.Errorf("expected corrupt snapshot error for a modified alias code, got %v", err)
+	}
+	aliased := bytes.Index(data, []byte("seven"))
+	corrupt = append([]byte{}, data...)
+	corrupt[aliased] = 'S'
+	if err := loaded.UnmarshalCompact(corrupt); err != ErrCorruptSnapshot {
+		t.Errorf("expected corrupt snapshot error for a modified alias, got %v", err)
+	}
+	if err := loaded.UnmarshalCompact(snapshot); err != ErrFormat {
+		t.Error("expected format error for a gob snapshot")
+	}
+	if !bytes.HasPrefix(data, compactMagic) {
+		t.Error("snapshot does not begin with the magic")
+	}
+}
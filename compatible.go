@@ -0,0 +1,45 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+// Compatible will return an `ErrIncompatible` error if a value
+// encoded by both encoders has a different code in each, such
+// as when the encoders of a training and a serving bundle have
+// drifted apart. Values encoded by only one of the encoders
+// are ignored. Neither encoder is modified.
+func Compatible(a, b OrdinalEncoder) error {
+	codes := make(map[string]uint64, b.Length())
+	for code := 0; code < b.Length(); code++ {
+		v := b.Decode(uint64(code))
+		if _, ok := codes[v]; !ok {
+			codes[v] = uint64(code)
+		}
+	}
+
+	seen := make(map[string]bool, a.Length())
+	for code := 0; code < a.Length(); code++ {
+		v := a.Decode(uint64(code))
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+
+		other, ok := codes[v]
+		if ok && other != uint64(code) {
+			return ErrIncompatible
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package encoder
+
+import "testing"
+
+func TestCompatible(t *testing.T) {
+	training := NewOrdinal(true)
+	training.EncodeSlice([]string{"red", "green", "blue"})
+
+	serving := training.Clone()
+	serving.Encode("yellow")
+	if err := Compatible(training, serving); err != nil {
+		t.Errorf("grown vocabulary was incompatible: %+v", err)
+	}
+
+	trie := NewTrieOrdinal(true)
+	trie.EncodeSlice([]string{"red", "green"})
+	if err := Compatible(trie, training); err != nil {
+		t.Errorf("trie vocabulary was incompatible: %+v", err)
+	}
+
+	drifted := NewOrdinal(true)
+	drifted.EncodeSlice([]string{"red", "blue", "green"})
+	if err := Compatible(training, drifted); err != ErrIncompatible {
+		t.Error("expected incompatible error")
+	}
+	if serving.Length() != 5 || drifted.Length() != 4 {
+		t.Error("encoders were modified")
+	}
+}
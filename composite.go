@@ -0,0 +1,118 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"strings"
+)
+
+// compositeEscape escapes the separator, and itself, within
+// the values of a tuple.
+const compositeEscape = `\`
+
+// CompositeOrdinal will encode tuples of strings, such as the
+// country, device and browser of a visit, into a single unique
+// integer code, and decode codes back into their tuples. Values
+// are escaped when joined, so tuples never collide, whatever
+// characters their values contain.
+type CompositeOrdinal struct {
+	ordinal *Ordinal
+	sep     string
+}
+
+// NewCompositeOrdinal will create a CompositeOrdinal encoder that
+// joins the values of every tuple with `sep`, or with the ASCII
+// unit separator if `sep` is empty or starts with a backslash,
+// which is used to escape the separator. The options configure the
+// ordinal encoder of the joined tuples.
+func NewCompositeOrdinal(sep string, opts ...OrdinalOption) *CompositeOrdinal {
+	if sep == "" || strings.HasPrefix(sep, compositeEscape) {
+		sep = crossSeparator
+	}
+
+	return &CompositeOrdinal{
+		ordinal: NewOrdinal(false, opts...),
+		sep:     sep,
+	}
+}
+
+// Encode will return the code of the tuple of values.
+func (e *CompositeOrdinal) Encode(values ...string) uint64 {
+	return e.ordinal.Encode(e.join(values))
+}
+
+// Contains will return whether or not the tuple
+// of values has been assigned a code.
+func (e *CompositeOrdinal) Contains(values ...string) bool {
+	return e.ordinal.Contains(e.join(values))
+}
+
+// Decode will return the tuple of values for the given
+// code, or an `ErrBounds` error if the code is not valid.
+func (e *CompositeOrdinal) Decode(code uint64) ([]string, error) {
+	s, err := e.ordinal.DecodeChecked(code)
+	if err != nil {
+		return []string{}, err
+	}
+
+	return e.split(s), nil
+}
+
+// Length will return the number of encoded tuples.
+func (e *CompositeOrdinal) Length() int {
+	return e.ordinal.Length()
+}
+
+// Ordinal will return the encoder of the joined tuples.
+func (e *CompositeOrdinal) Ordinal() *Ordinal {
+	return e.ordinal
+}
+
+func (e *CompositeOrdinal) join(values []string) string {
+	escaped := make([]string, len(values), len(values))
+	for i, v := range values {
+		v = strings.Replace(v, compositeEscape, compositeEscape+compositeEscape, -1)
+		escaped[i] = strings.Replace(v, e.sep, compositeEscape+e.sep, -1)
+	}
+
+	return strings.Join(escaped, e.sep)
+}
+
+func (e *CompositeOrdinal) split(s string) []string {
+	var values []string
+	var value strings.Builder
+	for i := 0; i < len(s); {
+		switch {
+		case strings.HasPrefix(s[i:], compositeEscape) && i+1 < len(s):
+			// the escaped character is either the escape or the
+			// first byte of the separator
+			if strings.HasPrefix(s[i+1:], e.sep) {
+				value.WriteString(e.sep)
+				i += 1 + len(e.sep)
+				continue
+			}
+			value.WriteByte(s[i+1])
+			i += 2
+		case strings.HasPrefix(s[i:], e.sep):
+			values = append(values, value.String())
+			value.Reset()
+			i += len(e.sep)
+		default:
+			value.WriteByte(s[i])
+			i++
+		}
+	}
+
+	return append(values, value.String())
+}
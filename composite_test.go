@@ -0,0 +1,44 @@
+package encoder
+
+import "testing"
+
+func TestCompositeOrdinal(t *testing.T) {
+	e := NewCompositeOrdinal("|")
+
+	tuples := [][]string{
+		{"US", "mobile", "safari"},
+		{"US|mobile", "safari"},
+		{`US\`, "mobile", "safari"},
+		{"", ""},
+		{"US", "mobile", "safari"},
+	}
+	codes := make([]uint64, len(tuples))
+	for i, tuple := range tuples {
+		codes[i] = e.Encode(tuple...)
+	}
+
+	if e.Length() != 4 || codes[0] != codes[4] {
+		t.Errorf("unexpected codes %v", codes)
+	}
+	for i, tuple := range tuples {
+		decoded, err := e.Decode(codes[i])
+		if err != nil {
+			t.Fatalf("decode error: %+v", err)
+		}
+		if len(decoded) != len(tuple) {
+			t.Fatalf("%q decoded to %q", tuple, decoded)
+		}
+		for j := range tuple {
+			if decoded[j] != tuple[j] {
+				t.Errorf("%q decoded to %q", tuple, decoded)
+			}
+		}
+	}
+
+	if !e.Contains("US|mobile", "safari") || e.Contains("US", "mobile|safari") {
+		t.Error("escaped tuples collided")
+	}
+	if _, err := e.Decode(10); err != ErrBounds {
+		t.Error("expected bounds error")
+	}
+}
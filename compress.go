@@ -0,0 +1,135 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+// Codec compresses encoder snapshots.
+// Codecs other than the built-in ones, such as zstd,
+// can be added with RegisterCodec so that snapshots
+// written with them are detected on load.
+type Codec interface {
+	// Magic is the prefix of every compressed stream,
+	// used to detect the codec on load.
+	Magic() []byte
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+// Built-in codecs.
+var (
+	Gzip Codec = gzipCodec{}
+)
+
+var (
+	codecs   = []Codec{Gzip}
+	codecsMu = &sync.RWMutex{}
+)
+
+// RegisterCodec will make snapshots compressed with the
+// codec loadable by LoadCompressed.
+func RegisterCodec(c Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+
+	codecs = append(codecs, c)
+}
+
+// SaveCompressed will write the gob snapshot of
+// the encoder to `w` compressed with the codec.
+func (e *Ordinal) SaveCompressed(w io.Writer, codec Codec) error {
+	data, err := e.GobEncode()
+	if err != nil {
+		return err
+	}
+
+	return writeCompressed(w, codec, data)
+}
+
+// LoadCompressed will replace the contents of the encoder
+// with the snapshot read from `r`. The codec is detected
+// from the snapshot, and uncompressed snapshots written
+// by GobEncode are also accepted.
+func (e *Ordinal) LoadCompressed(r io.Reader) error {
+	data, err := readCompressed(r)
+	if err != nil {
+		return err
+	}
+
+	return e.GobDecode(data)
+}
+
+func writeCompressed(w io.Writer, codec Codec, data []byte) error {
+	cw, err := codec.NewWriter(w)
+	if err != nil {
+		return err
+	}
+
+	_, err = cw.Write(data)
+	if err != nil {
+		cw.Close()
+		return err
+	}
+
+	return cw.Close()
+}
+
+func readCompressed(r io.Reader) ([]byte, error) {
+	br := bufio.NewReader(r)
+
+	codecsMu.RLock()
+	var codec Codec
+	for _, c := range codecs {
+		magic, err := br.Peek(len(c.Magic()))
+		if err == nil && bytes.Equal(magic, c.Magic()) {
+			codec = c
+			break
+		}
+	}
+	codecsMu.RUnlock()
+
+	if codec == nil {
+		return ioutil.ReadAll(br)
+	}
+
+	cr, err := codec.NewReader(br)
+	if err != nil {
+		return []byte{}, err
+	}
+	defer cr.Close()
+
+	return ioutil.ReadAll(cr)
+}
+
+type gzipCodec struct{}
+
+func (gzipCodec) Magic() []byte {
+	return []byte{0x1f, 0x8b}
+}
+
+func (gzipCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriter(w), nil
+}
+
+func (gzipCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
@@ -0,0 +1,36 @@
+package encoder
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestOrdinalCompressed(t *testing.T) {
+	encoder := NewOrdinal(false)
+	value := "hello world"
+	code := encoder.Encode(value)
+
+	var buf bytes.Buffer
+	err := encoder.SaveCompressed(&buf, Gzip)
+	if err != nil {
+		t.Fatalf("save error: %+v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), Gzip.Magic()) {
+		t.Error("snapshot was not gzip compressed")
+	}
+
+	newEncoder := NewOrdinal(false)
+	err = newEncoder.LoadCompressed(&buf)
+	if err != nil {
+		t.Fatalf("load error: %+v", err)
+	}
+	if newEncoder.Decode(code) != value {
+		t.Error("decoded value did not equal original value")
+	}
+
+	data, _ := encoder.GobEncode()
+	err = newEncoder.LoadCompressed(bytes.NewReader(data))
+	if err != nil || newEncoder.Decode(code) != value {
+		t.Errorf("uncompressed load error: %+v", err)
+	}
+}
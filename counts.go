@@ -0,0 +1,56 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+// WithCounts will count how many times every value is
+// encoded, so that rare categories and drift in the
+// category distribution can be found with Count and TopN.
+func WithCounts() OrdinalOption {
+	return func(e *Ordinal) {
+		e.counts = make(map[uint64]int)
+	}
+}
+
+// Count will return the number of times the value has been
+// encoded, or 0 if the encoder was not created with `WithCounts`.
+func (e *Ordinal) Count(s string) int {
+	e.RLock()
+	defer e.RUnlock()
+
+	code, ok := e.encoder[hashString(e.prepare(s))]
+	if !ok {
+		return 0
+	}
+
+	return e.counts[code]
+}
+
+// TopN will return the `k` most frequently encoded values,
+// most frequent first, or nothing if the encoder was not
+// created with `WithCounts`.
+func (e *Ordinal) TopN(k int) []CategoryCount {
+	e.RLock()
+	defer e.RUnlock()
+
+	return e.topN(k)
+}
+
+func (e *Ordinal) topN(k int) []CategoryCount {
+	counts := make(map[string]int, len(e.counts))
+	for code, count := range e.counts {
+		counts[e.decoder.get(int(code))] = count
+	}
+
+	return topCounts(counts, k)
+}
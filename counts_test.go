@@ -0,0 +1,28 @@
+package encoder
+
+import (
+	"testing"
+)
+
+func TestOrdinalCounts(t *testing.T) {
+	encoder := NewOrdinal(false, WithCounts())
+	encoder.EncodeSlice([]string{"red", "blue", "red", "green", "red", "blue"})
+	encoder.Alias("red", "rouge")
+	encoder.Encode("rouge")
+
+	if encoder.Count("red") != 4 || encoder.Count("blue") != 2 || encoder.Count("purple") != 0 {
+		t.Errorf("unexpected counts %d %d", encoder.Count("red"), encoder.Count("blue"))
+	}
+
+	top := encoder.TopN(2)
+	if len(top) != 2 || top[0].Value != "red" || top[1].Value != "blue" || top[1].Count != 2 {
+		t.Errorf("unexpected top values %v", top)
+	}
+	if len(encoder.Stats().Top) != 3 {
+		t.Error("stats did not report the top values")
+	}
+
+	if NewOrdinal(false).TopN(5) == nil || len(NewOrdinal(false).Stats().Top) != 0 {
+		t.Error("encoders without counts should report no top values")
+	}
+}
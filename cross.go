@@ -0,0 +1,122 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"strings"
+)
+
+// crossSeparator joins the values of an interaction. It is
+// the ASCII unit separator, which does not occur in text.
+const crossSeparator = "\x1f"
+
+// Cross will encode combinations of values from two or more
+// categorical columns as interaction categories, e.g. the
+// pair ("US", "mobile") as the single category "US x mobile".
+// Interactions are either given ordinal codes, and so can be
+// decoded, or hashed into a fixed number of buckets.
+type Cross struct {
+	ordinal *Ordinal
+	hash    *Hash
+}
+
+// NewCross will create a Cross encoder. If `buckets` is 0 every
+// interaction is given its own ordinal code, otherwise the codes
+// of interactions are hashed into the range [0, buckets).
+func NewCross(buckets uint64) *Cross {
+	if buckets > 0 {
+		return &Cross{
+			hash: NewHash(buckets),
+		}
+	}
+
+	return &Cross{
+		ordinal: NewOrdinal(false),
+	}
+}
+
+// NewCrossSeeded will create a Cross encoder that hashes the
+// codes of interactions into the range [0, buckets) with the
+// given seed, like NewHashSeeded.
+func NewCrossSeeded(buckets, seed uint64) *Cross {
+	if buckets == 0 {
+		return NewCross(0)
+	}
+
+	return &Cross{
+		hash: NewHashSeeded(buckets, seed),
+	}
+}
+
+// Encode will return the code of the interaction of the values.
+func (e *Cross) Encode(values ...string) uint64 {
+	s := strings.Join(values, crossSeparator)
+	if e.hash != nil {
+		return e.hash.Encode(s)
+	}
+
+	return e.ordinal.Encode(s)
+}
+
+// EncodeColumns will return the code of the interaction of
+// every row of the columns. If the columns are not all the
+// same length an `ErrLength` error is returned.
+func (e *Cross) EncodeColumns(columns ...[]string) ([]uint64, error) {
+	if len(columns) == 0 {
+		return []uint64{}, nil
+	}
+
+	for _, column := range columns {
+		if len(column) != len(columns[0]) {
+			return []uint64{}, ErrLength
+		}
+	}
+
+	codes := make([]uint64, len(columns[0]), len(columns[0]))
+	values := make([]string, len(columns), len(columns))
+	for i := range codes {
+		for j, column := range columns {
+			values[j] = column[i]
+		}
+		codes[i] = e.Encode(values...)
+	}
+
+	return codes, nil
+}
+
+// Decode will return the values of the interaction with
+// the given code, or nil if the code is not valid or the
+// encoder hashes interactions.
+func (e *Cross) Decode(code uint64) []string {
+	if e.hash != nil {
+		return nil
+	}
+
+	s, err := e.ordinal.DecodeChecked(code)
+	if err != nil {
+		return nil
+	}
+
+	return strings.Split(s, crossSeparator)
+}
+
+// Length will return the number of interactions encoded,
+// or the number of buckets if the encoder hashes them.
+func (e *Cross) Length() int {
+	if e.hash != nil {
+		return int(e.hash.dimension)
+	}
+
+	return e.ordinal.Length()
+}
@@ -0,0 +1,37 @@
+package encoder
+
+import (
+	"testing"
+)
+
+func TestCross(t *testing.T) {
+	encoder := NewCross(0)
+	codes, err := encoder.EncodeColumns(
+		[]string{"US", "US", "DE", "US"},
+		[]string{"mobile", "web", "mobile", "mobile"},
+	)
+	if err != nil {
+		t.Fatalf("encode error: %+v", err)
+	}
+	if codes[0] != codes[3] || codes[0] == codes[1] || codes[0] == codes[2] || encoder.Length() != 3 {
+		t.Errorf("unexpected interaction codes %v", codes)
+	}
+	if values := encoder.Decode(codes[2]); len(values) != 2 || values[0] != "DE" || values[1] != "mobile" {
+		t.Errorf("unexpected decoded interaction %v", values)
+	}
+	if encoder.Encode("a b", "c") == encoder.Encode("a", "b c") {
+		t.Error("different interactions shared a code")
+	}
+
+	if _, err := encoder.EncodeColumns([]string{"a"}, []string{}); err != ErrLength {
+		t.Error("expected length error")
+	}
+
+	hashed := NewCross(8)
+	if code := hashed.Encode("US", "mobile"); code >= 8 || code != hashed.Encode("US", "mobile") {
+		t.Error("hashed interaction code is not stable and in range")
+	}
+	if hashed.Decode(0) != nil {
+		t.Error("hashed interactions should not decode")
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// csvIntegrity begins the comment line that records the
+// number of rows and their checksum at the end of a CSV
+// written in a dialect with comments.
+const csvIntegrity = "integrity"
+
+// CSVDialect configures the CSV written by MarshalCSVDialect
+// and read by UnmarshalCSVDialect. The zero value is the
+// dialect of MarshalCSV: comma separated, with a header row
+// naming the columns "value" and "code".
+type CSVDialect struct {
+	// Comma is the field delimiter, e.g. '\t' for TSV.
+	// Defaults to ','.
+	Comma rune
+	// Comment, if not 0, is the character beginning
+	// lines that are ignored when reading. A comment
+	// recording the number of rows and their checksum
+	// is written after the rows, and checked when read.
+	Comment rune
+	// NoHeader is whether the header row is omitted.
+	// Without a header the value is read from the
+	// first column and the code from the second.
+	NoHeader bool
+	// ValueColumn and CodeColumn are the names of the
+	// columns in the header. When reading, the columns
+	// are found by name so they may be in any order.
+	// Default to "value" and "code".
+	ValueColumn string
+	CodeColumn  string
+	// QuoteAll is whether every field is quoted when
+	// writing, instead of only fields that need it.
+	QuoteAll bool
+	// LazyQuotes is whether quotes may appear in
+	// unquoted fields when reading.
+	LazyQuotes bool
+	// UseCRLF is whether lines end with \r\n
+	// instead of \n when writing.
+	UseCRLF bool
+}
+
+// TSV is the dialect of tab separated files.
+var TSV = CSVDialect{Comma: '\t'}
+
+func (d CSVDialect) comma() rune {
+	if d.Comma == 0 {
+		return ','
+	}
+
+	return d.Comma
+}
+
+func (d CSVDialect) columns() []string {
+	value, code := d.ValueColumn, d.CodeColumn
+	if value == "" {
+		value = "value"
+	}
+	if code == "" {
+		code = "code"
+	}
+
+	return []string{value, code}
+}
+
+// write will encode the rows of values and codes,
+// preceded by the header unless it is omitted and
+// followed by their integrity record in dialects
+// with comments.
+func (d CSVDialect) write(rows [][]string) ([]byte, error) {
+	data, err := d.writeRows(rows)
+	if err != nil || d.Comment == 0 {
+		return data, err
+	}
+
+	integrity := newRowsIntegrity()
+	for _, row := range rows {
+		integrity.add(row...)
+	}
+	record := integrity.record()
+	line := fmt.Sprintf("%c%s %d %d%s", d.Comment, csvIntegrity, record.Count, record.Checksum, d.newline())
+	return append(data, line...), nil
+}
+
+func (d CSVDialect) newline() string {
+	if d.UseCRLF {
+		return "\r\n"
+	}
+
+	return "\n"
+}
+
+func (d CSVDialect) writeRows(rows [][]string) ([]byte, error) {
+	if !d.NoHeader {
+		rows = append([][]string{d.columns()}, rows...)
+	}
+
+	var b bytes.Buffer
+	if !d.QuoteAll {
+		w := csv.NewWriter(&b)
+		w.Comma = d.comma()
+		w.UseCRLF = d.UseCRLF
+		err := w.WriteAll(rows)
+		if err != nil {
+			return []byte{}, err
+		}
+
+		return b.Bytes(), nil
+	}
+
+	newline := d.newline()
+	for _, row := range rows {
+		for i, field := range row {
+			if i > 0 {
+				b.WriteRune(d.comma())
+			}
+			b.WriteString(`"` + strings.Replace(field, `"`, `""`, -1) + `"`)
+		}
+		b.WriteString(newline)
+	}
+
+	return b.Bytes(), nil
+}
+
+// read will decode the rows of the CSV as pairs of value
+// and code. An `ErrFormat` error is returned if the header
+// does not name both columns or a row is too short, and an
+// `ErrCorruptSnapshot` error if the rows do not match their
+// integrity record.
+func (d CSVDialect) read(data []byte) ([][]string, error) {
+	rows, err := d.readRows(data)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := d.integrity(data)
+	if err != nil || record == nil {
+		return rows, err
+	}
+
+	integrity := newRowsIntegrity()
+	for _, row := range rows {
+		integrity.add(row...)
+	}
+	if !integrity.matches(record) {
+		return nil, ErrCorruptSnapshot
+	}
+
+	return rows, nil
+}
+
+// integrity will return the integrity record ending the
+// CSV, or nil if it has none, as is the case in dialects
+// without comments. An `ErrCorruptSnapshot` error is
+// returned if the record cannot be read.
+func (d CSVDialect) integrity(data []byte) (*snapshotIntegrity, error) {
+	if d.Comment == 0 {
+		return nil, nil
+	}
+
+	data = bytes.TrimRight(data, "\r\n")
+	line := string(data[bytes.LastIndexByte(data, '\n')+1:])
+	prefix := string(d.Comment) + csvIntegrity + " "
+	if !strings.HasPrefix(line, prefix) {
+		return nil, nil
+	}
+
+	var record snapshotIntegrity
+	_, err := fmt.Sscanf(line[len(prefix):], "%d %d", &record.Count, &record.Checksum)
+	if err != nil {
+		return nil, ErrCorruptSnapshot
+	}
+
+	return &record, nil
+}
+
+func (d CSVDialect) readRows(data []byte) ([][]string, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = d.comma()
+	r.Comment = d.Comment
+	r.LazyQuotes = d.LazyQuotes
+	r.FieldsPerRecord = -1
+
+	valueIndex, codeIndex := 0, 1
+	if !d.NoHeader {
+		header, err := r.Read()
+		if err == io.EOF {
+			return [][]string{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		valueIndex, codeIndex = -1, -1
+		columns := d.columns()
+		for i, name := range header {
+			switch name {
+			case columns[0]:
+				valueIndex = i
+			case columns[1]:
+				codeIndex = i
+			}
+		}
+		if valueIndex < 0 || codeIndex < 0 {
+			return nil, ErrFormat
+		}
+	}
+
+	rows := make([][]string, 0)
+	for {
+		line, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(line) <= valueIndex || len(line) <= codeIndex {
+			return nil, ErrFormat
+		}
+
+		rows = append(rows, []string{line[valueIndex], line[codeIndex]})
+	}
+
+	return rows, nil
+}
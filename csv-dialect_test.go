@@ -0,0 +1,61 @@
+package encoder
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCSVDialect(t *testing.T) {
+	encoder := NewOrdinal(true)
+	encoder.EncodeSlice([]string{"red", `say "hi"`})
+
+	dialect := CSVDialect{Comma: '\t', QuoteAll: true, ValueColumn: "token", CodeColumn: "id"}
+	data, err := encoder.MarshalCSVDialect(dialect)
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	if !strings.HasPrefix(string(data), "\"token\"\t\"id\"\n\"\"\t\"0\"\n") {
+		t.Errorf("unexpected dialect output %q", data)
+	}
+
+	loaded := NewOrdinal(false)
+	if err := loaded.UnmarshalCSVDialect(data, dialect); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	if loaded.Decode(2) != `say "hi"` || loaded.Length() != 3 {
+		t.Error("dialect did not round trip")
+	}
+
+	reordered := NewOrdinal(false)
+	if err := reordered.UnmarshalCSVDialect([]byte("id\ttoken\n0\tblue\n"), dialect); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	if reordered.Decode(0) != "blue" {
+		t.Error("columns were not found by name")
+	}
+
+	headless := NewOneHot()
+	if err := headless.UnmarshalCSVDialect([]byte("\t1\nred\t2\n"), CSVDialect{Comma: '\t', NoHeader: true}); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	if col, ok := headless.Index("red"); !ok || col != 1 {
+		t.Error("headless rows were not read")
+	}
+
+	if err := NewOrdinal(false).UnmarshalCSVDialect(data, TSV); err != ErrFormat {
+		t.Error("expected format error for unknown columns")
+	}
+
+	commented := CSVDialect{Comment: '#'}
+	data, err = encoder.MarshalCSVDialect(commented)
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	if err := NewOrdinal(false).UnmarshalCSVDialect(data, commented); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	truncated := strings.Replace(string(data), "red,1\n", "", 1)
+	if err := NewOrdinal(false).UnmarshalCSVDialect([]byte(truncated), commented); err != ErrCorruptSnapshot {
+		t.Errorf("expected corrupt snapshot error for a missing row, got %v", err)
+	}
+}
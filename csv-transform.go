@@ -0,0 +1,196 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+const defaultChunkSize = 1024
+
+// CSVSpec configures how TransformCSV encodes a CSV stream.
+type CSVSpec struct {
+	// Columns maps the index of every column to
+	// encode to the encoder applied to it. Columns
+	// without an encoder are copied unchanged.
+	// OneHot encoders are frozen for the stream so
+	// that every row has the same width: values
+	// they have not seen are encoded as all zeros.
+	Columns map[int]Transformer
+	// Header is whether the first row is a header.
+	// The names of encoded columns are replaced by
+	// the encoder's feature names when it has them.
+	Header bool
+	// Comma is the field delimiter. Defaults to ','.
+	Comma rune
+	// ChunkSize is the number of rows read, encoded
+	// and written at a time. Defaults to 1024.
+	ChunkSize int
+}
+
+// TransformCSV will read CSV rows from `r`, replace the fields of
+// every column in the spec with their encoded features, and write
+// the rows to `w`.
+// Reading, encoding and writing run concurrently on chunks of rows,
+// with at most a few chunks in memory at once, so arbitrarily large
+// files can be transformed. Rows are encoded in order, so encoders
+// that assign codes as values are seen assign the same codes as
+// they would encoding the file sequentially.
+func TransformCSV(ctx context.Context, r io.Reader, w io.Writer, spec CSVSpec) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	chunkSize := spec.ChunkSize
+	if chunkSize < 1 {
+		chunkSize = defaultChunkSize
+	}
+
+	reader := csv.NewReader(r)
+	writer := csv.NewWriter(w)
+	if spec.Comma != 0 {
+		reader.Comma = spec.Comma
+		writer.Comma = spec.Comma
+	}
+	reader.FieldsPerRecord = -1
+
+	columns := fixedWidth(spec.Columns)
+
+	read := make(chan [][]string, 1)
+	encoded := make(chan [][]string, 1)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(read)
+		for {
+			chunk := make([][]string, 0, chunkSize)
+			for len(chunk) < chunkSize {
+				row, err := reader.Read()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+				chunk = append(chunk, row)
+			}
+			if len(chunk) == 0 {
+				return
+			}
+
+			select {
+			case read <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		defer close(encoded)
+		header := spec.Header
+		for chunk := range read {
+			for i, row := range chunk {
+				if header {
+					header = false
+					chunk[i] = encodeHeader(row, columns)
+					continue
+				}
+				chunk[i] = encodeRow(row, columns)
+			}
+
+			select {
+			case encoded <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for chunk := range encoded {
+		err := writer.WriteAll(chunk)
+		if err != nil {
+			return err
+		}
+	}
+
+	select {
+	case err := <-errs:
+		return err
+	default:
+	}
+
+	return ctx.Err()
+}
+
+// featureNamer is implemented by encoders that
+// name each of the features of their encoding.
+type featureNamer interface {
+	FeatureNames(column string) []string
+}
+
+// fixedWidth will replace every OneHot encoder with a
+// frozen snapshot, so that its codewords cannot grow
+// while the stream is being transformed.
+func fixedWidth(columns map[int]Transformer) map[int]Transformer {
+	fixed := make(map[int]Transformer, len(columns))
+	for i, e := range columns {
+		if onehot, ok := e.(*OneHot); ok {
+			e = onehot.Snapshot()
+		}
+		fixed[i] = e
+	}
+
+	return fixed
+}
+
+// encodeHeader will replace the name of every column
+// with an encoder that names its features with them.
+func encodeHeader(row []string, columns map[int]Transformer) []string {
+	out := make([]string, 0, len(row))
+	for i, field := range row {
+		e, ok := columns[i].(featureNamer)
+		if !ok {
+			out = append(out, field)
+			continue
+		}
+
+		out = append(out, e.FeatureNames(field)...)
+	}
+
+	return out
+}
+
+// encodeRow will replace every field with an encoder
+// with the formatted features of its encoding.
+func encodeRow(row []string, columns map[int]Transformer) []string {
+	out := make([]string, 0, len(row))
+	for i, field := range row {
+		e, ok := columns[i]
+		if !ok {
+			out = append(out, field)
+			continue
+		}
+
+		for _, v := range e.Transform(field) {
+			out = append(out, strconv.FormatFloat(v, 'g', -1, 64))
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,61 @@
+package encoder
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTransformCSV(t *testing.T) {
+	input := "id,color,size\n1,red,s\n2,green,m\n3,red,l\n"
+
+	var output bytes.Buffer
+	err := TransformCSV(context.Background(), strings.NewReader(input), &output, CSVSpec{
+		Columns:   map[int]Transformer{1: NewOrdinal(false)},
+		Header:    true,
+		ChunkSize: 2,
+	})
+	if err != nil {
+		t.Fatalf("transform error: %+v", err)
+	}
+
+	expected := "id,color,size\n1,0,s\n2,1,m\n3,0,l\n"
+	if output.String() != expected {
+		t.Errorf("output was %q and not %q", output.String(), expected)
+	}
+}
+
+func TestTransformCSVCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var output bytes.Buffer
+	err := TransformCSV(ctx, strings.NewReader("a\nb\n"), &output, CSVSpec{})
+	if err != context.Canceled {
+		t.Errorf("error was %+v and not context canceled", err)
+	}
+}
+
+func TestTransformCSVOneHotWidth(t *testing.T) {
+	encoder := NewOneHot()
+	encoder.Encode("red")
+	input := "color\nred\ngreen\n"
+
+	var output bytes.Buffer
+	err := TransformCSV(context.Background(), strings.NewReader(input), &output, CSVSpec{
+		Columns: map[int]Transformer{0: encoder},
+		Header:  true,
+	})
+	if err != nil {
+		t.Fatalf("transform error: %+v", err)
+	}
+
+	expected := "color=,color=red\n0,1\n0,0\n"
+	if output.String() != expected {
+		t.Errorf("output was %q and not %q", output.String(), expected)
+	}
+	if encoder.Contains("green") {
+		t.Errorf("unseen value was added to the encoder")
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import "strconv"
+
+// Dataset will encode rows of string columns into a numeric
+// matrix, applying an encoder to every column and expanding
+// one-hot columns into one feature per value.
+type Dataset struct {
+	columns []*column
+	offsets []int
+	width   int
+}
+
+// Fit will fit an encoder of the kind given by `spec` to every
+// column of the rows; `spec` has one kind per column and columns
+// whose kind is empty are dropped. An `ErrLength` error is returned
+// if a row does not have one value per kind, and an `ErrUnsupported`
+// error for an unknown kind.
+func (d *Dataset) Fit(rows [][]string, spec []ColumnKind) error {
+	for _, row := range rows {
+		if len(row) != len(spec) {
+			return ErrLength
+		}
+	}
+
+	columns := make([]*column, len(spec), len(spec))
+	offsets := make([]int, len(spec), len(spec))
+	var width int
+	for j, kind := range spec {
+		offsets[j] = width
+		if kind == "" {
+			continue
+		}
+
+		values := make([]string, len(rows), len(rows))
+		for i, row := range rows {
+			values[i] = row[j]
+		}
+
+		c, err := fitColumn(kind, values)
+		if err != nil {
+			return err
+		}
+		columns[j] = c
+		width += c.dimension()
+	}
+
+	d.columns, d.offsets, d.width = columns, offsets, width
+	return nil
+}
+
+// Transform will return the matrix of features of the rows, one
+// row of `Dimension()` features per row. An `ErrLength` error is
+// returned if a row does not have one value per fitted column.
+func (d *Dataset) Transform(rows [][]string) ([][]float64, error) {
+	matrix := make([][]float64, len(rows), len(rows))
+	for i, row := range rows {
+		features, err := d.AppendRow(make([]float64, 0, d.width), row)
+		if err != nil {
+			return [][]float64{}, err
+		}
+		matrix[i] = features
+	}
+
+	return matrix, nil
+}
+
+// AppendRow will append the features of the row to `dst` and
+// return the extended slice, so that rows can be written into
+// existing storage, such as the rows of a matrix, without
+// allocating. An `ErrLength` error is returned if the row does
+// not have one value per fitted column.
+func (d *Dataset) AppendRow(dst []float64, row []string) ([]float64, error) {
+	if len(row) != len(d.columns) {
+		return dst, ErrLength
+	}
+
+	for j, c := range d.columns {
+		if c != nil {
+			dst = c.transform(dst, row[j])
+		}
+	}
+
+	return dst, nil
+}
+
+// Dimension will return the number of features of every row.
+func (d *Dataset) Dimension() int {
+	return d.width
+}
+
+// Span will return the first feature of the given column and
+// its number of features, which is 0 for a dropped column. An
+// `ErrBounds` error is returned if there is no such column.
+func (d *Dataset) Span(column int) (int, int, error) {
+	if column < 0 || column >= len(d.columns) {
+		return 0, 0, ErrBounds
+	}
+	if d.columns[column] == nil {
+		return d.offsets[column], 0, nil
+	}
+
+	return d.offsets[column], d.columns[column].dimension(), nil
+}
+
+// FeatureNames will return a name for every feature, in order,
+// using the names of the columns in the header: `column=value`
+// for every value of a one-hot column and the name of the column
+// otherwise. Columns without a name in the header are named by
+// their index.
+func (d *Dataset) FeatureNames(header []string) []string {
+	names := make([]string, 0, d.width)
+	for j, c := range d.columns {
+		if c == nil {
+			continue
+		}
+
+		name := strconv.Itoa(j)
+		if j < len(header) {
+			name = header[j]
+		}
+		names = append(names, c.featureNames(name)...)
+	}
+
+	return names
+}
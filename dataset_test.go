@@ -0,0 +1,58 @@
+package encoder
+
+import "testing"
+
+func TestDataset(t *testing.T) {
+	rows := [][]string{
+		{"1", "US", "20"},
+		{"2", "FR", "40"},
+		{"3", "US", "30"},
+	}
+
+	var d Dataset
+	if err := d.Fit(rows, []ColumnKind{"", KindOneHot, KindNumeric}); err != nil {
+		t.Fatalf("fit error: %+v", err)
+	}
+
+	if d.Dimension() != 4 {
+		t.Errorf("dimension was %d and not 4", d.Dimension())
+	}
+	if start, width, _ := d.Span(1); start != 0 || width != 3 {
+		t.Errorf("one-hot column spans %d features from %d", width, start)
+	}
+	if start, width, _ := d.Span(2); start != 3 || width != 1 {
+		t.Errorf("numeric column spans %d features from %d", width, start)
+	}
+	if _, _, err := d.Span(3); err != ErrBounds {
+		t.Error("expected bounds error")
+	}
+
+	names := d.FeatureNames([]string{"id", "country"})
+	expected := []string{"country=", "country=US", "country=FR", "2"}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("feature names were %v and not %v", names, expected)
+		}
+	}
+
+	matrix, err := d.Transform([][]string{{"4", "FR", "50"}, {"5", "DE", "10"}})
+	if err != nil {
+		t.Fatalf("transform error: %+v", err)
+	}
+	want := [][]float64{{0, 0, 1, 50}, {0, 0, 0, 10}}
+	for i := range want {
+		for j := range want[i] {
+			if matrix[i][j] != want[i][j] {
+				t.Errorf("row %d was %v and not %v", i, matrix[i], want[i])
+				break
+			}
+		}
+	}
+
+	if _, err := d.Transform([][]string{{"1"}}); err != ErrLength {
+		t.Error("expected length error")
+	}
+	if err := d.Fit(rows, []ColumnKind{KindOrdinal}); err != ErrLength {
+		t.Error("expected length error")
+	}
+}
@@ -0,0 +1,311 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// delta is the serialized form of the changes to an
+// encoder since a version: the values of every code from
+// `start`, the number of codes at that version, and the
+// codes below it that have since expired or, if they were
+// reserved, been assigned a value. Codes that hold no value
+// are serialized as the empty string and listed by the kind
+// of placeholder they are.
+type delta struct {
+	Since    uint64            `json:"since"`
+	Version  uint64            `json:"version"`
+	Start    uint64            `json:"start"`
+	Values   []string          `json:"values"`
+	Missing  bool              `json:"missing,omitempty"`
+	Reserved []uint64          `json:"reserved,omitempty"`
+	Expired  []uint64          `json:"expired,omitempty"`
+	Aliases  map[string]string `json:"aliases,omitempty"`
+	Assigned []jsonlRow        `json:"assigned,omitempty"`
+}
+
+// change is a change to an encoder that adds no code,
+// such as a code expiring, and the version it made.
+type change struct {
+	version uint64
+	code    uint64
+}
+
+// Version will return the version of the encoder's
+// vocabulary, which advances with every change to it: the
+// number of codes plus the number of codes that have expired
+// or, if they were reserved, been assigned a value.
+// The version is serialized with the encoder's JSON and Gob
+// snapshots.
+func (e *Ordinal) Version() uint64 {
+	e.RLock()
+	defer e.RUnlock()
+
+	return e.version()
+}
+
+func (e *Ordinal) version() uint64 {
+	return uint64(e.decoder.len()) + e.changes
+}
+
+// changed will record a change to the code
+// that advances the version without adding a code.
+func (e *Ordinal) changed(code uint64) {
+	e.changes++
+	e.history = append(e.history, change{version: e.version(), code: code})
+}
+
+// lengthAt will return the number of codes the encoder had at
+// the given version, or false if the version is from before
+// the history of changes the encoder keeps, which begins when
+// it is created, loaded or brought up to date with a delta.
+func (e *Ordinal) lengthAt(version uint64) (uint64, bool) {
+	if version < e.historyStart || version > e.version() {
+		return 0, false
+	}
+
+	n := sort.Search(len(e.history), func(i int) bool {
+		return e.history[i].version > version
+	})
+	changes := e.changes - uint64(len(e.history)-n)
+	return version - changes, true
+}
+
+// MarshalDelta will serialize only the values added
+// to the encoder since the given version, so that
+// replicas at that version can be brought up to date
+// with ApplyDelta without shipping a full snapshot.
+// Reserved and expired codes are carried as such, as are
+// codes that have expired or been assigned to a value since
+// the version, and every alias is included. A delta since a
+// version from before the history the encoder keeps holds
+// every value.
+// An `ErrDelta` error is returned if the version is ahead
+// of the encoder.
+func (e *Ordinal) MarshalDelta(sinceVersion uint64) ([]byte, error) {
+	e.RLock()
+	defer e.RUnlock()
+
+	if sinceVersion > e.version() {
+		return []byte{}, ErrDelta
+	}
+	start, ok := e.lengthAt(sinceVersion)
+
+	d := delta{
+		Since:   sinceVersion,
+		Version: e.version(),
+		Start:   start,
+		Values:  make([]string, 0, uint64(e.decoder.len())-start),
+	}
+	for code := int(start); code < e.decoder.len(); code++ {
+		d.Values = append(d.Values, e.decoder.get(code))
+		switch {
+		case e.missing != nil && uint64(code) == MissingCode:
+			d.Missing = true
+		case e.reserved[uint64(code)]:
+			d.Reserved = append(d.Reserved, uint64(code))
+		case e.expired[uint64(code)]:
+			d.Expired = append(d.Expired, uint64(code))
+		}
+	}
+	if len(e.aliases) > 0 {
+		d.Aliases = e.aliases
+	}
+
+	if ok {
+		e.deltaChanges(&d)
+	}
+
+	return json.Marshal(d)
+}
+
+// deltaChanges will add the codes below the start of the
+// delta that changed since its version, in their current
+// state.
+func (e *Ordinal) deltaChanges(d *delta) {
+	seen := make(map[uint64]bool)
+	for i := len(e.history) - 1; i >= 0 && e.history[i].version > d.Since; i-- {
+		code := e.history[i].code
+		if code >= d.Start || seen[code] {
+			continue
+		}
+		seen[code] = true
+
+		if e.expired[code] {
+			d.Expired = append(d.Expired, code)
+		} else {
+			d.Assigned = append(d.Assigned, jsonlRow{Value: e.decoder.get(int(code)), Code: code})
+		}
+	}
+	sort.Slice(d.Expired, func(i, j int) bool { return d.Expired[i] < d.Expired[j] })
+	sort.Slice(d.Assigned, func(i, j int) bool { return d.Assigned[i].Code < d.Assigned[j].Code })
+}
+
+// ApplyDelta will add the values serialized by MarshalDelta
+// to the encoder, with the same codes they have in the source.
+// A delta can be applied to an encoder at any version between
+// the version it was taken since and the version it brings the
+// encoder to; values the encoder already has are checked rather
+// than added. Reserved and expired codes are added as such,
+// codes the delta has expired are expired, reserved codes the
+// delta has assigned are assigned, and aliases are added for
+// canonical values the encoder has.
+// The encoder is then at the version of the delta.
+// An `ErrDelta` error is returned, and the encoder left
+// unchanged, if the delta does not continue the encoder's
+// vocabulary or one of its aliases has a different code.
+func (e *Ordinal) ApplyDelta(data []byte) error {
+	var d delta
+	err := json.Unmarshal(data, &d)
+	if err != nil {
+		return err
+	}
+
+	e.lock()
+	defer e.Unlock()
+
+	length := uint64(e.decoder.len())
+	end := d.Start + uint64(len(d.Values))
+	if d.Start > length || d.Version < end {
+		return ErrDelta
+	}
+
+	reserved := codeSet(d.Reserved)
+	expired := codeSet(d.Expired)
+	assigned := make([]jsonlRow, 0, len(d.Assigned))
+	for _, row := range d.Assigned {
+		if row.Code >= d.Start || !e.canAssign(row.Value, row.Code) {
+			return ErrDelta
+		}
+		assigned = append(assigned, row)
+	}
+	for code := range expired {
+		if code >= end {
+			return ErrDelta
+		}
+	}
+	for i, v := range d.Values {
+		code := d.Start + uint64(i)
+		switch {
+		case expired[code], d.Missing && code == MissingCode:
+			// placeholders have no value to check
+		case reserved[code]:
+			if code < length && !e.reserved[code] {
+				return ErrDelta
+			}
+		case code < length:
+			if !e.canAssign(v, code) {
+				return ErrDelta
+			}
+			assigned = append(assigned, jsonlRow{Value: v, Code: code})
+		case e.contains(v):
+			return ErrDelta
+		}
+	}
+	for alias, canonical := range d.Aliases {
+		code, ok := e.encoder[hashString(alias)]
+		if ok && e.decoder.get(int(code)) != canonical {
+			return ErrDelta
+		}
+	}
+
+	removed := make(map[uint64]bool)
+	for code := range expired {
+		if code < length && !e.expired[code] {
+			removed[code] = true
+		}
+	}
+	e.expire(removed)
+	for _, row := range assigned {
+		if e.reserved[row.Code] {
+			e.assign(row.Value, row.Code)
+		}
+	}
+
+	for i := length - d.Start; i < uint64(len(d.Values)); i++ {
+		code := d.Start + i
+		switch {
+		case d.Missing && code == MissingCode:
+			e.decoder.append("")
+			if e.missing != nil {
+				e.encoder[missingHash] = MissingCode
+			}
+		case reserved[code]:
+			e.reserve(code, code+1)
+		case expired[code]:
+			e.decoder.append("")
+			if e.expired == nil {
+				e.expired = make(map[uint64]bool)
+			}
+			e.expired[code] = true
+		default:
+			e.encode(d.Values[i])
+		}
+	}
+
+	for alias, canonical := range d.Aliases {
+		code, ok := e.encoder[hashString(canonical)]
+		if !ok {
+			continue
+		}
+
+		if e.aliases == nil {
+			e.aliases = make(map[string]string)
+		}
+		e.aliases[alias] = canonical
+		e.encoder[hashString(alias)] = code
+	}
+
+	if d.Version >= e.version() {
+		e.changes = d.Version - uint64(e.decoder.len())
+		e.history, e.historyStart = nil, d.Version
+	}
+
+	return nil
+}
+
+// canAssign will return whether the code, which the encoder
+// has, holds the value or is reserved and can be assigned it.
+func (e *Ordinal) canAssign(value string, code uint64) bool {
+	if e.reserved[code] {
+		return !e.contains(value)
+	}
+
+	return !e.expired[code] && e.decoder.get(int(code)) == value
+}
+
+// codeSet will return the codes as a set.
+func codeSet(codes []uint64) map[uint64]bool {
+	set := make(map[uint64]bool, len(codes))
+	for _, code := range codes {
+		set[code] = true
+	}
+
+	return set
+}
+
+// sortedCodes will return the codes of the set
+// in ascending order.
+func sortedCodes(set map[uint64]bool) []uint64 {
+	codes := make([]uint64, 0, len(set))
+	for code := range set {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+
+	return codes
+}
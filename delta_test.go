@@ -0,0 +1,117 @@
+package encoder
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOrdinalDelta(t *testing.T) {
+	source := NewOrdinal(true)
+	source.Encode("red")
+
+	replica := source.Clone()
+	version := replica.Version()
+
+	source.Encode("green")
+	source.Encode("blue")
+
+	data, err := source.MarshalDelta(version)
+	if err != nil {
+		t.Fatalf("marshal delta error: %+v", err)
+	}
+
+	err = replica.ApplyDelta(data)
+	if err != nil {
+		t.Fatalf("apply delta error: %+v", err)
+	}
+	if replica.Fingerprint() != source.Fingerprint() {
+		t.Error("replica did not match source after applying delta")
+	}
+
+	// applying the same delta again is a no-op
+	err = replica.ApplyDelta(data)
+	if err != nil || replica.Version() != source.Version() {
+		t.Errorf("reapplying delta error: %+v", err)
+	}
+
+	diverged := NewOrdinal(true)
+	diverged.Encode("purple")
+	diverged.Encode("yellow")
+	if diverged.ApplyDelta(data) != ErrDelta {
+		t.Error("expected delta error")
+	}
+}
+
+func TestOrdinalDeltaPlaceholders(t *testing.T) {
+	source := NewOrdinal(true)
+	replica := source.Clone()
+	version := replica.Version()
+
+	source.Encode("red")
+	err := source.Reserve(2, 4)
+	if err != nil {
+		t.Fatalf("reserve error: %+v", err)
+	}
+	source.Encode("green")
+	err = source.Alias("red", "crimson")
+	if err != nil {
+		t.Fatalf("alias error: %+v", err)
+	}
+
+	data, err := source.MarshalDelta(version)
+	if err != nil {
+		t.Fatalf("marshal delta error: %+v", err)
+	}
+
+	err = replica.ApplyDelta(data)
+	if err != nil {
+		t.Fatalf("apply delta error: %+v", err)
+	}
+	if replica.Encode("green") != 4 || replica.Encode("crimson") != 1 {
+		t.Errorf("replica codes did not match the source")
+	}
+	if !replica.IsReserved(2) || !replica.IsReserved(3) {
+		t.Errorf("reserved codes were not carried by the delta")
+	}
+}
+
+func TestOrdinalDeltaChanges(t *testing.T) {
+	source := NewOrdinal(false, WithTTL(20*time.Millisecond))
+	source.Encode("stale")
+	if err := source.Reserve(1, 3); err != nil {
+		t.Fatalf("reserve error: %+v", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	source.Encode("fresh")
+
+	replica := source.Clone()
+	version := replica.Version()
+
+	if err := source.AssignCode("UNK", 2); err != nil {
+		t.Fatalf("assign error: %+v", err)
+	}
+	if expired := source.Sweep(); len(expired) != 1 || expired[0] != 0 {
+		t.Fatalf("expired codes were %v and not [0]", expired)
+	}
+	if source.Version() != version+2 {
+		t.Errorf("version was %d and not %d", source.Version(), version+2)
+	}
+
+	data, err := source.MarshalDelta(version)
+	if err != nil {
+		t.Fatalf("marshal delta error: %+v", err)
+	}
+	err = replica.ApplyDelta(data)
+	if err != nil {
+		t.Fatalf("apply delta error: %+v", err)
+	}
+	if replica.Contains("stale") || replica.Encode("UNK") != 2 || replica.IsReserved(2) {
+		t.Error("changes below the delta's start were not applied")
+	}
+	if replica.Version() != source.Version() {
+		t.Errorf("replica version was %d and not %d", replica.Version(), source.Version())
+	}
+	if err := replica.ApplyDelta(data); err != nil {
+		t.Errorf("reapplying delta error: %+v", err)
+	}
+}
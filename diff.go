@@ -0,0 +1,121 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Report lists the changes between two versions of an encoder's
+// vocabulary, for review when refreshing encoder artifacts.
+// Categories are listed in order of their codes.
+type Report struct {
+	Added    []CategoryCode  `json:"added"`
+	Removed  []CategoryCode  `json:"removed"`
+	Remapped []CategoryRemap `json:"remapped"`
+	// Unchanged is the number of categories
+	// with the same code in both versions.
+	Unchanged int `json:"unchanged"`
+}
+
+// CategoryCode is a category and its code.
+type CategoryCode struct {
+	Value string `json:"value"`
+	Code  uint64 `json:"code"`
+}
+
+// CategoryRemap is a category whose code has changed.
+type CategoryRemap struct {
+	Value   string `json:"value"`
+	OldCode uint64 `json:"oldCode"`
+	NewCode uint64 `json:"newCode"`
+}
+
+// DiffReport will compare the vocabularies of two versions of an
+// encoder, listing the categories only in the new version, those
+// only in the old version and those whose code has changed.
+// Neither encoder is modified.
+func DiffReport(old, new OrdinalEncoder) Report {
+	oldCodes, newCodes := codesOf(old), codesOf(new)
+
+	r := Report{
+		Added:    []CategoryCode{},
+		Removed:  []CategoryCode{},
+		Remapped: []CategoryRemap{},
+	}
+	for v, code := range newCodes {
+		oldCode, ok := oldCodes[v]
+		switch {
+		case !ok:
+			r.Added = append(r.Added, CategoryCode{Value: v, Code: code})
+		case oldCode != code:
+			r.Remapped = append(r.Remapped, CategoryRemap{Value: v, OldCode: oldCode, NewCode: code})
+		default:
+			r.Unchanged++
+		}
+	}
+	for v, code := range oldCodes {
+		if _, ok := newCodes[v]; !ok {
+			r.Removed = append(r.Removed, CategoryCode{Value: v, Code: code})
+		}
+	}
+
+	sort.Slice(r.Added, func(i, j int) bool { return r.Added[i].Code < r.Added[j].Code })
+	sort.Slice(r.Removed, func(i, j int) bool { return r.Removed[i].Code < r.Removed[j].Code })
+	sort.Slice(r.Remapped, func(i, j int) bool { return r.Remapped[i].OldCode < r.Remapped[j].OldCode })
+
+	return r
+}
+
+// codesOf will return the first code of every value.
+func codesOf(e OrdinalEncoder) map[string]uint64 {
+	codes := make(map[string]uint64, e.Length())
+	for code := 0; code < e.Length(); code++ {
+		v := e.Decode(uint64(code))
+		if _, ok := codes[v]; !ok {
+			codes[v] = uint64(code)
+		}
+	}
+
+	return codes
+}
+
+// Empty will return whether or not the
+// versions have the same vocabulary.
+func (r Report) Empty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Remapped) == 0
+}
+
+// String will render the report as text: a summary line
+// followed by a line per change, `+` for added, `-` for
+// removed and `~` for remapped categories.
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d added, %d removed, %d remapped, %d unchanged\n",
+		len(r.Added), len(r.Removed), len(r.Remapped), r.Unchanged)
+
+	for _, c := range r.Added {
+		fmt.Fprintf(&b, "+ %q %d\n", c.Value, c.Code)
+	}
+	for _, c := range r.Removed {
+		fmt.Fprintf(&b, "- %q %d\n", c.Value, c.Code)
+	}
+	for _, c := range r.Remapped {
+		fmt.Fprintf(&b, "~ %q %d -> %d\n", c.Value, c.OldCode, c.NewCode)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,45 @@
+package encoder
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiffReport(t *testing.T) {
+	old := NewOrdinal(true)
+	old.EncodeSlice([]string{"red", "green", "blue"})
+	new := NewOrdinal(true)
+	new.EncodeSlice([]string{"red", "blue", "yellow"})
+
+	r := DiffReport(old, new)
+	if len(r.Added) != 1 || r.Added[0] != (CategoryCode{Value: "yellow", Code: 3}) {
+		t.Errorf("unexpected added categories %v", r.Added)
+	}
+	if len(r.Removed) != 1 || r.Removed[0] != (CategoryCode{Value: "green", Code: 2}) {
+		t.Errorf("unexpected removed categories %v", r.Removed)
+	}
+	if len(r.Remapped) != 1 || r.Remapped[0] != (CategoryRemap{Value: "blue", OldCode: 3, NewCode: 2}) {
+		t.Errorf("unexpected remapped categories %v", r.Remapped)
+	}
+	if r.Unchanged != 2 || r.Empty() {
+		t.Error("unexpected unchanged count")
+	}
+
+	expected := "1 added, 1 removed, 1 remapped, 2 unchanged\n+ \"yellow\" 3\n- \"green\" 2\n~ \"blue\" 3 -> 2\n"
+	if r.String() != expected {
+		t.Errorf("unexpected text report %q", r.String())
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(b, &decoded); err != nil || decoded.Added[0].Value != "yellow" {
+		t.Error("report did not round trip through JSON")
+	}
+
+	if !DiffReport(old, old.Clone()).Empty() {
+		t.Error("clone differs from its encoder")
+	}
+}
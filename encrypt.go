@@ -0,0 +1,104 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+	"io/ioutil"
+)
+
+// SaveEncrypted will write the gob snapshot of the encoder to
+// `w` encrypted with AES-GCM under the given key, which must be
+// 16, 24 or 32 bytes long (AES-128, AES-192 or AES-256).
+// A random nonce is generated for every snapshot and written
+// before the ciphertext.
+func (e *Ordinal) SaveEncrypted(w io.Writer, key []byte) error {
+	data, err := e.GobEncode()
+	if err != nil {
+		return err
+	}
+
+	data, err = encrypt(key, data)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+// LoadEncrypted will replace the contents of the encoder with
+// the snapshot read from `r`, decrypted with the given key.
+// An `ErrDecryption` error is returned if the key is wrong or
+// the snapshot has been modified.
+func (e *Ordinal) LoadEncrypted(r io.Reader, key []byte) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	data, err = decrypt(key, data)
+	if err != nil {
+		return err
+	}
+
+	return e.GobDecode(data)
+}
+
+func encrypt(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(plaintext)+gcm.Overhead())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func decrypt(key, ciphertext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return []byte{}, ErrDecryption
+	}
+
+	nonce := ciphertext[:gcm.NonceSize()]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext[gcm.NonceSize():], nil)
+	if err != nil {
+		return []byte{}, ErrDecryption
+	}
+
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,40 @@
+package encoder
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestOrdinalEncrypted(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+
+	encoder := NewOrdinal(false)
+	value := "alice@example.com"
+	code := encoder.Encode(value)
+
+	var buf bytes.Buffer
+	err := encoder.SaveEncrypted(&buf, key)
+	if err != nil {
+		t.Fatalf("save error: %+v", err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte(value)) {
+		t.Error("snapshot contained plaintext value")
+	}
+
+	data := buf.Bytes()
+
+	newEncoder := NewOrdinal(false)
+	err = newEncoder.LoadEncrypted(bytes.NewReader(data), key)
+	if err != nil {
+		t.Fatalf("load error: %+v", err)
+	}
+	if newEncoder.Decode(code) != value {
+		t.Error("decoded value did not equal original value")
+	}
+
+	wrongKey := bytes.Repeat([]byte{8}, 32)
+	err = newEncoder.LoadEncrypted(bytes.NewReader(data), wrongKey)
+	if err != ErrDecryption {
+		t.Errorf("error was %+v and not a decryption error", err)
+	}
+}
@@ -17,7 +17,37 @@ package encoder
 import "errors"
 
 var (
-	ErrBounds       = errors.New("index out of bounds")
-	ErrLength       = errors.New("code length does not match encoder length")
-	ErrTargetLength = errors.New("target data is not same length as categorical data")
+	ErrBounds            = errors.New("index out of bounds")
+	ErrLength            = errors.New("code length does not match encoder length")
+	ErrTargetLength      = errors.New("target data is not same length as categorical data")
+	ErrFalsePositiveRate = errors.New("false positive rate must be between 0 and 1")
+	ErrPrecision         = errors.New("precision is out of range")
+	ErrDType             = errors.New("unsupported element type")
+	ErrDuplicateValue    = errors.New("value is duplicated")
+	ErrReservedValue     = errors.New("value is reserved by the encoder")
+	ErrUnsupported       = errors.New("unsupported encoder configuration")
+	ErrMemoryBudget      = errors.New("encoders exceed memory budget")
+	ErrFormat            = errors.New("data is not in the expected format")
+	ErrCorruptSnapshot   = errors.New("snapshot does not match its checksum")
+	ErrDecryption        = errors.New("snapshot could not be decrypted")
+	ErrDelta             = errors.New("delta does not apply to encoder version")
+	ErrExpired           = errors.New("code has expired")
+	ErrCapacity          = errors.New("capacity must be positive")
+	ErrCodeTaken         = errors.New("code is already assigned")
+	ErrNotDense          = errors.New("codes are not dense")
+	ErrPreprocessor      = errors.New("preprocessor is not registered")
+	ErrInvalidCodeword   = errors.New("codeword is not a valid one-hot codeword")
+	ErrFolds             = errors.New("number of folds is out of range")
+	ErrWeightLength      = errors.New("weights are not same length as categorical data")
+	ErrPriorStrength     = errors.New("prior strength must not be negative")
+	ErrNoData            = errors.New("no data to fit")
+	ErrQuantile          = errors.New("quantiles must be ordered and between 0 and 1")
+	ErrIncompatible      = errors.New("encoders assign different codes to the same value")
+	ErrUnknownCategory   = errors.New("value is not a category of the encoder")
+	ErrStorage           = errors.New("no storage is registered for URL scheme")
+	ErrFrozen            = errors.New("value is not encoded by the frozen encoder")
+	ErrBins              = errors.New("bin edges must be finite and increasing")
+	ErrWeight            = errors.New("weights must be finite and non-negative")
+	ErrBase              = errors.New("base must be between 2 and 256")
+	ErrReserveRange      = errors.New("reservation adds too many codes")
 )
@@ -0,0 +1,150 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"sort"
+	"time"
+)
+
+// ExpandingMean is a one way encoder.
+// You cannot decode ExpandingMean values
+// as some values may be encoded with the same
+// numerical code.
+// ExpandingMean is a time-aware target-based encoder
+// that encodes an observation with the smoothed target
+// mean of its category over observations from strictly
+// earlier times, so training rows never see the future.
+type ExpandingMean struct {
+	categories map[string]*history
+	global     *history
+	smoothing  float64
+}
+
+// history holds the observation times of a category
+// in order with the running sums of their targets.
+type history struct {
+	times []time.Time
+	sums  []float64
+}
+
+// NewExpandingMean will create an ExpandingMean encoder from
+// the values, their targets and the times they were observed.
+// Category means are shrunk towards the mean of the whole
+// target before the same time with `smoothing` pseudo-observations.
+func NewExpandingMean(values []string, target []float64, times []time.Time, smoothing float64) (*ExpandingMean, error) {
+	if len(target) != len(values) || len(times) != len(values) {
+		return &ExpandingMean{}, ErrTargetLength
+	}
+	if smoothing < 0 {
+		return &ExpandingMean{}, ErrPriorStrength
+	}
+
+	order := make([]int, len(values), len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return times[order[a]].Before(times[order[b]])
+	})
+
+	e := &ExpandingMean{
+		categories: make(map[string]*history),
+		global:     newHistory(),
+		smoothing:  smoothing,
+	}
+	for _, i := range order {
+		h, ok := e.categories[values[i]]
+		if !ok {
+			h = newHistory()
+			e.categories[values[i]] = h
+		}
+		h.add(times[i], target[i])
+		e.global.add(times[i], target[i])
+	}
+
+	return e, nil
+}
+
+func newHistory() *history {
+	return &history{
+		sums: []float64{0},
+	}
+}
+
+func (h *history) add(t time.Time, y float64) {
+	h.times = append(h.times, t)
+	h.sums = append(h.sums, h.sums[len(h.sums)-1]+y)
+}
+
+// before will return the number of observations and the
+// sum of their targets from strictly before the cutoff.
+func (h *history) before(cutoff time.Time) (float64, float64) {
+	n := sort.Search(len(h.times), func(i int) bool {
+		return !h.times[i].Before(cutoff)
+	})
+
+	return float64(n), h.sums[n]
+}
+
+// EncodeAt will return the encoding of the value using only
+// observations from strictly before the cutoff time. The
+// mean of the whole target before the cutoff is returned for
+// categories without earlier observations, and 0 if there
+// are no earlier observations at all.
+func (e *ExpandingMean) EncodeAt(s string, cutoff time.Time) float64 {
+	n, sum := e.global.before(cutoff)
+	if n == 0 {
+		return 0
+	}
+	prior := sum / n
+
+	h, ok := e.categories[s]
+	if !ok {
+		return prior
+	}
+	n, sum = h.before(cutoff)
+	if n+e.smoothing == 0 {
+		return prior
+	}
+
+	return (sum + e.smoothing*prior) / (n + e.smoothing)
+}
+
+// EncodeSliceAt will return the encoding of every value at
+// its own cutoff time. Called with the training values and
+// times, it returns encodings free of future leakage.
+func (e *ExpandingMean) EncodeSliceAt(values []string, cutoffs []time.Time) ([]float64, error) {
+	if len(cutoffs) != len(values) {
+		return []float64{}, ErrLength
+	}
+
+	codes := make([]float64, len(values), len(values))
+	for i, v := range values {
+		codes[i] = e.EncodeAt(v, cutoffs[i])
+	}
+
+	return codes, nil
+}
+
+// Get will return the encoding of the value using every
+// observation, for inference after the training period.
+func (e *ExpandingMean) Get(s string) float64 {
+	if len(e.global.times) == 0 {
+		return 0
+	}
+	last := e.global.times[len(e.global.times)-1]
+
+	return e.EncodeAt(s, last.Add(time.Nanosecond))
+}
@@ -0,0 +1,50 @@
+package encoder
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestExpandingMean(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2020, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+	values := []string{"b", "a", "a", "a", "b"}
+	target := []float64{0, 1, 3, 5, 2}
+	times := []time.Time{day(1), day(1), day(2), day(2), day(3)}
+
+	e, err := NewExpandingMean(values, target, times, 0)
+	if err != nil {
+		t.Fatalf("fit error: %+v", err)
+	}
+
+	codes, err := e.EncodeSliceAt(values, times)
+	if err != nil {
+		t.Fatalf("encode error: %+v", err)
+	}
+	// nothing precedes day 1, "a" on day 2 only sees day 1
+	// and "b" on day 3 only sees its day 1 observation.
+	expected := []float64{0, 0, 1, 1, 0}
+	for i := range expected {
+		if math.Abs(codes[i]-expected[i]) > 1e-9 {
+			t.Errorf("code %d was %f and not %f", i, codes[i], expected[i])
+		}
+	}
+
+	if got := e.EncodeAt("c", day(2)); got != 0.5 {
+		t.Errorf("unseen category was %f and not the prior 0.5", got)
+	}
+	if got := e.Get("a"); got != 3 {
+		t.Errorf("full history encoding was %f and not 3", got)
+	}
+
+	smoothed, _ := NewExpandingMean(values, target, times, 1)
+	if got := smoothed.EncodeAt("b", day(3)); math.Abs(got-(0+2.25)/2) > 1e-9 {
+		t.Errorf("smoothed encoding was %f", got)
+	}
+
+	if _, err := NewExpandingMean(values, target, times[:2], 0); err != ErrTargetLength {
+		t.Error("expected target length error")
+	}
+}
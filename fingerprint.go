@@ -0,0 +1,106 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// Fingerprint will return a SHA-256 hash of the value to code
+// mapping of the encoder and of everything else that decides
+// how values are encoded: its aliases, preprocessor names,
+// reserved and expired codes and whether it reserves a code for
+// missing values. Encoders that differ in any of these have
+// different fingerprints, so the fingerprint can be recorded
+// with a model at training time and checked against the encoder
+// loaded for serving. Missing policies and preprocessors are
+// functions, so only whether a policy is set and the names of
+// the preprocessors are hashed, not what they do.
+func (e *Ordinal) Fingerprint() [32]byte {
+	e.RLock()
+	defer e.RUnlock()
+
+	values := e.decoder.strings()
+	if e.missing == nil && len(e.aliases) == 0 && len(e.preprocessNames) == 0 &&
+		len(e.reserved) == 0 && len(e.expired) == 0 {
+		return fingerprint("ordinal", values)
+	}
+
+	// the number of values separates them from the
+	// tables that follow, hashed as a single value
+	var buf bytes.Buffer
+	writeUvarint(&buf, uint64(len(values)))
+	kind := "ordinal-tables" + buf.String()
+
+	buf.Reset()
+	if e.missing != nil {
+		buf.WriteByte(1)
+	} else {
+		buf.WriteByte(0)
+	}
+
+	aliases := e.sortedAliases()
+	writeUvarint(&buf, uint64(len(aliases)))
+	for _, alias := range aliases {
+		writeString(&buf, alias)
+		writeString(&buf, e.aliases[alias])
+	}
+
+	writeUvarint(&buf, uint64(len(e.preprocessNames)))
+	for _, name := range e.preprocessNames {
+		writeString(&buf, name)
+	}
+
+	for _, codes := range [][]uint64{sortedCodes(e.reserved), sortedCodes(e.expired)} {
+		writeUvarint(&buf, uint64(len(codes)))
+		for _, code := range codes {
+			writeUvarint(&buf, code)
+		}
+	}
+
+	return fingerprint(kind, append(values, buf.String()))
+}
+
+// Fingerprint will return a SHA-256 hash of the value to
+// dimension mapping of the encoder, including whether the
+// first dimension is dropped.
+func (e *OneHot) Fingerprint() [32]byte {
+	kind := "onehot"
+	if e.dropFirst {
+		kind = "onehot-dropfirst"
+	}
+
+	return fingerprint(kind, e.decoder)
+}
+
+// fingerprint will hash the kind of encoder followed by
+// every value, length-prefixed, in code order.
+func fingerprint(kind string, values []string) [32]byte {
+	var length [binary.MaxVarintLen64]byte
+
+	h := sha256.New()
+	h.Write([]byte(kind))
+	for _, v := range values {
+		n := binary.PutUvarint(length[:], uint64(len(v)))
+		h.Write(length[:n])
+		h.Write([]byte(v))
+	}
+
+	var sum [32]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
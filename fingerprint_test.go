@@ -0,0 +1,84 @@
+package encoder
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestOrdinalFingerprint(t *testing.T) {
+	a := NewOrdinal(true)
+	a.Encode("red")
+	a.Encode("green")
+
+	b := NewOrdinal(true)
+	b.Encode("red")
+	b.Encode("green")
+
+	if a.Fingerprint() != b.Fingerprint() {
+		t.Error("identical encoders had different fingerprints")
+	}
+
+	c := NewOrdinal(true)
+	c.Encode("green")
+	c.Encode("red")
+
+	if a.Fingerprint() == c.Fingerprint() {
+		t.Error("encoders with different codes had the same fingerprint")
+	}
+}
+
+func TestOrdinalFingerprintAliases(t *testing.T) {
+	a := NewOrdinal(true)
+	a.Encode("red")
+
+	b := a.Clone()
+	err := b.Alias("red", "crimson")
+	if err != nil {
+		t.Fatalf("alias error: %+v", err)
+	}
+
+	if a.Fingerprint() == b.Fingerprint() {
+		t.Error("encoders with different aliases had the same fingerprint")
+	}
+
+	filter, err := b.BloomFilter(0.01)
+	if err != nil {
+		t.Fatalf("bloom filter error: %+v", err)
+	}
+	if !filter.Contains("crimson") {
+		t.Error("bloom filter did not contain the alias")
+	}
+
+	node := b.ONNXNode("ordinal", "color", "color_code")
+	if !bytes.Contains(node, []byte("crimson")) {
+		t.Error("ONNX node did not contain the alias")
+	}
+}
+
+func TestOrdinalFingerprintTables(t *testing.T) {
+	plain := NewOrdinal(true)
+	plain.Encode("red")
+
+	missing := NewOrdinal(false, WithMissing(DefaultMissing))
+	missing.Encode("red")
+	if plain.Fingerprint() == missing.Fingerprint() {
+		t.Error("encoders with different missing policies had the same fingerprint")
+	}
+
+	// both decode code 1 as the empty string
+	reserved := NewOrdinal(false)
+	reserved.Encode("red")
+	reserved.Reserve(1, 2)
+	empty := NewOrdinal(false)
+	empty.EncodeSlice([]string{"red", ""})
+	if reserved.Fingerprint() == empty.Fingerprint() {
+		t.Error("encoders with different reserved codes had the same fingerprint")
+	}
+
+	normalized := NewOrdinal(true, WithNormalizer("lower", strings.ToLower))
+	normalized.Encode("red")
+	if plain.Fingerprint() == normalized.Fingerprint() {
+		t.Error("encoders with different preprocessors had the same fingerprint")
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// Fold holds the indexes of the rows used to fit
+// (train) and to evaluate (test) in one split of a
+// dataset, so that target-based encoders can be fit
+// without seeing the targets of the rows they encode.
+type Fold struct {
+	Train []int
+	Test  []int
+}
+
+// KFold will split `n` rows into `k` folds of consecutive rows,
+// every row appearing in exactly one test set. If `shuffle` is
+// true the rows are shuffled with the seed first. An `ErrFolds`
+// error is returned unless 2 <= k <= n.
+func KFold(n, k int, shuffle bool, seed int64) ([]Fold, error) {
+	if k < 2 || k > n {
+		return nil, ErrFolds
+	}
+
+	order := make([]int, n, n)
+	for i := range order {
+		order[i] = i
+	}
+	if shuffle {
+		order = rand.New(rand.NewSource(seed)).Perm(n)
+	}
+
+	assignment := make([]int, n, n)
+	for rank, i := range order {
+		assignment[i] = rank * k / n
+	}
+
+	return foldsFromAssignment(assignment, k), nil
+}
+
+// StratifiedKFold will split the rows of the class target into
+// `k` folds whose class proportions match those of the whole
+// target, dealing the rows of every class to the folds in turn.
+// If `shuffle` is true the rows of every class are shuffled with
+// the seed first. An `ErrFolds` error is returned unless
+// 2 <= k <= len(target).
+func StratifiedKFold(target []string, k int, shuffle bool, seed int64) ([]Fold, error) {
+	if k < 2 || k > len(target) {
+		return nil, ErrFolds
+	}
+
+	classes := make(map[string][]int)
+	for i, class := range target {
+		classes[class] = append(classes[class], i)
+	}
+
+	names := make([]string, 0, len(classes))
+	for class := range classes {
+		names = append(names, class)
+	}
+	sort.Strings(names)
+
+	random := rand.New(rand.NewSource(seed))
+	assignment := make([]int, len(target), len(target))
+	next := 0
+	for _, class := range names {
+		rows := classes[class]
+		if shuffle {
+			random.Shuffle(len(rows), func(i, j int) { rows[i], rows[j] = rows[j], rows[i] })
+		}
+		// continue dealing where the previous class stopped
+		// so that small classes do not all land in fold 0
+		for _, i := range rows {
+			assignment[i] = next
+			next = (next + 1) % k
+		}
+	}
+
+	return foldsFromAssignment(assignment, k), nil
+}
+
+// TimeSeriesSplit will split `n` rows ordered in time into `k`
+// folds with expanding training windows: every fold tests on
+// the next block of rows and trains on all the rows before it,
+// so no fold is fit on rows later than those it encodes. An
+// `ErrFolds` error is returned unless 1 <= k < n.
+func TimeSeriesSplit(n, k int) ([]Fold, error) {
+	if k < 1 || k >= n {
+		return nil, ErrFolds
+	}
+
+	size := n / (k + 1)
+	folds := make([]Fold, k, k)
+	for f := range folds {
+		start := n - (k-f)*size
+		folds[f] = Fold{
+			Train: indexRange(0, start),
+			Test:  indexRange(start, start+size),
+		}
+	}
+
+	return folds, nil
+}
+
+func foldsFromAssignment(assignment []int, k int) []Fold {
+	folds := make([]Fold, k, k)
+	for f := range folds {
+		folds[f] = Fold{
+			Train: make([]int, 0, len(assignment)),
+			Test:  make([]int, 0),
+		}
+	}
+
+	for i, a := range assignment {
+		for f := range folds {
+			if f == a {
+				folds[f].Test = append(folds[f].Test, i)
+			} else {
+				folds[f].Train = append(folds[f].Train, i)
+			}
+		}
+	}
+
+	return folds
+}
+
+func indexRange(from, to int) []int {
+	indexes := make([]int, 0, to-from)
+	for i := from; i < to; i++ {
+		indexes = append(indexes, i)
+	}
+
+	return indexes
+}
@@ -0,0 +1,79 @@
+package encoder
+
+import (
+	"testing"
+)
+
+func checkFolds(t *testing.T, folds []Fold, n int) {
+	tested := make(map[int]int)
+	for _, fold := range folds {
+		if len(fold.Train)+len(fold.Test) != n {
+			t.Errorf("fold does not cover every row: %v", fold)
+		}
+		for _, i := range fold.Test {
+			tested[i]++
+		}
+	}
+	for i := 0; i < n; i++ {
+		if tested[i] != 1 {
+			t.Errorf("row %d was tested %d times", i, tested[i])
+		}
+	}
+}
+
+func TestKFold(t *testing.T) {
+	folds, err := KFold(10, 3, false, 0)
+	if err != nil {
+		t.Fatalf("split error: %+v", err)
+	}
+	checkFolds(t, folds, 10)
+	if folds[0].Test[0] != 0 || len(folds[0].Test) != 4 {
+		t.Errorf("unexpected first fold %v", folds[0].Test)
+	}
+
+	shuffled, _ := KFold(10, 3, true, 42)
+	checkFolds(t, shuffled, 10)
+	again, _ := KFold(10, 3, true, 42)
+	if shuffled[1].Test[0] != again[1].Test[0] {
+		t.Error("shuffled folds are not reproducible with the same seed")
+	}
+
+	if _, err := KFold(2, 3, false, 0); err != ErrFolds {
+		t.Error("expected folds error")
+	}
+}
+
+func TestStratifiedKFold(t *testing.T) {
+	target := []string{"a", "a", "a", "a", "b", "b", "b", "b"}
+	folds, err := StratifiedKFold(target, 2, true, 7)
+	if err != nil {
+		t.Fatalf("split error: %+v", err)
+	}
+	checkFolds(t, folds, len(target))
+
+	for _, fold := range folds {
+		counts := make(map[string]int)
+		for _, i := range fold.Test {
+			counts[target[i]]++
+		}
+		if counts["a"] != 2 || counts["b"] != 2 {
+			t.Errorf("fold is not stratified: %v", counts)
+		}
+	}
+}
+
+func TestTimeSeriesSplit(t *testing.T) {
+	folds, err := TimeSeriesSplit(9, 2)
+	if err != nil {
+		t.Fatalf("split error: %+v", err)
+	}
+
+	if len(folds[0].Train) != 3 || folds[0].Test[0] != 3 || len(folds[1].Train) != 6 || folds[1].Test[2] != 8 {
+		t.Errorf("unexpected folds %v", folds)
+	}
+	for _, fold := range folds {
+		if fold.Train[len(fold.Train)-1] >= fold.Test[0] {
+			t.Error("fold trains on rows after its test rows")
+		}
+	}
+}
@@ -14,14 +14,51 @@
 
 package encoder
 
-import "github.com/humilityai/sam"
+import (
+	"time"
+
+	"github.com/humilityai/sam"
+)
 
 // Frequency is a one-way encoder.
 // You cannot decode Frequency values
 // as some values may be encoded with the same
 // numerical value.
 type Frequency struct {
-	encoder sam.MapStringInt
+	encoder   sam.MapStringInt
+	total     int
+	smoothing float64
+	missing   MissingPolicy
+	created   time.Time
+}
+
+// FrequencyOption configures optional behaviour
+// of a Frequency encoder at construction time.
+type FrequencyOption func(*Frequency)
+
+// WithFrequencySmoothing will add `alpha` pseudo-counts to
+// every category, and to a shared bucket for unseen
+// categories, when estimating probabilities: alpha 1 is
+// Laplace smoothing and other values are a symmetric
+// Dirichlet prior. Unseen and rare categories then get
+// calibrated, non-zero probabilities, and the encoder
+// transforms values to their smoothed probability rather
+// than their raw count.
+func WithFrequencySmoothing(alpha float64) FrequencyOption {
+	return func(e *Frequency) {
+		e.smoothing = alpha
+	}
+}
+
+// WithFrequencyMissing will count every value considered
+// missing by the policy as a single category of its own, so
+// missing values are encoded with the number of missing
+// observations rather than with counts of their spellings.
+// The empty string is only missing if the policy says so.
+func WithFrequencyMissing(policy MissingPolicy) FrequencyOption {
+	return func(e *Frequency) {
+		e.missing = policy
+	}
 }
 
 // RollingFrequency is a one-war encoder.
@@ -29,21 +66,39 @@ type Frequency struct {
 // as some values may be encoded with the same
 // numerical code.
 type RollingFrequency struct {
-	window int
-	codes  sam.SliceInt
+	window  int
+	codes   sam.SliceInt
+	current sam.MapStringInt
+	seen    int
+}
+
+// SessionFrequency is a one-way encoder.
+// You cannot decode SessionFrequency values
+// as some values may be encoded with the same
+// numerical code.
+// SessionFrequency counts values within explicit sessions,
+// such as clickstream visits, rather than fixed windows.
+type SessionFrequency struct {
+	codes sam.SliceInt
 }
 
 // NewFrequency will return a frequency encoder
 // with the given values encoded.
-func NewFrequency(values []string) *Frequency {
-	encoder := make(sam.MapStringInt)
-	for _, v := range values {
-		encoder.Increment(v)
+func NewFrequency(values []string, opts ...FrequencyOption) *Frequency {
+	e := &Frequency{
+		encoder: make(sam.MapStringInt),
+		total:   len(values),
+		created: time.Now(),
+	}
+	for _, opt := range opts {
+		opt(e)
 	}
 
-	return &Frequency{
-		encoder: encoder,
+	for _, v := range values {
+		e.encoder.Increment(missingKey(e.missing, v))
 	}
+
+	return e
 }
 
 // NewRollingFrequency will create a codeword for every value in the list of values
@@ -65,9 +120,39 @@ func NewRollingFrequency(window int, values []string) *RollingFrequency {
 	}
 
 	return &RollingFrequency{
-		codes:  codes,
-		window: window,
+		codes:   codes,
+		window:  window,
+		current: encoder,
+		seen:    len(values),
+	}
+}
+
+// NewSessionFrequency will create a codeword for every value in the list of values
+// in the order of those values: the number of times the value has been seen so far
+// within its session. Counts start again for every session, wherever its observations
+// appear in the list.
+// The list of sessions must be the same length as the list of values.
+func NewSessionFrequency(sessions, values []string) (*SessionFrequency, error) {
+	if len(sessions) != len(values) {
+		return &SessionFrequency{}, ErrLength
+	}
+
+	codes := make(sam.SliceInt, len(values), len(values))
+
+	encoders := make(map[string]sam.MapStringInt)
+	for i := 0; i < len(values); i++ {
+		encoder, ok := encoders[sessions[i]]
+		if !ok {
+			encoder = make(sam.MapStringInt)
+			encoders[sessions[i]] = encoder
+		}
+		encoder.Increment(values[i])
+		codes[i] = encoder[values[i]]
 	}
+
+	return &SessionFrequency{
+		codes: codes,
+	}, nil
 }
 
 // Codes will return the list of codes generated
@@ -88,6 +173,26 @@ func (e *RollingFrequency) Get(index int) (int, error) {
 	return e.codes[index], nil
 }
 
+// Current will return the count of the value
+// in the active window.
+func (e *RollingFrequency) Current(s string) int {
+	return e.current[s]
+}
+
+// Update will add an observation of the value to the
+// active window, starting a new window when the active one
+// is full, and return its code. Observations added with
+// Update are not included in the training codes.
+func (e *RollingFrequency) Update(s string) int {
+	if e.current == nil || e.seen%e.window == 0 {
+		e.current = make(sam.MapStringInt)
+	}
+	e.seen++
+	e.current.Increment(s)
+
+	return e.current[s]
+}
+
 // Window will return the window used when
 // creating the RollingFrequency encoder.
 func (e *RollingFrequency) Window() int {
@@ -96,6 +201,38 @@ func (e *RollingFrequency) Window() int {
 
 // Get ...
 func (e *Frequency) Get(s string) (int, bool) {
-	v, ok := e.encoder[s]
+	v, ok := e.encoder[missingKey(e.missing, s)]
 	return v, ok
 }
+
+// Codes will return the list of codes generated
+// for the list of values provided in the creation
+// of the SessionFrequency encoder.
+func (e *SessionFrequency) Codes() sam.SliceInt {
+	return e.codes
+}
+
+// Get will return the code for the given index, according
+// to the original slice of values provided in the construction
+// of the SessionFrequency encoder.
+func (e *SessionFrequency) Get(index int) (int, error) {
+	if index < 0 || index > len(e.codes)-1 {
+		return 0, ErrBounds
+	}
+
+	return e.codes[index], nil
+}
+
+// Probability will return the estimated probability of the
+// value, smoothed if the encoder was created with
+// WithFrequencySmoothing. Without smoothing this is the
+// relative frequency of the value, and 0 if it is unseen.
+func (e *Frequency) Probability(s string) float64 {
+	// one extra bucket is shared by every unseen value
+	denominator := float64(e.total) + e.smoothing*float64(len(e.encoder)+1)
+	if denominator == 0 {
+		return 0
+	}
+
+	return (float64(e.encoder[missingKey(e.missing, s)]) + e.smoothing) / denominator
+}
@@ -0,0 +1,73 @@
+package encoder
+
+import "testing"
+
+func TestSessionFrequency(t *testing.T) {
+	sessions := []string{"s1", "s2", "s1", "s1", "s2"}
+	values := []string{"home", "home", "cart", "home", "home"}
+
+	e, err := NewSessionFrequency(sessions, values)
+	if err != nil {
+		t.Fatalf("create error: %+v", err)
+	}
+
+	expected := []int{1, 1, 1, 2, 2}
+	for i, code := range e.Codes() {
+		if code != expected[i] {
+			t.Errorf("code %d was %d and not %d", i, code, expected[i])
+		}
+	}
+
+	if _, err := e.Get(5); err != ErrBounds {
+		t.Error("expected bounds error")
+	}
+	if _, err := NewSessionFrequency(sessions[:1], values); err != ErrLength {
+		t.Error("expected length error")
+	}
+}
+
+func TestRollingFrequencyCurrent(t *testing.T) {
+	e := NewRollingFrequency(3, []string{"a", "b", "a", "a"})
+
+	if e.Current("a") != 1 || e.Current("b") != 0 {
+		t.Errorf("unexpected active window counts %d, %d", e.Current("a"), e.Current("b"))
+	}
+
+	if code := e.Update("a"); code != 2 {
+		t.Errorf("update code was %d and not 2", code)
+	}
+	e.Update("b")
+	if code := e.Update("a"); code != 1 {
+		t.Errorf("update code in a new window was %d and not 1", code)
+	}
+	if e.Current("b") != 0 {
+		t.Error("window was not reset")
+	}
+	if len(e.Codes()) != 4 {
+		t.Error("updates changed the training codes")
+	}
+}
+
+func TestFrequencySmoothing(t *testing.T) {
+	values := []string{"a", "a", "a", "b"}
+
+	raw := NewFrequency(values)
+	if raw.Probability("a") != 0.75 || raw.Probability("c") != 0 {
+		t.Error("unexpected relative frequencies")
+	}
+
+	e := NewFrequency(values, WithFrequencySmoothing(1))
+	// 4 observations plus one pseudo-count for "a", "b" and unseen values
+	if p := e.Probability("a"); p != 4.0/7 {
+		t.Errorf("smoothed probability was %f and not 4/7", p)
+	}
+	if p := e.Probability("c"); p != 1.0/7 {
+		t.Errorf("unseen probability was %f and not 1/7", p)
+	}
+	if f := e.Transform("b")[0]; f != 2.0/7 {
+		t.Errorf("smoothed transform was %f and not 2/7", f)
+	}
+	if f := raw.Transform("b")[0]; f != 1 {
+		t.Errorf("raw transform was %f and not the count 1", f)
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"math"
+
+	"github.com/humilityai/sam"
+)
+
+const (
+	glmmIterations = 100
+	glmmTolerance  = 1e-8
+)
+
+// GLMMRegression is a one way encoder.
+// You cannot decode GLMMRegression values
+// as some values may be encoded with the same
+// numerical code.
+// GLMMRegression is a target-based encoder that
+// fits a random-intercept linear mixed model and
+// encodes every category with the BLUP of its
+// random intercept.
+type GLMMRegression struct {
+	encoder map[string]float64
+	missing MissingPolicy
+}
+
+// GLMMClassification is a one way encoder.
+// You cannot decode GLMMClassification values
+// as some values may be encoded with the same
+// numerical code.
+// GLMMClassification is a target-based encoder that
+// fits a random-intercept logistic mixed model and
+// encodes every category with the estimate of its
+// random intercept on the log-odds scale.
+type GLMMClassification struct {
+	encoder map[string]float64
+	missing MissingPolicy
+}
+
+// NewGLMMRegression will create a GLMMRegression encoder.
+// The variance components are estimated with the one-way
+// ANOVA (method of moments) estimator.
+func NewGLMMRegression(values []string, target []float64, opts ...TargetOption) (*GLMMRegression, error) {
+	if len(target) != len(values) {
+		return &GLMMRegression{}, ErrTargetLength
+	}
+	o := newTargetOptions(opts)
+	values = o.categories(values)
+
+	targetValues := make(map[string]sam.SliceFloat64)
+	for i := 0; i < len(values); i++ {
+		targetValues[values[i]] = append(targetValues[values[i]], target[i])
+	}
+
+	mean := sam.SliceFloat64(target).Avg()
+	groups := float64(len(targetValues))
+	total := float64(len(target))
+
+	var ssWithin, ssBetween, sumSquaredCounts float64
+	groupMeans := make(map[string]float64)
+	for k, v := range targetValues {
+		groupMean := v.Avg()
+		groupMeans[k] = groupMean
+		for _, y := range v {
+			ssWithin += (y - groupMean) * (y - groupMean)
+		}
+		n := float64(len(v))
+		ssBetween += n * (groupMean - mean) * (groupMean - mean)
+		sumSquaredCounts += n * n
+	}
+
+	var msWithin, tau2 float64
+	if total > groups {
+		msWithin = ssWithin / (total - groups)
+	}
+	if groups > 1 {
+		msBetween := ssBetween / (groups - 1)
+		n0 := (total - sumSquaredCounts/total) / (groups - 1)
+		tau2 = math.Max(0, (msBetween-msWithin)/n0)
+	}
+
+	encoder := make(map[string]float64)
+	for k, v := range targetValues {
+		if tau2 == 0 {
+			encoder[k] = 0
+			continue
+		}
+		shrinkage := tau2 / (tau2 + msWithin/float64(len(v)))
+		encoder[k] = shrinkage * (groupMeans[k] - mean)
+	}
+
+	return &GLMMRegression{
+		encoder: encoder,
+		missing: o.missing,
+	}, nil
+}
+
+// NewGLMMClassification will create a GLMMClassification encoder
+// for a binary target.
+// The model is fit by alternating Newton updates of the random
+// intercepts (Laplace approximation) and the fixed intercept,
+// with the random-intercept variance re-estimated each iteration.
+func NewGLMMClassification(values []string, target []bool, opts ...TargetOption) (*GLMMClassification, error) {
+	if len(target) != len(values) {
+		return &GLMMClassification{}, ErrTargetLength
+	}
+	o := newTargetOptions(opts)
+	values = o.categories(values)
+
+	counts := make(sam.MapStringInt)
+	positives := make(sam.MapStringInt)
+	var totalPositives int
+	for i := 0; i < len(values); i++ {
+		counts.Increment(values[i])
+		if target[i] {
+			positives.Increment(values[i])
+			totalPositives++
+		}
+	}
+
+	p := (float64(totalPositives) + 0.5) / (float64(len(target)) + 1)
+	mu := math.Log(p / (1 - p))
+	tau2 := 1.0
+
+	intercepts := make(map[string]float64)
+	curvatures := make(map[string]float64)
+	for i := 0; i < glmmIterations; i++ {
+		var change float64
+		for group, n := range counts {
+			u := intercepts[group]
+			var hessian float64
+			for step := 0; step < 5; step++ {
+				prob := sigmoid(mu + u)
+				gradient := float64(positives[group]) - float64(n)*prob - u/tau2
+				hessian = -float64(n)*prob*(1-prob) - 1/tau2
+				u -= gradient / hessian
+			}
+			change = math.Max(change, math.Abs(u-intercepts[group]))
+			intercepts[group] = u
+			curvatures[group] = -hessian
+		}
+
+		var gradient, hessian float64
+		for group, n := range counts {
+			prob := sigmoid(mu + intercepts[group])
+			gradient += float64(positives[group]) - float64(n)*prob
+			hessian -= float64(n) * prob * (1 - prob)
+		}
+		if hessian != 0 {
+			step := gradient / hessian
+			mu -= step
+			change = math.Max(change, math.Abs(step))
+		}
+
+		var variance float64
+		for group := range counts {
+			u := intercepts[group]
+			variance += u*u + 1/curvatures[group]
+		}
+		tau2 = math.Max(variance/float64(len(counts)), glmmTolerance)
+
+		if change < glmmTolerance {
+			break
+		}
+	}
+
+	return &GLMMClassification{
+		encoder: intercepts,
+		missing: o.missing,
+	}, nil
+}
+
+// Get will retrieve the code for the given categorical value.
+func (e *GLMMRegression) Get(s string) (float64, bool) {
+	v, ok := e.encoder[missingKey(e.missing, s)]
+	return v, ok
+}
+
+// Get will retrieve the code for the given categorical value.
+func (e *GLMMClassification) Get(s string) (float64, bool) {
+	v, ok := e.encoder[missingKey(e.missing, s)]
+	return v, ok
+}
+
+func sigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
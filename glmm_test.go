@@ -0,0 +1,49 @@
+package encoder
+
+import (
+	"testing"
+)
+
+func TestGLMMRegression(t *testing.T) {
+	values := []string{"a", "a", "a", "b", "b", "b", "c"}
+	target := []float64{1, 2, 3, 7, 8, 9, 5}
+
+	encoder, err := NewGLMMRegression(values, target)
+	if err != nil {
+		t.Fatalf("glmm regression error: %+v", err)
+	}
+
+	a, _ := encoder.Get("a")
+	b, _ := encoder.Get("b")
+	if a >= 0 || b <= 0 {
+		t.Errorf("codes a=%f b=%f did not have expected signs", a, b)
+	}
+	if a < 2-5 || b > 8-5 {
+		t.Errorf("codes a=%f b=%f were not shrunk towards 0", a, b)
+	}
+
+	_, err = NewGLMMRegression(values, target[1:])
+	if err != ErrTargetLength {
+		t.Error("expected target length error")
+	}
+}
+
+func TestGLMMClassification(t *testing.T) {
+	values := []string{"a", "a", "a", "a", "b", "b", "b", "b"}
+	target := []bool{true, true, true, false, false, false, false, true}
+
+	encoder, err := NewGLMMClassification(values, target)
+	if err != nil {
+		t.Fatalf("glmm classification error: %+v", err)
+	}
+
+	a, _ := encoder.Get("a")
+	b, _ := encoder.Get("b")
+	if a <= b {
+		t.Errorf("code a=%f was not greater than code b=%f", a, b)
+	}
+
+	if _, ok := encoder.Get("c"); ok {
+		t.Error("unseen category should not have a code")
+	}
+}
@@ -0,0 +1,13 @@
+module github.com/humilityai/encoder/gonum
+
+go 1.24.0
+
+require (
+	github.com/humilityai/encoder v0.0.0
+	github.com/humilityai/sam v0.0.0-20200926070415-163d9ceca42a
+	gonum.org/v1/gonum v0.17.0
+)
+
+require github.com/humilityai/math v0.0.0-20200803033757-480d44b783d6 // indirect
+
+replace github.com/humilityai/encoder => ../
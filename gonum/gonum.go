@@ -0,0 +1,76 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// Package gonum writes encoded batches straight into gonum
+// matrices, so that training code built on gonum does not
+// convert the slices returned by the encoders element by
+// element. Dense matrices are filled in place, and one-hot
+// batches can also be viewed as a sparse `mat.Matrix`.
+package gonum
+
+import (
+	"github.com/humilityai/encoder"
+	"github.com/humilityai/sam"
+	"gonum.org/v1/gonum/mat"
+)
+
+// OneHot will return the codewords of every value in the
+// slice of strings as the rows of a `len(s)` x `Dimension()`
+// matrix. An empty matrix is returned if either is 0.
+func OneHot(e *encoder.OneHot, s sam.SliceString) *mat.Dense {
+	if len(s) == 0 || e.Dimension() == 0 {
+		return &mat.Dense{}
+	}
+
+	m := mat.NewDense(len(s), e.Dimension(), nil)
+	e.Fill(m, 0, s)
+	return m
+}
+
+// Vector will return the features of every value in the
+// slice of strings as the rows of a `len(s)` x `Dimension()`
+// matrix. The dimension is taken before any value is encoded,
+// as in `encoder.NewFloat64Tensor`. An empty matrix is
+// returned if either is 0.
+func Vector(e encoder.Vector, s sam.SliceString) *mat.Dense {
+	if len(s) == 0 || e.Dimension() == 0 {
+		return &mat.Dense{}
+	}
+
+	m := mat.NewDense(len(s), e.Dimension(), nil)
+	for i, v := range s {
+		copy(m.RawRowView(i), e.Transform(v))
+	}
+
+	return m
+}
+
+// Dataset will return the features of the rows as the rows of
+// a `len(rows)` x `Dimension()` matrix, written in place. An
+// `encoder.ErrLength` error is returned if a row does not have
+// one value per fitted column.
+func Dataset(d *encoder.Dataset, rows [][]string) (*mat.Dense, error) {
+	if len(rows) == 0 || d.Dimension() == 0 {
+		return &mat.Dense{}, nil
+	}
+
+	m := mat.NewDense(len(rows), d.Dimension(), nil)
+	for i, row := range rows {
+		_, err := d.AppendRow(m.RawRowView(i)[:0], row)
+		if err != nil {
+			return &mat.Dense{}, err
+		}
+	}
+
+	return m, nil
+}
@@ -0,0 +1,64 @@
+package gonum
+
+import (
+	"testing"
+
+	"github.com/humilityai/encoder"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestOneHot(t *testing.T) {
+	e := encoder.NewOneHot()
+	e.Encode("red")
+	e.Encode("blue")
+
+	m := OneHot(e, []string{"blue", "red", "green"})
+	expected := mat.NewDense(3, 3, []float64{
+		0, 0, 1,
+		0, 1, 0,
+		0, 0, 0,
+	})
+	if !mat.Equal(m, expected) {
+		t.Errorf("matrix was %v and not %v", mat.Formatted(m), mat.Formatted(expected))
+	}
+
+	if !OneHot(e, nil).IsEmpty() {
+		t.Error("matrix of no values was not empty")
+	}
+}
+
+func TestVector(t *testing.T) {
+	e, err := encoder.NewThermometer([]string{"low", "medium", "high"})
+	if err != nil {
+		t.Fatalf("new thermometer error: %+v", err)
+	}
+
+	m := Vector(e, []string{"high", "medium"})
+	expected := mat.NewDense(2, 2, []float64{1, 1, 1, 0})
+	if !mat.Equal(m, expected) {
+		t.Errorf("matrix was %v and not %v", mat.Formatted(m), mat.Formatted(expected))
+	}
+}
+
+func TestDataset(t *testing.T) {
+	var d encoder.Dataset
+	err := d.Fit([][]string{{"red", "1"}, {"blue", "3"}}, []encoder.ColumnKind{encoder.KindOrdinal, encoder.KindMinMax})
+	if err != nil {
+		t.Fatalf("fit error: %+v", err)
+	}
+
+	m, err := Dataset(&d, [][]string{{"blue", "2"}, {"red", "3"}})
+	if err != nil {
+		t.Fatalf("dataset error: %+v", err)
+	}
+
+	expected := mat.NewDense(2, 2, []float64{1, 0.5, 0, 1})
+	if !mat.Equal(m, expected) {
+		t.Errorf("matrix was %v and not %v", mat.Formatted(m), mat.Formatted(expected))
+	}
+
+	_, err = Dataset(&d, [][]string{{"red"}})
+	if err != encoder.ErrLength {
+		t.Error("expected length error")
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package gonum
+
+import (
+	"github.com/humilityai/encoder"
+	"github.com/humilityai/sam"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Indicator is a read-only `mat.Matrix` of the one-hot codewords
+// of a batch of values. Only the hot column of every row is held,
+// so it takes memory in proportion to the number of rows rather
+// than rows x dimension.
+type Indicator struct {
+	hot  []int
+	cols int
+}
+
+// NewIndicator will return the codewords of every value in the
+// slice of strings as a `len(s)` x `Dimension()` matrix. Rows of
+// values without a hot dimension are all zeros.
+func NewIndicator(e *encoder.OneHot, s sam.SliceString) *Indicator {
+	hot := make([]int, len(s), len(s))
+	for i := range hot {
+		hot[i] = -1
+	}
+
+	rows, cols := e.Indices(s)
+	for i, row := range rows {
+		hot[row] = cols[i]
+	}
+
+	return &Indicator{
+		hot:  hot,
+		cols: e.Dimension(),
+	}
+}
+
+// Dims returns the dimensions of the matrix.
+func (m *Indicator) Dims() (r, c int) {
+	return len(m.hot), m.cols
+}
+
+// At returns the element at row i, column j.
+func (m *Indicator) At(i, j int) float64 {
+	if i < 0 || i >= len(m.hot) {
+		panic(mat.ErrRowAccess)
+	}
+	if j < 0 || j >= m.cols {
+		panic(mat.ErrColAccess)
+	}
+
+	if m.hot[i] == j {
+		return 1
+	}
+	return 0
+}
+
+// T returns the transpose of the matrix.
+func (m *Indicator) T() mat.Matrix {
+	return mat.Transpose{Matrix: m}
+}
+
+// DoNonZero calls fn for every hot element of the matrix,
+// in row order, implementing `mat.NonZeroDoer`.
+func (m *Indicator) DoNonZero(fn func(i, j int, v float64)) {
+	for i, j := range m.hot {
+		if j >= 0 {
+			fn(i, j, 1)
+		}
+	}
+}
+
+// DoRowNonZero calls fn for the hot element of row i, if it
+// has one, implementing `mat.RowNonZeroDoer`.
+func (m *Indicator) DoRowNonZero(i int, fn func(i, j int, v float64)) {
+	if i < 0 || i >= len(m.hot) {
+		panic(mat.ErrRowAccess)
+	}
+
+	if m.hot[i] >= 0 {
+		fn(i, m.hot[i], 1)
+	}
+}
@@ -0,0 +1,34 @@
+package gonum
+
+import (
+	"testing"
+
+	"github.com/humilityai/encoder"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestIndicator(t *testing.T) {
+	e := encoder.NewOneHot()
+	e.Encode("red")
+	e.Encode("blue")
+
+	s := []string{"blue", "green", "red"}
+	m := NewIndicator(e, s)
+	if !mat.Equal(m, OneHot(e, s)) {
+		t.Errorf("indicator was %v", mat.Formatted(m))
+	}
+
+	var product mat.Dense
+	product.Mul(m.T(), m)
+	if product.At(1, 1) != 1 || product.At(2, 2) != 1 || product.At(0, 0) != 0 {
+		t.Errorf("product was %v", mat.Formatted(&product))
+	}
+
+	var nonzero int
+	m.DoNonZero(func(i, j int, v float64) {
+		nonzero++
+	})
+	if nonzero != 2 {
+		t.Errorf("matrix had %d non-zero elements and not 2", nonzero)
+	}
+}
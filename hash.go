@@ -0,0 +1,111 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+)
+
+// Hash is a stateless one way encoder.
+// Every string is encoded with a stable
+// hash-derived code, so the same value will
+// receive the same code in every process
+// without the encoder ever being persisted.
+// Different values may collide on the same code
+// and values cannot be decoded.
+// Codes can be rotated deliberately by changing
+// the seed of the encoder.
+type Hash struct {
+	dimension uint64
+	seed      uint64
+}
+
+// NewHash will create a Hash encoder whose codes
+// are in the range [0, dimension).
+// A dimension of 0 will return the full 64-bit hash.
+func NewHash(dimension uint64) *Hash {
+	return &Hash{
+		dimension: dimension,
+	}
+}
+
+// NewHashSeeded will create a Hash encoder like NewHash
+// whose codes are derived from the seed as well as the
+// string, so that changing the seed changes every code.
+// A seed of 0 gives the same codes as NewHash.
+func NewHashSeeded(dimension, seed uint64) *Hash {
+	return &Hash{
+		dimension: dimension,
+		seed:      seed,
+	}
+}
+
+// Encode will return the hash-derived code
+// for the given string.
+func (e *Hash) Encode(s string) uint64 {
+	code := hashSeeded(s, e.seed)
+	if e.dimension > 0 {
+		return code % e.dimension
+	}
+
+	return code
+}
+
+// EncodeSlice will encode all the values in the slice of strings
+// provided as an argument.
+func (e *Hash) EncodeSlice(s []string) []uint64 {
+	codes := make([]uint64, len(s), len(s))
+	for i, v := range s {
+		codes[i] = e.Encode(v)
+	}
+
+	return codes
+}
+
+// Dimension will return the modulus used
+// when creating the Hash encoder.
+func (e *Hash) Dimension() uint64 {
+	return e.dimension
+}
+
+// Seed will return the seed used
+// when creating the Hash encoder.
+func (e *Hash) Seed() uint64 {
+	return e.seed
+}
+
+// hashSeeded will return the 64-bit FNV-1a hash of
+// the seed followed by s, or of s alone if the seed is 0.
+func hashSeeded(s string, seed uint64) uint64 {
+	if seed == 0 {
+		return hashString(s)
+	}
+
+	var prefix [8]byte
+	binary.LittleEndian.PutUint64(prefix[:], seed)
+
+	hasher := fnv.New64a()
+	hasher.Write(prefix[:])
+	hasher.Write([]byte(s))
+	return hasher.Sum64()
+}
+
+// hashString will return the 64-bit FNV-1a hash of s.
+func hashString(s string) uint64 {
+	hasher := fnv.New64a()
+	hasher.Write([]byte(s))
+	return hasher.Sum64()
+}
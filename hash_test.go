@@ -0,0 +1,44 @@
+package encoder
+
+import (
+	"testing"
+)
+
+func TestHashEncode(t *testing.T) {
+	encoder := NewHash(8)
+	code := encoder.Encode("hello world")
+	if code >= 8 {
+		t.Errorf("code %d was not within the dimension", code)
+	}
+
+	if NewHash(8).Encode("hello world") != code {
+		t.Error("code was not stable across encoders")
+	}
+
+	if NewHash(0).Encode("hello world")%8 != code {
+		t.Error("modulus code did not match full hash code")
+	}
+}
+
+func TestHashSeeded(t *testing.T) {
+	unseeded := NewHash(1 << 20)
+	if NewHashSeeded(1<<20, 0).Encode("red") != unseeded.Encode("red") {
+		t.Error("seed 0 changed the codes")
+	}
+
+	seeded := NewHashSeeded(1<<20, 42)
+	if seeded.Encode("red") != NewHashSeeded(1<<20, 42).Encode("red") {
+		t.Error("seeded codes are not reproducible")
+	}
+	if seeded.Encode("red") == unseeded.Encode("red") && seeded.Encode("blue") == unseeded.Encode("blue") {
+		t.Error("seed did not rotate the codes")
+	}
+	if seeded.Seed() != 42 {
+		t.Errorf("seed was %d and not 42", seeded.Seed())
+	}
+
+	cross := NewCrossSeeded(1<<20, 42)
+	if cross.Encode("US", "mobile") != seeded.Encode("US"+crossSeparator+"mobile") {
+		t.Error("cross did not use the seed")
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"strings"
+)
+
+// Hierarchical will encode path-like categories, such as
+// "electronics/phones/android", as a code for every level of
+// the hierarchy followed by a code for the node itself. Every
+// level is encoded with the path up to that level, so that the
+// same name under different parents receives different codes.
+// Level codes of paths shorter than the depth of the encoder
+// are 0, the code of the empty string.
+type Hierarchical struct {
+	separator string
+	levels    []*Ordinal
+	nodes     *Ordinal
+}
+
+// NewHierarchical will create an encoder for paths whose
+// levels are separated by `separator`, emitting codes for
+// the first `depth` levels.
+func NewHierarchical(separator string, depth int) *Hierarchical {
+	levels := make([]*Ordinal, depth, depth)
+	for i := range levels {
+		levels[i] = NewOrdinal(true)
+	}
+
+	return &Hierarchical{
+		separator: separator,
+		levels:    levels,
+		nodes:     NewOrdinal(true),
+	}
+}
+
+// Encode will return the code of every level of the path
+// followed by the code of the path itself, adding the path
+// and all of its ancestors to the encoder if needed.
+func (e *Hierarchical) Encode(path string) []uint64 {
+	prefixes := e.prefixes(path)
+
+	codes := make([]uint64, len(e.levels)+1, len(e.levels)+1)
+	for i, level := range e.levels {
+		if i < len(prefixes) {
+			codes[i] = level.Encode(prefixes[i])
+		}
+	}
+
+	for _, p := range prefixes {
+		codes[len(e.levels)] = e.nodes.Encode(p)
+	}
+
+	return codes
+}
+
+// Lookup will return the codes of the path without adding
+// it to the encoder. Unseen levels are 0, and if the path
+// itself is unseen its code is that of its closest encoded
+// ancestor, or 0 if it has none.
+func (e *Hierarchical) Lookup(path string) []uint64 {
+	prefixes := e.prefixes(path)
+
+	codes := make([]uint64, len(e.levels)+1, len(e.levels)+1)
+	for i, level := range e.levels {
+		if i < len(prefixes) && level.Contains(prefixes[i]) {
+			codes[i] = level.Encode(prefixes[i])
+		}
+	}
+
+	for i := len(prefixes) - 1; i >= 0; i-- {
+		if e.nodes.Contains(prefixes[i]) {
+			codes[len(e.levels)] = e.nodes.Encode(prefixes[i])
+			break
+		}
+	}
+
+	return codes
+}
+
+// Decode will return the path of the given node code,
+// or the empty string if the code is not valid.
+func (e *Hierarchical) Decode(code uint64) string {
+	return e.nodes.Decode(code)
+}
+
+// Depth will return the number of levels encoded.
+func (e *Hierarchical) Depth() int {
+	return len(e.levels)
+}
+
+// Transform will encode the path, adding it to the encoder
+// if needed, and return its codes as features.
+func (e *Hierarchical) Transform(s string) []float64 {
+	codes := e.Encode(s)
+
+	features := make([]float64, len(codes), len(codes))
+	for i, v := range codes {
+		features[i] = float64(v)
+	}
+
+	return features
+}
+
+// prefixes will return the path up to every level,
+// e.g. "a", "a/b" and "a/b/c" for the path "a/b/c".
+func (e *Hierarchical) prefixes(path string) []string {
+	if path == "" {
+		return []string{}
+	}
+
+	parts := strings.Split(path, e.separator)
+	prefixes := make([]string, len(parts), len(parts))
+	for i := range parts {
+		prefixes[i] = strings.Join(parts[:i+1], e.separator)
+	}
+
+	return prefixes
+}
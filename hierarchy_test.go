@@ -0,0 +1,33 @@
+package encoder
+
+import (
+	"testing"
+)
+
+func TestHierarchical(t *testing.T) {
+	encoder := NewHierarchical("/", 2)
+	android := encoder.Encode("electronics/phones/android")
+	if len(android) != 3 {
+		t.Fatalf("expected 3 codes, got %d", len(android))
+	}
+
+	apple := encoder.Encode("food/apple")
+	if apple[0] == android[0] || apple[1] == android[1] {
+		t.Error("different parents shared level codes")
+	}
+
+	if short := encoder.Encode("toys"); short[1] != 0 {
+		t.Error("missing level was not encoded as 0")
+	}
+
+	ios := encoder.Lookup("electronics/phones/ios")
+	if ios[0] != android[0] || ios[1] != android[1] {
+		t.Error("lookup did not return the level codes of the parents")
+	}
+	if encoder.Decode(ios[2]) != "electronics/phones" {
+		t.Errorf("unseen leaf fell back to %q", encoder.Decode(ios[2]))
+	}
+	if encoder.Lookup("garden/tools")[2] != 0 {
+		t.Error("path without encoded ancestors should have node code 0")
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import "math"
+
+// IDF is a one-way encoder.
+// You cannot decode IDF values
+// as some values may be encoded with the same
+// numerical code.
+// IDF weights categories by their inverse frequency across
+// partitions (datasets, tenants, ...): categories found in
+// most partitions get a low weight and categories specific
+// to a few partitions a high weight.
+type IDF struct {
+	encoder    map[string]int
+	partitions int
+}
+
+// NewIDF will create an IDF encoder from the values of
+// every partition. A value counts once per partition
+// however often it appears in it.
+func NewIDF(partitions [][]string) *IDF {
+	encoder := make(map[string]int)
+	for _, partition := range partitions {
+		seen := make(map[string]bool)
+		for _, v := range partition {
+			if seen[v] {
+				continue
+			}
+			seen[v] = true
+			encoder[v]++
+		}
+	}
+
+	return &IDF{
+		encoder:    encoder,
+		partitions: len(partitions),
+	}
+}
+
+// Get will return the smoothed inverse partition frequency
+// of the value, `log((1 + n) / (1 + df)) + 1` where `n` is the
+// number of partitions and `df` the number of partitions that
+// contain the value. Values found in every partition have
+// weight 1 and unseen values have the highest weight.
+func (e *IDF) Get(s string) float64 {
+	return math.Log(float64(1+e.partitions)/float64(1+e.encoder[s])) + 1
+}
+
+// DocumentFrequency will return the number of
+// partitions that contain the value.
+func (e *IDF) DocumentFrequency(s string) int {
+	return e.encoder[s]
+}
+
+// Partitions will return the number of partitions
+// used when creating the IDF encoder.
+func (e *IDF) Partitions() int {
+	return e.partitions
+}
+
+// Transform will return the weight of the string
+// as a single feature.
+func (e *IDF) Transform(s string) []float64 {
+	return []float64{e.Get(s)}
+}
+
+// SizeBytes will return an estimate of the
+// memory held by the encoder in bytes.
+func (e *IDF) SizeBytes() int {
+	var size int
+	for k := range e.encoder {
+		size += mapEntryBytes + len(k)
+	}
+
+	return size
+}
@@ -0,0 +1,27 @@
+package encoder
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIDF(t *testing.T) {
+	e := NewIDF([][]string{
+		{"the", "invoice", "the"},
+		{"the", "ticket"},
+		{"the", "invoice"},
+	})
+
+	if e.Partitions() != 3 || e.DocumentFrequency("the") != 3 {
+		t.Error("unexpected partition counts")
+	}
+	if w := e.Get("the"); w != 1 {
+		t.Errorf("common value weight was %f and not 1", w)
+	}
+	if w := e.Get("ticket"); math.Abs(w-(math.Log(2)+1)) > 1e-9 {
+		t.Errorf("specific value weight was %f", w)
+	}
+	if e.Get("unseen") <= e.Get("ticket") || e.Get("ticket") <= e.Get("invoice") {
+		t.Error("weights do not decrease with partition frequency")
+	}
+}
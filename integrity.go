@@ -0,0 +1,170 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash"
+	"hash/crc32"
+	"sort"
+)
+
+// snapshotIntegrity is recorded in serialized snapshots
+// so that truncated or corrupted snapshots are detected
+// when they are loaded.
+type snapshotIntegrity struct {
+	Count    uint64 `json:"count"`
+	Checksum uint32 `json:"checksum"`
+	// Tables is the CRC-32 of the tables of an Ordinal
+	// beside its values. Snapshots written before it
+	// was recorded have none.
+	Tables *uint32 `json:"tables,omitempty"`
+}
+
+func newSnapshotIntegrity(values []string) *snapshotIntegrity {
+	return &snapshotIntegrity{
+		Count:    uint64(len(values)),
+		Checksum: valuesChecksum(values),
+	}
+}
+
+// newOrdinalIntegrity will record the values and
+// the tables of a snapshot of an Ordinal.
+func newOrdinalIntegrity(values []string, t ordinalTables) *snapshotIntegrity {
+	return newSnapshotIntegrity(values).withTables(t)
+}
+
+// withTables will record the tables of an Ordinal
+// beside the values recorded by the integrity.
+func (i *snapshotIntegrity) withTables(t ordinalTables) *snapshotIntegrity {
+	tables := t.checksum()
+	i.Tables = &tables
+	return i
+}
+
+// verify will return whether or not the values
+// match the recorded count and checksum.
+func (i *snapshotIntegrity) verify(values []string) bool {
+	return i.Count == uint64(len(values)) && i.Checksum == valuesChecksum(values)
+}
+
+// verifyOrdinal will return whether or not the values and
+// the tables match those recorded by newOrdinalIntegrity.
+func (i *snapshotIntegrity) verifyOrdinal(values []string, t ordinalTables) bool {
+	return i.verify(values) && i.verifyTables(t)
+}
+
+// verifyTables will return whether or not the
+// tables match those recorded by withTables.
+func (i *snapshotIntegrity) verifyTables(t ordinalTables) bool {
+	return i.Tables == nil || *i.Tables == t.checksum()
+}
+
+// ordinalTables are the parts of a snapshot of an Ordinal
+// beside its values that change how values are encoded.
+type ordinalTables struct {
+	aliases       map[string]string
+	preprocessors []string
+	reserved      []uint64
+	expired       []uint64
+	changes       uint64
+}
+
+// checksum will return the CRC-32 of the tables,
+// with the aliases in sorted order.
+func (t ordinalTables) checksum() uint32 {
+	var buf bytes.Buffer
+
+	aliases := make([]string, 0, len(t.aliases))
+	for alias := range t.aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	writeUvarint(&buf, uint64(len(aliases)))
+	for _, alias := range aliases {
+		writeString(&buf, alias)
+		writeString(&buf, t.aliases[alias])
+	}
+
+	writeUvarint(&buf, uint64(len(t.preprocessors)))
+	for _, name := range t.preprocessors {
+		writeString(&buf, name)
+	}
+
+	for _, codes := range [][]uint64{t.reserved, t.expired} {
+		writeUvarint(&buf, uint64(len(codes)))
+		for _, code := range codes {
+			writeUvarint(&buf, code)
+		}
+	}
+	writeUvarint(&buf, t.changes)
+
+	return crc32.ChecksumIEEE(buf.Bytes())
+}
+
+// valuesChecksum will return the CRC-32 of the
+// length-prefixed values, in order.
+func valuesChecksum(values []string) uint32 {
+	var length [binary.MaxVarintLen64]byte
+
+	h := crc32.NewIEEE()
+	for _, v := range values {
+		n := binary.PutUvarint(length[:], uint64(len(v)))
+		h.Write(length[:n])
+		h.Write([]byte(v))
+	}
+
+	return h.Sum32()
+}
+
+// rowsIntegrity accumulates the entry count and checksum of
+// the rows of a snapshot written as lines, such as JSON Lines
+// and CSV, where the record follows the last row. Rows of a
+// single field have the checksum of valuesChecksum, so values
+// can be recorded as they are streamed.
+type rowsIntegrity struct {
+	count uint64
+	hash  hash.Hash32
+}
+
+func newRowsIntegrity() *rowsIntegrity {
+	return &rowsIntegrity{hash: crc32.NewIEEE()}
+}
+
+// add will record a row of length-prefixed fields.
+func (r *rowsIntegrity) add(fields ...string) {
+	var length [binary.MaxVarintLen64]byte
+
+	r.count++
+	for _, field := range fields {
+		n := binary.PutUvarint(length[:], uint64(len(field)))
+		r.hash.Write(length[:n])
+		r.hash.Write([]byte(field))
+	}
+}
+
+func (r *rowsIntegrity) record() *snapshotIntegrity {
+	return &snapshotIntegrity{
+		Count:    r.count,
+		Checksum: r.hash.Sum32(),
+	}
+}
+
+// matches will return whether or not the
+// rows match the recorded count and checksum.
+func (r *rowsIntegrity) matches(i *snapshotIntegrity) bool {
+	return i.Count == r.count && i.Checksum == r.hash.Sum32()
+}
@@ -25,6 +25,7 @@ import (
 // JamesSteinRegression is a target-based encoder.
 type JamesSteinRegression struct {
 	encoder map[string]float64
+	missing MissingPolicy
 }
 
 // JamesSteinClassification is a one way encoder.
@@ -37,10 +38,12 @@ type JamesSteinClassification struct {
 }
 
 // NewJamesSteinRegression will create a JamesSteinRegression encoder
-func NewJamesSteinRegression(values []string, target []float64) (*JamesSteinRegression, error) {
+func NewJamesSteinRegression(values []string, target []float64, opts ...TargetOption) (*JamesSteinRegression, error) {
 	if len(target) != len(values) {
 		return &JamesSteinRegression{}, ErrTargetLength
 	}
+	o := newTargetOptions(opts)
+	values = o.categories(values)
 
 	targetValues := make(map[string]sam.SliceFloat64)
 	for i := 0; i < len(values); i++ {
@@ -54,6 +57,44 @@ func NewJamesSteinRegression(values []string, target []float64) (*JamesSteinRegr
 
 	return &JamesSteinRegression{
 		encoder: encoder,
+		missing: o.missing,
+	}, nil
+}
+
+// NewJamesSteinRegressionWeighted will create a JamesSteinRegression
+// encoder whose codes are the weighted means of the target, e.g. to
+// undo importance sampling. An `ErrWeightLength` error is returned
+// if the weights are not the same length as the values, and an
+// `ErrWeight` error if a weight is negative or not finite.
+func NewJamesSteinRegressionWeighted(values []string, target, weights []float64, opts ...TargetOption) (*JamesSteinRegression, error) {
+	if len(target) != len(values) {
+		return &JamesSteinRegression{}, ErrTargetLength
+	}
+	err := checkWeights(values, weights)
+	if err != nil {
+		return &JamesSteinRegression{}, err
+	}
+	o := newTargetOptions(opts)
+	values = o.categories(values)
+
+	sums := make(map[string]float64)
+	totals := make(map[string]float64)
+	for i := 0; i < len(values); i++ {
+		sums[values[i]] += weights[i] * target[i]
+		totals[values[i]] += weights[i]
+	}
+
+	encoder := make(map[string]float64)
+	for k, total := range totals {
+		// categories without weight were not observed
+		if total > 0 {
+			encoder[k] = sums[k] / total
+		}
+	}
+
+	return &JamesSteinRegression{
+		encoder: encoder,
+		missing: o.missing,
 	}, nil
 }
 
@@ -102,7 +143,7 @@ func NewJamesSteinClassification(values []string, target []string) (*JamesSteinC
 
 // Get will retrieve the code for the given categorical value.
 func (e *JamesSteinRegression) Get(s string) (float64, bool) {
-	v, ok := e.encoder[s]
+	v, ok := e.encoder[missingKey(e.missing, s)]
 	return v, ok
 }
 
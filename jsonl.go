@@ -0,0 +1,195 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// jsonlRow is a line of the JSON Lines form of an encoder.
+type jsonlRow struct {
+	Value string `json:"value"`
+	Code  uint64 `json:"code"`
+}
+
+// jsonlLine is a row, or the `{"count":...,"checksum":...}`
+// line recording the integrity of the rows before it.
+type jsonlLine struct {
+	jsonlRow
+	Count    *uint64 `json:"count"`
+	Checksum uint32  `json:"checksum"`
+}
+
+// WriteJSONL will write the encoder to `w` as JSON Lines, one
+// `{"value":...,"code":...}` object per line in code order,
+// followed by a line for every alias holding the code of its
+// value, so that vocabularies can be processed with streaming
+// tools and diffed line by line. The last line records the
+// number of rows and their checksum.
+func (e *Ordinal) WriteJSONL(w io.Writer) error {
+	e.RLock()
+	defer e.RUnlock()
+
+	b := bufio.NewWriter(w)
+	rows := newRowsIntegrity()
+	for code := 0; code < e.decoder.len(); code++ {
+		err := writeJSONLRow(b, rows, e.decoder.get(code), uint64(code))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, alias := range e.sortedAliases() {
+		err := writeJSONLRow(b, rows, alias, e.encoder[hashString(alias)])
+		if err != nil {
+			return err
+		}
+	}
+
+	err := writeJSONLLine(b, rows.record())
+	if err != nil {
+		return err
+	}
+
+	return b.Flush()
+}
+
+// ReadJSONL will replace the contents of the encoder with the
+// JSON Lines read from `r`. Lines may be in any order; codes
+// without a line decode to the empty string, and every line
+// holding the code of an earlier line is an alias of its value.
+// An `ErrCorruptSnapshot` error is returned if a code is not
+// below the number of lines, or if the rows do not match the
+// count and checksum of a last line recording them. Lines
+// written by other tools need not end with such a line.
+func (e *Ordinal) ReadJSONL(r io.Reader) error {
+	rows, err := readJSONLRows(r)
+	if err != nil {
+		return err
+	}
+
+	values := make([]string, len(rows), len(rows))
+	codes := make([]uint64, len(rows), len(rows))
+	for i, row := range rows {
+		values[i], codes[i] = row.Value, row.Code
+	}
+
+	e.Lock()
+	defer e.Unlock()
+
+	return e.loadRows(values, codes)
+}
+
+// WriteJSONL will write the encoder to `w` as JSON Lines, one
+// `{"value":...,"code":...}` object per line in position order,
+// where codes are the 1-based positions used by MarshalCSV,
+// followed by a line recording the number of rows and their
+// checksum.
+func (e *OneHot) WriteJSONL(w io.Writer) error {
+	b := bufio.NewWriter(w)
+	rows := newRowsIntegrity()
+	for i, value := range e.decoder {
+		err := writeJSONLRow(b, rows, value, uint64(i+1))
+		if err != nil {
+			return err
+		}
+	}
+
+	err := writeJSONLLine(b, rows.record())
+	if err != nil {
+		return err
+	}
+
+	return b.Flush()
+}
+
+// ReadJSONL will replace the values of the encoder with the
+// JSON Lines read from `r`. An `ErrBounds` error is returned
+// for a code outside the lines read, and an
+// `ErrCorruptSnapshot` error if the rows do not match the
+// line recording them.
+func (e *OneHot) ReadJSONL(r io.Reader) error {
+	rows, err := readJSONLRows(r)
+	if err != nil {
+		return err
+	}
+
+	decoder := make([]string, len(rows), len(rows))
+	for _, row := range rows {
+		if row.Code < 1 || row.Code > uint64(len(decoder)) {
+			return ErrBounds
+		}
+		decoder[row.Code-1] = row.Value
+	}
+	e.load(decoder)
+
+	return nil
+}
+
+func writeJSONLRow(w *bufio.Writer, rows *rowsIntegrity, value string, code uint64) error {
+	rows.add(value, strconv.FormatUint(code, 10))
+	return writeJSONLLine(w, jsonlRow{Value: value, Code: code})
+}
+
+func writeJSONLLine(w *bufio.Writer, v interface{}) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(line)
+	if err != nil {
+		return err
+	}
+
+	return w.WriteByte('\n')
+}
+
+func readJSONLRows(r io.Reader) ([]jsonlRow, error) {
+	dec := json.NewDecoder(bufio.NewReader(r))
+
+	rows := make([]jsonlRow, 0)
+	integrity := newRowsIntegrity()
+	var recorded *snapshotIntegrity
+	for {
+		var line jsonlLine
+		err := dec.Decode(&line)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		// nothing follows the integrity record
+		if recorded != nil {
+			return nil, ErrCorruptSnapshot
+		}
+
+		if line.Count != nil {
+			recorded = &snapshotIntegrity{Count: *line.Count, Checksum: line.Checksum}
+			continue
+		}
+		integrity.add(line.Value, strconv.FormatUint(line.Code, 10))
+		rows = append(rows, line.jsonlRow)
+	}
+
+	if recorded != nil && !integrity.matches(recorded) {
+		return nil, ErrCorruptSnapshot
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,71 @@
+package encoder
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestOrdinalJSONL(t *testing.T) {
+	encoder := NewOrdinal(false)
+	encoder.EncodeSlice([]string{"red", "blue"})
+	encoder.Alias("red", "rouge")
+
+	var buf bytes.Buffer
+	if err := encoder.WriteJSONL(&buf); err != nil {
+		t.Fatalf("write error: %+v", err)
+	}
+	expected := "{\"value\":\"red\",\"code\":0}\n{\"value\":\"blue\",\"code\":1}\n{\"value\":\"rouge\",\"code\":0}\n{\"count\":3,"
+	if !strings.HasPrefix(buf.String(), expected) {
+		t.Errorf("unexpected JSON Lines %q", buf.String())
+	}
+	corrupt := strings.Replace(buf.String(), "blue", "bleu", 1)
+	if err := NewOrdinal(false).ReadJSONL(strings.NewReader(corrupt)); err != ErrCorruptSnapshot {
+		t.Errorf("expected corrupt snapshot error for modified rows, got %v", err)
+	}
+	truncated := strings.Replace(buf.String(), "{\"value\":\"rouge\",\"code\":0}\n", "", 1)
+	if err := NewOrdinal(false).ReadJSONL(strings.NewReader(truncated)); err != ErrCorruptSnapshot {
+		t.Errorf("expected corrupt snapshot error for a missing row, got %v", err)
+	}
+
+	loaded := NewOrdinal(false)
+	if err := loaded.ReadJSONL(&buf); err != nil {
+		t.Fatalf("read error: %+v", err)
+	}
+	if loaded.Decode(1) != "blue" || loaded.Encode("rouge") != 0 || loaded.Length() != 2 {
+		t.Error("JSON Lines did not round trip")
+	}
+
+	if err := loaded.ReadJSONL(strings.NewReader("{\"value\":")); err == nil {
+		t.Error("expected error for truncated input")
+	}
+	huge := "{\"value\":\"red\",\"code\":18446744073709551615}\n"
+	if err := loaded.ReadJSONL(strings.NewReader(huge)); err != ErrCorruptSnapshot {
+		t.Errorf("error was %+v and not a corrupt snapshot error", err)
+	}
+	if loaded.Decode(1) != "blue" {
+		t.Error("rejected input changed the encoder")
+	}
+}
+
+func TestOneHotJSONL(t *testing.T) {
+	encoder := NewOneHot()
+	encoder.Encode("red")
+
+	var buf bytes.Buffer
+	if err := encoder.WriteJSONL(&buf); err != nil {
+		t.Fatalf("write error: %+v", err)
+	}
+
+	loaded := NewOneHot()
+	if err := loaded.ReadJSONL(&buf); err != nil {
+		t.Fatalf("read error: %+v", err)
+	}
+	if col, ok := loaded.Index("red"); !ok || col != 1 || loaded.Dimension() != 2 {
+		t.Error("JSON Lines did not round trip")
+	}
+
+	if err := loaded.ReadJSONL(strings.NewReader(`{"value":"red","code":3}`)); err != ErrBounds {
+		t.Error("expected bounds error")
+	}
+}
@@ -0,0 +1,314 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+)
+
+// kerasLayer is the serialized form of a Keras layer,
+// as found in a model's JSON.
+type kerasLayer struct {
+	ClassName string          `json:"class_name"`
+	Config    json.RawMessage `json:"config"`
+}
+
+// kerasLookupConfig holds the fields of a Keras
+// StringLookup or TextVectorization config that
+// determine its vocabulary indices.
+type kerasLookupConfig struct {
+	Vocabulary           json.RawMessage `json:"vocabulary"`
+	MaskToken            *string         `json:"mask_token"`
+	OOVToken             *string         `json:"oov_token"`
+	NumOOVIndices        *int            `json:"num_oov_indices"`
+	Standardize          json.RawMessage `json:"standardize"`
+	Split                json.RawMessage `json:"split"`
+	Ngrams               json.RawMessage `json:"ngrams"`
+	OutputMode           *string         `json:"output_mode"`
+	OutputSequenceLength *int            `json:"output_sequence_length"`
+}
+
+// kerasStandardize maps the standardize options of
+// TextVectorization to registered preprocessors.
+var kerasStandardize = map[string][]string{
+	"lower":                       {"keras_lowercase"},
+	"strip_punctuation":           {"keras_strip_punctuation"},
+	"lower_and_strip_punctuation": {"keras_lowercase", "keras_strip_punctuation"},
+}
+
+// kerasPunctuation is the set of characters removed by the
+// strip_punctuation standardization of TextVectorization,
+// those of the character class of its DEFAULT_STRIP_REGEX.
+const kerasPunctuation = "!\"#$%&()*+,-./:;<=>?@[\\]^_`{|}~'"
+
+// KerasLowercase will lowercase the ASCII letters of the
+// string, leaving every other character unchanged, like
+// the lower standardization of TextVectorization.
+func KerasLowercase(s string) string {
+	return strings.Map(func(r rune) rune {
+		if 'A' <= r && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}, s)
+}
+
+// KerasStripPunctuation will remove the ASCII punctuation
+// characters of the string, like the strip_punctuation
+// standardization of TextVectorization. Unlike
+// StripPunctuation, other punctuation is kept.
+func KerasStripPunctuation(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < utf8.RuneSelf && strings.ContainsRune(kerasPunctuation, r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// NewOrdinalFromKeras will create an ordinal encoder that assigns
+// the same indices as a Keras StringLookup or TextVectorization
+// layer. The config is the layer's `get_config()` as JSON, or the
+// layer's entry in a model JSON (`{"class_name": ..., "config": ...}`).
+//
+// Like Keras, the mask token (if any) is code 0, followed by the
+// OOV token and then the vocabulary. Values outside the vocabulary
+// are given the OOV token's index by Keras, so lookups should use
+// a Snapshot and fall back to the code of the OOV token instead of
+// encoding new values. The standardize option of TextVectorization
+// is applied with the equivalent preprocessors. TextVectorization
+// layers split text into tokens before looking them up; use
+// KerasTextVectorization to transform text rather than tokens.
+//
+// An `ErrUnsupported` error is returned for layers with more than
+// one OOV index, which Keras assigns by hashing, or with a custom
+// standardize function, and an `ErrFormat` error if the vocabulary
+// is not in the config (such as a path to a vocabulary file).
+func NewOrdinalFromKeras(config []byte, opts ...OrdinalOption) (*Ordinal, error) {
+	className, c, err := parseKerasLayer(config)
+	if err != nil {
+		return NewOrdinal(false), err
+	}
+
+	return ordinalFromKeras(className, c, opts...)
+}
+
+// parseKerasLayer will return the class name and the
+// lookup config of a layer config or model JSON entry.
+func parseKerasLayer(config []byte) (string, kerasLookupConfig, error) {
+	var c kerasLookupConfig
+
+	var layer kerasLayer
+	err := json.Unmarshal(config, &layer)
+	if err != nil {
+		return "", c, err
+	}
+	if layer.ClassName == "" || layer.Config == nil {
+		layer.Config = config
+	}
+
+	err = json.Unmarshal(layer.Config, &c)
+	return layer.ClassName, c, err
+}
+
+func ordinalFromKeras(className string, c kerasLookupConfig, opts ...OrdinalOption) (*Ordinal, error) {
+	var vocabulary []string
+	err := json.Unmarshal(c.Vocabulary, &vocabulary)
+	if err != nil || vocabulary == nil {
+		return NewOrdinal(false), ErrFormat
+	}
+
+	// TextVectorization always masks the empty string
+	// and has a single OOV index.
+	mask, oov, numOOV := c.MaskToken, "[UNK]", 1
+	if className == "TextVectorization" {
+		empty := ""
+		mask = &empty
+	}
+	if c.OOVToken != nil {
+		oov = *c.OOVToken
+	}
+	if c.NumOOVIndices != nil {
+		numOOV = *c.NumOOVIndices
+	}
+	if numOOV > 1 {
+		return NewOrdinal(false), ErrUnsupported
+	}
+
+	values := make([]string, 0, len(vocabulary)+2)
+	if mask != nil {
+		values = append(values, *mask)
+	}
+	if numOOV == 1 {
+		values = append(values, oov)
+	}
+	values = append(values, vocabulary...)
+
+	m := make(map[string]uint64, len(values))
+	for code, v := range values {
+		if _, ok := m[v]; ok {
+			return NewOrdinal(false), ErrDuplicateValue
+		}
+		m[v] = uint64(code)
+	}
+
+	e, err := NewOrdinalFromMap(m, opts...)
+	if err != nil {
+		return e, err
+	}
+
+	// TextVectorization standardizes by default,
+	// and null does not standardize the text
+	standardize := ""
+	if len(c.Standardize) == 0 && className == "TextVectorization" {
+		standardize = "lower_and_strip_punctuation"
+	}
+	if len(c.Standardize) > 0 && string(c.Standardize) != "null" {
+		err = json.Unmarshal(c.Standardize, &standardize)
+		if err != nil {
+			return e, ErrUnsupported
+		}
+	}
+
+	if standardize != "" {
+		names, ok := kerasStandardize[standardize]
+		if !ok {
+			return e, ErrUnsupported
+		}
+		err = e.SetPreprocessors(names...)
+	}
+
+	return e, err
+}
+
+// KerasTextVectorization will transform text into token indices
+// like a Keras TextVectorization layer with the "int" output mode:
+// the text is standardized, split into tokens, and every token is
+// looked up, with tokens outside the vocabulary given the index of
+// the OOV token.
+type KerasTextVectorization struct {
+	lookup *ReadOnlyOrdinal
+	split  string
+	length int
+	oov    uint64
+}
+
+// NewKerasTextVectorization will create a KerasTextVectorization
+// from the config of a TextVectorization layer, in the forms
+// accepted by NewOrdinalFromKeras. Splitting on whitespace, by
+// character or not at all is supported, as is padding or
+// truncating to `output_sequence_length`.
+//
+// An `ErrUnsupported` error is returned for layers with another
+// output mode, ngrams or a custom split function, and the errors
+// of NewOrdinalFromKeras for the vocabulary.
+func NewKerasTextVectorization(config []byte) (*KerasTextVectorization, error) {
+	_, c, err := parseKerasLayer(config)
+	if err != nil {
+		return &KerasTextVectorization{}, err
+	}
+
+	// the split option defaults to whitespace,
+	// and null does not split the text
+	split := "whitespace"
+	if len(c.Split) > 0 && string(c.Split) != "null" {
+		err = json.Unmarshal(c.Split, &split)
+		if err != nil {
+			return &KerasTextVectorization{}, ErrUnsupported
+		}
+	} else if len(c.Split) > 0 {
+		split = ""
+	}
+	if split != "whitespace" && split != "character" && split != "" {
+		return &KerasTextVectorization{}, ErrUnsupported
+	}
+	if c.OutputMode != nil && *c.OutputMode != "int" {
+		return &KerasTextVectorization{}, ErrUnsupported
+	}
+	if len(c.Ngrams) > 0 && string(c.Ngrams) != "null" {
+		return &KerasTextVectorization{}, ErrUnsupported
+	}
+
+	e, err := ordinalFromKeras("TextVectorization", c)
+	if err != nil {
+		return &KerasTextVectorization{}, err
+	}
+
+	v := &KerasTextVectorization{
+		lookup: e.Snapshot(),
+		split:  split,
+	}
+	if c.OutputSequenceLength != nil {
+		v.length = *c.OutputSequenceLength
+	}
+	// the OOV token follows the mask token
+	v.oov = 1
+
+	return v, nil
+}
+
+// Tokens will return the standardized tokens of the text,
+// split the way the layer splits them.
+func (v *KerasTextVectorization) Tokens(text string) []string {
+	text = normalize(v.lookup.normalize, text)
+
+	switch v.split {
+	case "whitespace":
+		return strings.FieldsFunc(text, isASCIISpace)
+	case "character":
+		tokens := make([]string, 0, len(text))
+		for _, r := range text {
+			tokens = append(tokens, string(r))
+		}
+		return tokens
+	default:
+		return []string{text}
+	}
+}
+
+// Transform will return the index of every token of the text.
+// If the layer has an output sequence length the indices are
+// truncated or padded with the mask index 0 to that length.
+func (v *KerasTextVectorization) Transform(text string) []uint64 {
+	tokens := v.Tokens(text)
+
+	length := len(tokens)
+	if v.length > 0 {
+		length = v.length
+	}
+
+	codes := make([]uint64, length, length)
+	for i := 0; i < len(tokens) && i < length; i++ {
+		code, ok := v.lookup.Lookup(tokens[i])
+		if !ok {
+			code = v.oov
+		}
+		codes[i] = code
+	}
+
+	return codes
+}
+
+// isASCIISpace will return whether the rune is one of the
+// ASCII whitespace characters TensorFlow splits text on.
+func isASCIISpace(r rune) bool {
+	switch r {
+	case ' ', '\t', '\n', '\v', '\f', '\r':
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,101 @@
+package encoder
+
+import "testing"
+
+func TestNewOrdinalFromKeras(t *testing.T) {
+	e, err := NewOrdinalFromKeras([]byte(`{
+		"name": "string_lookup",
+		"num_oov_indices": 1,
+		"mask_token": null,
+		"oov_token": "[UNK]",
+		"vocabulary": ["red", "green", "blue"],
+		"output_mode": "int"
+	}`))
+	if err != nil {
+		t.Fatalf("load error: %+v", err)
+	}
+	expected := []string{"[UNK]", "red", "green", "blue"}
+	for code, v := range expected {
+		if e.Decode(uint64(code)) != v {
+			t.Errorf("code %d was %s and not %s", code, e.Decode(uint64(code)), v)
+		}
+	}
+
+	e, err = NewOrdinalFromKeras([]byte(`{
+		"class_name": "TextVectorization",
+		"config": {"standardize": "lower_and_strip_punctuation", "vocabulary": ["the", "cat"]}
+	}`))
+	if err != nil {
+		t.Fatalf("load error: %+v", err)
+	}
+	if e.Decode(0) != "" || e.Decode(1) != "[UNK]" || e.Decode(3) != "cat" {
+		t.Errorf("unexpected TextVectorization indices %v", e.List())
+	}
+	if code, ok := e.Snapshot().Lookup("Cat!"); !ok || code != 3 {
+		t.Error("standardize was not applied")
+	}
+
+	if _, err := NewOrdinalFromKeras([]byte(`{"num_oov_indices": 2, "vocabulary": ["a"]}`)); err != ErrUnsupported {
+		t.Error("expected unsupported error")
+	}
+	if _, err := NewOrdinalFromKeras([]byte(`{"vocabulary": "/tmp/vocab.txt"}`)); err != ErrFormat {
+		t.Error("expected format error")
+	}
+}
+
+func TestKerasStandardize(t *testing.T) {
+	// tf.strings.lower only lowercases ASCII and the strip
+	// regex only matches ASCII punctuation
+	cases := map[string]string{
+		"The Cat-sat, on [the] mat.": "the catsat on the mat",
+		"ÉTÉ ¿Qué?":                  "ÉtÉ ¿qué",
+		`a\b'c"d_e`:                  "abcde",
+	}
+	for input, expected := range cases {
+		actual := KerasStripPunctuation(KerasLowercase(input))
+		if actual != expected {
+			t.Errorf("standardized %q was %q and not %q", input, actual, expected)
+		}
+	}
+}
+
+func TestKerasTextVectorization(t *testing.T) {
+	v, err := NewKerasTextVectorization([]byte(`{
+		"class_name": "TextVectorization",
+		"config": {
+			"standardize": "lower_and_strip_punctuation",
+			"split": "whitespace",
+			"output_mode": "int",
+			"output_sequence_length": 8,
+			"vocabulary": ["the", "cat", "sat", "on", "mat"]
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("load error: %+v", err)
+	}
+
+	// the indices Keras returns for the same layer
+	expected := []uint64{2, 3, 4, 5, 2, 6, 1, 0}
+	codes := v.Transform("The cat sat on\tthe mat. ¿Dog?")
+	if len(codes) != len(expected) {
+		t.Fatalf("codes were %v and not %v", codes, expected)
+	}
+	for i := range expected {
+		if codes[i] != expected[i] {
+			t.Errorf("codes were %v and not %v", codes, expected)
+			break
+		}
+	}
+
+	v, err = NewKerasTextVectorization([]byte(`{"split": "character", "standardize": null, "vocabulary": ["a", "b"]}`))
+	if err != nil {
+		t.Fatalf("load error: %+v", err)
+	}
+	if codes := v.Transform("abA"); len(codes) != 3 || codes[1] != 3 || codes[2] != 1 {
+		t.Errorf("character codes were %v and not [2 3 1]", codes)
+	}
+
+	if _, err := NewKerasTextVectorization([]byte(`{"output_mode": "tf_idf", "vocabulary": ["a"]}`)); err != ErrUnsupported {
+		t.Error("expected unsupported error")
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// LagStats is a one-way encoder.
+// LagStats creates lag and rolling-statistics features
+// for grouped time series: every observation is given the
+// values of the previous observations in its group and the
+// mean, min, max and standard deviation of its group's
+// last `window` values. Only strictly earlier observations
+// are used, so the current value never leaks into its own
+// features.
+type LagStats struct {
+	lags     []int
+	window   int
+	features [][]float64
+}
+
+// NewLagStats will create the features for every observation,
+// in the order of the observations. Observations are ordered
+// by time within their group. Features without enough earlier
+// observations are NaN. The standard deviation is the population
+// standard deviation of the window.
+func NewLagStats(groups []string, times []time.Time, values []float64, lags []int, window int) (*LagStats, error) {
+	if len(times) != len(groups) || len(values) != len(groups) {
+		return &LagStats{}, ErrLength
+	}
+	for _, k := range lags {
+		if k < 1 {
+			return &LagStats{}, ErrBounds
+		}
+	}
+	if window < 1 {
+		return &LagStats{}, ErrCapacity
+	}
+
+	order := make([]int, len(groups), len(groups))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return times[order[a]].Before(times[order[b]])
+	})
+
+	e := &LagStats{
+		lags:     append([]int{}, lags...),
+		window:   window,
+		features: make([][]float64, len(groups), len(groups)),
+	}
+
+	seen := make(map[string][]float64)
+	for _, i := range order {
+		previous := seen[groups[i]]
+		e.features[i] = e.row(previous)
+		seen[groups[i]] = append(previous, values[i])
+	}
+
+	return e, nil
+}
+
+// row will return the features for an observation
+// preceded by the previous values of its group.
+func (e *LagStats) row(previous []float64) []float64 {
+	row := make([]float64, 0, len(e.lags)+4)
+	for _, k := range e.lags {
+		if k > len(previous) {
+			row = append(row, math.NaN())
+			continue
+		}
+		row = append(row, previous[len(previous)-k])
+	}
+
+	if len(previous) == 0 {
+		return append(row, math.NaN(), math.NaN(), math.NaN(), math.NaN())
+	}
+
+	start := len(previous) - e.window
+	if start < 0 {
+		start = 0
+	}
+	window := previous[start:]
+
+	var sum, squares float64
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range window {
+		sum += v
+		squares += v * v
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	n := float64(len(window))
+	mean := sum / n
+	variance := math.Max(squares/n-mean*mean, 0)
+
+	return append(row, mean, min, max, math.Sqrt(variance))
+}
+
+// Features will return the features of every observation
+// in the order of the observations provided in the creation
+// of the LagStats encoder.
+func (e *LagStats) Features() [][]float64 {
+	return e.features
+}
+
+// Get will return the features for the given index, according
+// to the original slice of observations provided in the
+// construction of the LagStats encoder.
+func (e *LagStats) Get(index int) ([]float64, error) {
+	if index < 0 || index > len(e.features)-1 {
+		return []float64{}, ErrBounds
+	}
+
+	return e.features[index], nil
+}
+
+// FeatureNames will return a name for every feature, in order,
+// of the form `column_lag_k` followed by `column_rolling_mean`,
+// `column_rolling_min`, `column_rolling_max` and `column_rolling_std`.
+func (e *LagStats) FeatureNames(column string) []string {
+	names := make([]string, 0, len(e.lags)+4)
+	for _, k := range e.lags {
+		names = append(names, column+"_lag_"+strconv.Itoa(k))
+	}
+
+	return append(names,
+		column+"_rolling_mean",
+		column+"_rolling_min",
+		column+"_rolling_max",
+		column+"_rolling_std",
+	)
+}
+
+// Window will return the window used when
+// creating the LagStats encoder.
+func (e *LagStats) Window() int {
+	return e.window
+}
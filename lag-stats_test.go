@@ -0,0 +1,50 @@
+package encoder
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLagStats(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2020, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+	groups := []string{"x", "y", "x", "x", "x"}
+	times := []time.Time{day(2), day(1), day(1), day(4), day(3)}
+	values := []float64{2, 10, 1, 6, 3}
+
+	e, err := NewLagStats(groups, times, values, []int{1, 2}, 2)
+	if err != nil {
+		t.Fatalf("create error: %+v", err)
+	}
+
+	names := e.FeatureNames("sales")
+	if len(names) != 6 || names[0] != "sales_lag_1" || names[5] != "sales_rolling_std" {
+		t.Errorf("unexpected feature names: %v", names)
+	}
+
+	first, _ := e.Get(2)
+	for _, f := range first {
+		if !math.IsNaN(f) {
+			t.Errorf("first observation of a group has features %v", first)
+			break
+		}
+	}
+
+	// "x" on day 4 is preceded by 1, 2 and 3.
+	last, _ := e.Get(3)
+	expected := []float64{3, 2, 2.5, 2, 3, 0.5}
+	for i := range expected {
+		if math.Abs(last[i]-expected[i]) > 1e-9 {
+			t.Errorf("feature %s was %f and not %f", names[i], last[i], expected[i])
+		}
+	}
+
+	if _, err := e.Get(5); err != ErrBounds {
+		t.Error("expected bounds error")
+	}
+	if _, err := NewLagStats(groups, times, values[:1], nil, 2); err != ErrLength {
+		t.Error("expected length error")
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+// The encoders implement encoding.TextMarshaler with their
+// JSON form and encoding.BinaryMarshaler with their Gob form,
+// so they compose with standard library packages that only
+// know those interfaces.
+
+// MarshalText ...
+func (e *Ordinal) MarshalText() ([]byte, error) {
+	return e.MarshalJSON()
+}
+
+// UnmarshalText ...
+func (e *Ordinal) UnmarshalText(data []byte) error {
+	return e.UnmarshalJSON(data)
+}
+
+// MarshalBinary ...
+func (e *Ordinal) MarshalBinary() ([]byte, error) {
+	return e.GobEncode()
+}
+
+// UnmarshalBinary ...
+func (e *Ordinal) UnmarshalBinary(data []byte) error {
+	return e.GobDecode(data)
+}
+
+// MarshalText ...
+func (e *OneHot) MarshalText() ([]byte, error) {
+	return e.MarshalJSON()
+}
+
+// UnmarshalText ...
+func (e *OneHot) UnmarshalText(data []byte) error {
+	return e.UnmarshalJSON(data)
+}
+
+// MarshalBinary ...
+func (e *OneHot) MarshalBinary() ([]byte, error) {
+	return e.GobEncode()
+}
+
+// UnmarshalBinary ...
+func (e *OneHot) UnmarshalBinary(data []byte) error {
+	return e.GobDecode(data)
+}
+
+// MarshalText ...
+func (e *TrieOrdinal) MarshalText() ([]byte, error) {
+	return e.MarshalJSON()
+}
+
+// UnmarshalText ...
+func (e *TrieOrdinal) UnmarshalText(data []byte) error {
+	return e.UnmarshalJSON(data)
+}
+
+// MarshalBinary will encode the values,
+// indexed by code, with Gob.
+func (e *TrieOrdinal) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(e.List())
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary ...
+func (e *TrieOrdinal) UnmarshalBinary(data []byte) error {
+	var values []string
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&values)
+	if err != nil {
+		return err
+	}
+
+	e.load(values)
+	return nil
+}
+
+// MarshalText ...
+func (f *BloomFilter) MarshalText() ([]byte, error) {
+	return f.MarshalJSON()
+}
+
+// UnmarshalText ...
+func (f *BloomFilter) UnmarshalText(data []byte) error {
+	return f.UnmarshalJSON(data)
+}
+
+// MarshalBinary ...
+func (f *BloomFilter) MarshalBinary() ([]byte, error) {
+	return f.GobEncode()
+}
+
+// UnmarshalBinary ...
+func (f *BloomFilter) UnmarshalBinary(data []byte) error {
+	return f.GobDecode(data)
+}
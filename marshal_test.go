@@ -0,0 +1,56 @@
+package encoder
+
+import (
+	"encoding"
+	"testing"
+)
+
+var (
+	_ encoding.TextMarshaler     = &Ordinal{}
+	_ encoding.TextUnmarshaler   = &Ordinal{}
+	_ encoding.BinaryMarshaler   = &Ordinal{}
+	_ encoding.BinaryUnmarshaler = &Ordinal{}
+	_ encoding.TextMarshaler     = &OneHot{}
+	_ encoding.BinaryUnmarshaler = &OneHot{}
+	_ encoding.TextMarshaler     = &TrieOrdinal{}
+	_ encoding.BinaryUnmarshaler = &TrieOrdinal{}
+	_ encoding.TextMarshaler     = &BloomFilter{}
+	_ encoding.BinaryUnmarshaler = &BloomFilter{}
+)
+
+func TestOrdinalMarshalText(t *testing.T) {
+	encoder := NewOrdinal(true)
+	encoder.Encode("red")
+
+	text, err := encoder.MarshalText()
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	fromText := NewOrdinal(false)
+	if err := fromText.UnmarshalText(text); err != nil || fromText.Decode(1) != "red" {
+		t.Error("text did not round trip")
+	}
+
+	b, err := encoder.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	fromBinary := NewOrdinal(false)
+	if err := fromBinary.UnmarshalBinary(b); err != nil || fromBinary.Decode(1) != "red" {
+		t.Error("binary did not round trip")
+	}
+}
+
+func TestTrieOrdinalMarshalBinary(t *testing.T) {
+	encoder := NewTrieOrdinal(false)
+	encoder.EncodeSlice([]string{"/a", "/a/b"})
+
+	b, err := encoder.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	var loaded TrieOrdinal
+	if err := loaded.UnmarshalBinary(b); err != nil || loaded.Decode(1) != "/a/b" {
+		t.Error("binary did not round trip")
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"github.com/humilityai/sam"
+)
+
+// Setter is implemented by matrices that can have
+// individual elements assigned, such as gonum's
+// `*mat.Dense`, so that encoders can write directly
+// into them without an intermediate representation.
+type Setter interface {
+	Set(i, j int, v float64)
+}
+
+// CSR is a compressed sparse row matrix.
+// The column indices and values of row `i` are
+// `Indices[IndPtr[i]:IndPtr[i+1]]` and `Data[IndPtr[i]:IndPtr[i+1]]`.
+// The fields follow the layout expected by most
+// sparse matrix libraries (e.g. `sparse.NewCSR(Rows, Cols, IndPtr, Indices, Data)`).
+type CSR struct {
+	Rows    int
+	Cols    int
+	IndPtr  []int
+	Indices []int
+	Data    []float64
+}
+
+// Fill will set the hot dimension of the codeword of
+// every value in the slice of strings to 1 in the matrix `m`.
+// Row `i` of the matrix is the codeword of `s[i]`, starting at
+// column `col`, so several encoders can fill adjacent column
+// blocks of the same matrix. Every other element is left
+// untouched, so `m` should already be zeroed.
+func (e *OneHot) Fill(m Setter, col int, s sam.SliceString) {
+	rows, cols := e.Indices(s)
+	for i := range rows {
+		m.Set(rows[i], col+cols[i], 1)
+	}
+}
+
+// CSR will return the codewords of every value in the
+// slice of strings as a sparse matrix with `len(s)` rows
+// and `Dimension()` columns.
+func (e *OneHot) CSR(s sam.SliceString) *CSR {
+	rows, cols := e.Indices(s)
+
+	m := &CSR{
+		Rows:    len(s),
+		Cols:    e.Dimension(),
+		IndPtr:  make([]int, len(s)+1, len(s)+1),
+		Indices: cols,
+		Data:    make([]float64, len(cols), len(cols)),
+	}
+
+	for i, row := range rows {
+		m.IndPtr[row+1]++
+		m.Data[i] = 1
+	}
+	for i := 0; i < len(s); i++ {
+		m.IndPtr[i+1] += m.IndPtr[i]
+	}
+
+	return m
+}
@@ -0,0 +1,48 @@
+package encoder
+
+import (
+	"testing"
+)
+
+type testDense struct {
+	cols int
+	data []float64
+}
+
+func (m *testDense) Set(i, j int, v float64) {
+	m.data[i*m.cols+j] = v
+}
+
+func TestOneHotFill(t *testing.T) {
+	encoder := NewOneHot()
+	encoder.Encode("red")
+	encoder.Encode("blue")
+
+	m := &testDense{cols: 4, data: make([]float64, 8)}
+	encoder.Fill(m, 1, []string{"blue", "red"})
+
+	expected := []float64{0, 0, 0, 1, 0, 0, 1, 0}
+	for i, v := range expected {
+		if m.data[i] != v {
+			t.Fatalf("matrix was %v and not %v", m.data, expected)
+		}
+	}
+}
+
+func TestOneHotCSR(t *testing.T) {
+	encoder := NewOneHot()
+	encoder.Encode("red")
+	encoder.Encode("blue")
+
+	m := encoder.CSR([]string{"blue", "green", "red"})
+	if m.Rows != 3 || m.Cols != 3 {
+		t.Errorf("matrix shape was %dx%d and not 3x3", m.Rows, m.Cols)
+	}
+
+	expected := []int{0, 1, 1, 2}
+	for i, v := range expected {
+		if m.IndPtr[i] != v {
+			t.Fatalf("row pointers were %v and not %v", m.IndPtr, expected)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"time"
+)
+
+// Metrics receives instrumentation from an encoder.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// Encoded is called for every encoded value, with
+	// whether the value already had a code.
+	Encoded(known bool)
+	// VocabularySize is called with the number of
+	// encoded values whenever it changes.
+	VocabularySize(n int)
+	// LockWait is called with the time spent waiting
+	// for the encoder's write lock.
+	LockWait(d time.Duration)
+}
+
+// Counter is a monotonically increasing metric,
+// such as a `prometheus.Counter`.
+type Counter interface {
+	Inc()
+}
+
+// Gauge is a metric that can be set to any value,
+// such as a `prometheus.Gauge`.
+type Gauge interface {
+	Set(float64)
+}
+
+// Observer is a metric that records a distribution of
+// observations, such as a `prometheus.Histogram`.
+type Observer interface {
+	Observe(float64)
+}
+
+// PrometheusMetrics is a Metrics hook that reports to
+// Prometheus metrics. The unknown rate can be queried as
+// `rate(unknown) / rate(encodes)`.
+// Any of the metrics may be nil, in which case
+// it is not reported.
+type PrometheusMetrics struct {
+	// Encodes counts every encoded value.
+	Encodes Counter
+	// Unknown counts every encoded value
+	// that did not already have a code.
+	Unknown Counter
+	// Size is the number of encoded values.
+	Size Gauge
+	// LockWaitSeconds observes the time spent
+	// waiting for the write lock, in seconds.
+	LockWaitSeconds Observer
+}
+
+// Encoded ...
+func (m *PrometheusMetrics) Encoded(known bool) {
+	if m.Encodes != nil {
+		m.Encodes.Inc()
+	}
+	if !known && m.Unknown != nil {
+		m.Unknown.Inc()
+	}
+}
+
+// VocabularySize ...
+func (m *PrometheusMetrics) VocabularySize(n int) {
+	if m.Size != nil {
+		m.Size.Set(float64(n))
+	}
+}
+
+// LockWait ...
+func (m *PrometheusMetrics) LockWait(d time.Duration) {
+	if m.LockWaitSeconds != nil {
+		m.LockWaitSeconds.Observe(d.Seconds())
+	}
+}
@@ -0,0 +1,41 @@
+package encoder
+
+import (
+	"testing"
+)
+
+type testMetric struct {
+	count        int
+	value        float64
+	observations int
+}
+
+func (m *testMetric) Inc()              { m.count++ }
+func (m *testMetric) Set(v float64)     { m.value = v }
+func (m *testMetric) Observe(v float64) { m.observations++ }
+
+func TestOrdinalMetrics(t *testing.T) {
+	encodes := &testMetric{}
+	unknown := &testMetric{}
+	size := &testMetric{}
+	wait := &testMetric{}
+
+	encoder := NewOrdinal(true, WithMetrics(&PrometheusMetrics{
+		Encodes:         encodes,
+		Unknown:         unknown,
+		Size:            size,
+		LockWaitSeconds: wait,
+	}))
+	encoder.Encode("red")
+	encoder.Encode("red")
+
+	if encodes.count != 3 || unknown.count != 2 {
+		t.Errorf("counted %d encodes and %d unknown and not 3 and 2", encodes.count, unknown.count)
+	}
+	if size.value != 2 {
+		t.Errorf("size was %f and not 2", size.value)
+	}
+	if wait.observations != 3 {
+		t.Errorf("observed %d lock waits and not 3", wait.observations)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/humilityai/sam"
+)
+
+// MissingCode is the code of missing values in
+// Ordinal encoders created with `WithMissing`.
+const MissingCode uint64 = 0
+
+// missingCategory is the category every missing value is
+// encoded as, so that missing values never share a code,
+// dimension or statistic with observed values, including
+// the empty string. Observed values are not expected to
+// hold NUL bytes.
+const missingCategory = "\x00missing"
+
+var missingHash = hashString(missingCategory)
+
+// MissingPolicy reports whether a raw value
+// represents a missing observation.
+type MissingPolicy func(s string) bool
+
+// TargetOption configures optional behaviour of
+// a target encoder at construction time.
+type TargetOption func(*targetOptions)
+
+type targetOptions struct {
+	missing MissingPolicy
+}
+
+// WithTargetMissing will fit every value considered missing by
+// the policy as a single category of its own, separate from
+// every observed category, and encode missing values with the
+// statistics of that category. The empty string is only
+// missing if the policy says so.
+func WithTargetMissing(policy MissingPolicy) TargetOption {
+	return func(o *targetOptions) {
+		o.missing = policy
+	}
+}
+
+func newTargetOptions(opts []TargetOption) targetOptions {
+	var o targetOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// categories will return the values with every
+// missing value replaced by the missing category.
+func (o targetOptions) categories(values []string) []string {
+	if o.missing == nil {
+		return values
+	}
+
+	categories := make([]string, len(values), len(values))
+	for i, v := range values {
+		categories[i] = missingKey(o.missing, v)
+	}
+
+	return categories
+}
+
+// missingKey will return the category of the value
+// under the policy, which may be nil.
+func missingKey(policy MissingPolicy, s string) string {
+	if policy != nil && policy(s) {
+		return missingCategory
+	}
+
+	return s
+}
+
+// DefaultMissing considers the empty string and the
+// common textual spellings of missing data produced by
+// CSV and dataframe exports as missing.
+func DefaultMissing(s string) bool {
+	switch strings.TrimSpace(s) {
+	case "", "NA", "N/A", "NaN", "nan", "null", "NULL", "None":
+		return true
+	}
+
+	return false
+}
+
+// MissingIndicator will return an indicator column for the
+// given values: 1 where the value is missing according to
+// the policy and 0 everywhere else.
+func MissingIndicator(values sam.SliceString, policy MissingPolicy) []uint8 {
+	indicator := make([]uint8, len(values), len(values))
+	for i, v := range values {
+		if policy(v) {
+			indicator[i] = 1
+		}
+	}
+
+	return indicator
+}
+
+// ImputeMean will parse the given values as numbers, replacing
+// every value that is missing according to the policy with the
+// mean of the non-missing values. The mean is also returned so
+// that it can be reused to impute values at inference time.
+// If every value is missing the mean is 0.
+func ImputeMean(values sam.SliceString, policy MissingPolicy) (sam.SliceFloat64, float64, error) {
+	parsed := make(sam.SliceFloat64, len(values), len(values))
+	observed := make(sam.SliceFloat64, 0, len(values))
+	for i, v := range values {
+		if policy(v) {
+			continue
+		}
+
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return sam.SliceFloat64{}, 0, err
+		}
+		parsed[i] = f
+		observed = append(observed, f)
+	}
+
+	mean := observed.Avg()
+	for i, v := range values {
+		if policy(v) {
+			parsed[i] = mean
+		}
+	}
+
+	return parsed, mean, nil
+}
@@ -0,0 +1,98 @@
+package encoder
+
+import (
+	"testing"
+)
+
+func TestOrdinalMissing(t *testing.T) {
+	encoder := NewOrdinal(false, WithMissing(DefaultMissing))
+	if code := encoder.Encode("NA"); code != MissingCode {
+		t.Errorf("missing code was %d and not %d", code, MissingCode)
+	}
+
+	if code := encoder.Encode("red"); code != 1 {
+		t.Errorf("code was %d and not 1", code)
+	}
+
+	if !encoder.Contains("null") {
+		t.Error("encoder should contain missing values")
+	}
+}
+
+func TestOneHotMissing(t *testing.T) {
+	encoder := NewOneHot(WithOneHotMissing(DefaultMissing))
+	code := encoder.Encode("None")
+	if len(code) != 1 || code[0] != 1 {
+		t.Errorf("missing code was %v and not [1]", code)
+	}
+}
+
+func TestImputeMean(t *testing.T) {
+	values, mean, err := ImputeMean([]string{"1", "NA", "3"}, DefaultMissing)
+	if err != nil {
+		t.Fatalf("impute error: %+v", err)
+	}
+
+	if mean != 2 || values[1] != 2 {
+		t.Errorf("imputed values were %v with mean %f", values, mean)
+	}
+
+	indicator := MissingIndicator([]string{"1", "NA", "3"}, DefaultMissing)
+	if indicator[0] != 0 || indicator[1] != 1 {
+		t.Errorf("indicator was %v and not [0 1 0]", indicator)
+	}
+}
+
+func TestMissingCodeIsDedicated(t *testing.T) {
+	onlyNA := func(s string) bool { return s == "NA" }
+
+	encoder := NewOrdinal(true, WithMissing(onlyNA))
+	empty := encoder.Encode("")
+	if empty == MissingCode || encoder.Encode("NA") != MissingCode {
+		t.Errorf("the empty string shares the missing code %d", empty)
+	}
+
+	data, err := encoder.MarshalJSON()
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	loaded := NewOrdinal(false, WithMissing(onlyNA))
+	if err := loaded.UnmarshalJSON(data); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	if loaded.Encode("") != empty || loaded.Encode("NA") != MissingCode || loaded.Length() != 2 {
+		t.Errorf("codes did not round trip: %v", loaded.List())
+	}
+
+	onehot := NewOneHot(WithOneHotMissing(onlyNA))
+	if code := onehot.Encode("NA"); len(code) != 1 || code[0] != 1 {
+		t.Errorf("missing code was %v and not [1]", code)
+	}
+	onehot.Encode("")
+	if onehot.Dimension() != 2 {
+		t.Errorf("the empty string shares the missing dimension of %d", onehot.Dimension())
+	}
+}
+
+func TestMissingFrequencyAndTarget(t *testing.T) {
+	values := []string{"", "NA", "null", "red", ""}
+	frequency := NewFrequency(values, WithFrequencyMissing(func(s string) bool { return s == "NA" || s == "null" }))
+	if n, _ := frequency.Get("NA"); n != 2 {
+		t.Errorf("missing count was %d and not 2", n)
+	}
+	if n, _ := frequency.Get(""); n != 2 {
+		t.Errorf("empty string count was %d and not 2", n)
+	}
+
+	target := []float64{1, 2, 4, 8, 3}
+	encoder, err := NewJamesSteinRegression(values, target, WithTargetMissing(DefaultMissing))
+	if err != nil {
+		t.Fatalf("james-stein error: %+v", err)
+	}
+	if v, _ := encoder.Get("None"); v != 2.5 {
+		t.Errorf("missing code was %f and not the mean of every missing value", v)
+	}
+	if v, _ := encoder.Get("red"); v != 8 {
+		t.Errorf("code was %f and not 8", v)
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// MulticlassTarget is a one way encoder.
+// You cannot decode MulticlassTarget values
+// as some values may be encoded with the same
+// numerical codes.
+// MulticlassTarget is a target-based encoder for
+// multiclass targets that encodes every category with
+// a vector of its smoothed class probabilities,
+// one dimension per class in sorted class order.
+type MulticlassTarget struct {
+	classes []string
+	encoder map[string][]float64
+	prior   []float64
+}
+
+// NewMulticlassTarget will create a MulticlassTarget encoder.
+// Every class probability of a category is shrunk towards the
+// class probability over all observations, as if `smoothing`
+// observations distributed like the whole target had been
+// added to the category (m-estimate smoothing).
+func NewMulticlassTarget(values []string, target []string, smoothing float64) (*MulticlassTarget, error) {
+	if len(target) != len(values) {
+		return &MulticlassTarget{}, ErrTargetLength
+	}
+
+	return newMulticlassTarget(values, target, nil, smoothing), nil
+}
+
+// NewMulticlassTargetWeighted will create a MulticlassTarget
+// encoder where every observation counts with its weight.
+// Categories without weight are not encoded. An
+// `ErrWeightLength` error is returned if the weights are not
+// the same length as the values, and an `ErrWeight` error if
+// a weight is negative or not finite.
+func NewMulticlassTargetWeighted(values []string, target []string, weights []float64, smoothing float64) (*MulticlassTarget, error) {
+	if len(target) != len(values) {
+		return &MulticlassTarget{}, ErrTargetLength
+	}
+	err := checkWeights(values, weights)
+	if err != nil {
+		return &MulticlassTarget{}, err
+	}
+
+	return newMulticlassTarget(values, target, weights, smoothing), nil
+}
+
+func newMulticlassTarget(values []string, target []string, weights []float64, smoothing float64) *MulticlassTarget {
+	classes, groupClassCounts, classCounts := countClasses(values, target, weights)
+
+	var total float64
+	for _, count := range classCounts {
+		total += count
+	}
+
+	prior := make([]float64, len(classes), len(classes))
+	for i, count := range classCounts {
+		prior[i] = count / total
+	}
+
+	encoder := make(map[string][]float64, len(groupClassCounts))
+	for v, classCounts := range groupClassCounts {
+		var n float64
+		for _, count := range classCounts {
+			n += count
+		}
+		// categories without weight were not observed
+		if n == 0 {
+			continue
+		}
+
+		probabilities := make([]float64, len(classes), len(classes))
+		for i, count := range classCounts {
+			probabilities[i] = (count + smoothing*prior[i]) / (n + smoothing)
+		}
+		encoder[v] = probabilities
+	}
+
+	return &MulticlassTarget{
+		classes: classes,
+		encoder: encoder,
+		prior:   prior,
+	}
+}
+
+// countClasses will return the sorted classes of the target,
+// the weighted count of every class for every category and
+// the weighted count of every class over all observations.
+func countClasses(values []string, target []string, weights []float64) ([]string, map[string][]float64, []float64) {
+	totals := make(map[string]float64)
+	for i, class := range target {
+		totals[class] += weight(weights, i)
+	}
+
+	classes := make([]string, 0, len(totals))
+	for class := range totals {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+
+	index := make(map[string]int, len(classes))
+	classCounts := make([]float64, len(classes), len(classes))
+	for i, class := range classes {
+		index[class] = i
+		classCounts[i] = totals[class]
+	}
+
+	groupClassCounts := make(map[string][]float64)
+	for i, v := range values {
+		if _, ok := groupClassCounts[v]; !ok {
+			groupClassCounts[v] = make([]float64, len(classes), len(classes))
+		}
+		groupClassCounts[v][index[target[i]]] += weight(weights, i)
+	}
+
+	return classes, groupClassCounts, classCounts
+}
+
+// Get will retrieve the class probabilities for the given
+// categorical value. Unseen values receive the class
+// probabilities over all observations.
+func (e *MulticlassTarget) Get(s string) ([]float64, bool) {
+	probabilities, ok := e.encoder[s]
+	if !ok {
+		probabilities = e.prior
+	}
+
+	return append([]float64{}, probabilities...), ok
+}
+
+// Probability will return the probability of the class for
+// the given categorical value, using the class probabilities
+// over all observations for unseen values. Classes not in the
+// target have probability 0.
+func (e *MulticlassTarget) Probability(s, class string) float64 {
+	i := sort.SearchStrings(e.classes, class)
+	if i == len(e.classes) || e.classes[i] != class {
+		return 0
+	}
+
+	probabilities, ok := e.encoder[s]
+	if !ok {
+		probabilities = e.prior
+	}
+
+	return probabilities[i]
+}
+
+// Transform will return the class probabilities of the
+// string as features. Unseen strings receive the class
+// probabilities over all observations.
+func (e *MulticlassTarget) Transform(s string) []float64 {
+	probabilities, _ := e.Get(s)
+	return probabilities
+}
+
+// Classes will return the classes of the
+// target in the order of the dimensions.
+func (e *MulticlassTarget) Classes() []string {
+	return append([]string{}, e.classes...)
+}
+
+// FeatureNames will return a name for every dimension,
+// in order, of the form `column_class`.
+func (e *MulticlassTarget) FeatureNames(column string) []string {
+	names := make([]string, len(e.classes), len(e.classes))
+	for i, class := range e.classes {
+		names[i] = column + "_" + class
+	}
+
+	return names
+}
+
+// SizeBytes will return an estimate of the
+// memory held by the encoder in bytes.
+func (e *MulticlassTarget) SizeBytes() int {
+	size := (len(e.classes) + 1) * float64Bytes
+	for k := range e.encoder {
+		size += mapEntryBytes + len(k) + len(e.classes)*float64Bytes
+	}
+
+	return size
+}
+
+type multiclassTargetJSON struct {
+	Classes []string             `json:"classes"`
+	Prior   []float64            `json:"prior"`
+	Mapping map[string][]float64 `json:"mapping"`
+}
+
+// MarshalJSON will encode the classes, the class probabilities
+// over all observations and the class probabilities of every
+// category.
+func (e *MulticlassTarget) MarshalJSON() ([]byte, error) {
+	return json.Marshal(multiclassTargetJSON{
+		Classes: e.classes,
+		Prior:   e.prior,
+		Mapping: e.encoder,
+	})
+}
+
+// UnmarshalJSON will return an `ErrLength` error if a
+// vector of probabilities does not have one dimension
+// per class, and an `ErrFormat` error if the classes
+// are not sorted.
+func (e *MulticlassTarget) UnmarshalJSON(data []byte) error {
+	var m multiclassTargetJSON
+	err := json.Unmarshal(data, &m)
+	if err != nil {
+		return err
+	}
+	if !sort.StringsAreSorted(m.Classes) {
+		return ErrFormat
+	}
+	if len(m.Prior) != len(m.Classes) {
+		return ErrLength
+	}
+	for _, probabilities := range m.Mapping {
+		if len(probabilities) != len(m.Classes) {
+			return ErrLength
+		}
+	}
+	if m.Mapping == nil {
+		m.Mapping = make(map[string][]float64)
+	}
+
+	e.classes, e.prior, e.encoder = m.Classes, m.Prior, m.Mapping
+	return nil
+}
@@ -0,0 +1,74 @@
+package encoder
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestMulticlassTarget(t *testing.T) {
+	values := []string{"a", "a", "a", "b", "b", "c"}
+	target := []string{"x", "x", "y", "z", "z", "x"}
+
+	encoder, err := NewMulticlassTarget(values, target, 0)
+	if err != nil {
+		t.Fatalf("encoder error: %+v", err)
+	}
+
+	a, ok := encoder.Get("a")
+	if !ok || len(a) != 3 || math.Abs(a[0]-2.0/3) > 1e-9 || math.Abs(a[1]-1.0/3) > 1e-9 || a[2] != 0 {
+		t.Errorf("unexpected class probabilities %v", a)
+	}
+
+	unseen, ok := encoder.Get("d")
+	if ok || math.Abs(unseen[0]-0.5) > 1e-9 {
+		t.Errorf("unseen value should receive the prior, got %v", unseen)
+	}
+
+	names := encoder.FeatureNames("color")
+	if len(names) != 3 || names[2] != "color_z" {
+		t.Errorf("unexpected feature names %v", names)
+	}
+
+	smoothed, _ := NewMulticlassTarget(values, target, 6)
+	c := smoothed.Transform("c")
+	if math.Abs(c[0]-(1+6*0.5)/7) > 1e-9 {
+		t.Errorf("unexpected smoothed probabilities %v", c)
+	}
+
+	if _, err := NewMulticlassTarget(values, target[1:], 0); err != ErrTargetLength {
+		t.Error("expected target length error")
+	}
+}
+
+func TestMulticlassTargetProbability(t *testing.T) {
+	values := []string{"a", "a", "a", "b", "b", "c"}
+	target := []string{"x", "x", "y", "z", "z", "x"}
+	encoder, _ := NewMulticlassTarget(values, target, 0)
+
+	if p := encoder.Probability("b", "z"); p != 1 {
+		t.Errorf("probability was %f and not 1", p)
+	}
+	if p := encoder.Probability("d", "x"); p != 0.5 {
+		t.Errorf("unseen probability was %f and not the prior 0.5", p)
+	}
+	if p := encoder.Probability("a", "w"); p != 0 {
+		t.Errorf("unknown class probability was %f and not 0", p)
+	}
+
+	b, err := json.Marshal(encoder)
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	var loaded MulticlassTarget
+	if err := json.Unmarshal(b, &loaded); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	if loaded.Probability("a", "y") != encoder.Probability("a", "y") || loaded.Probability("d", "z") != encoder.Probability("d", "z") {
+		t.Error("probabilities did not round trip")
+	}
+
+	if err := json.Unmarshal([]byte(`{"classes":["x"],"prior":[1],"mapping":{"a":[0.5,0.5]}}`), &loaded); err != ErrLength {
+		t.Error("expected length error")
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"strings"
+)
+
+// Normalizer rewrites a raw value before it is encoded or
+// looked up, so that near-duplicate spellings of a category
+// share a code. Normalizers must be idempotent, since a value
+// may be normalized more than once.
+//
+// Unicode normalization (NFC, NFKC) is out of scope: it needs
+// the Unicode tables of golang.org/x/text, which this module
+// does not depend on. The forms of its unicode/norm package
+// can be registered like any other normalizer, e.g.
+// `WithNormalizer("nfc", norm.NFC.String)`.
+type Normalizer func(s string) string
+
+// Lowercase will fold every letter to lower case.
+func Lowercase(s string) string {
+	return strings.ToLower(s)
+}
+
+// TrimSpace will remove leading and trailing white space.
+func TrimSpace(s string) string {
+	return strings.TrimSpace(s)
+}
+
+// WithNormalizer will register the normalizer as a preprocessor
+// under the given name, as RegisterPreprocessor does, and apply
+// it after any earlier preprocessors to every value before it is
+// encoded, looked up or checked against the missing policy. The
+// name is serialized with the encoder, so the same normalizer
+// must be registered under it in every program that loads it.
+func WithNormalizer(name string, f Normalizer) OrdinalOption {
+	RegisterPreprocessor(name, f)
+	return func(e *Ordinal) {
+		e.preprocessNames = append(e.preprocessNames, name)
+		e.preprocess = append(e.preprocess, f)
+	}
+}
+
+// WithOneHotNormalizer will register the normalizer as a
+// preprocessor and apply it in the same way as WithNormalizer.
+func WithOneHotNormalizer(name string, f Normalizer) OneHotOption {
+	RegisterPreprocessor(name, f)
+	return func(e *OneHot) {
+		e.preprocessNames = append(e.preprocessNames, name)
+		e.preprocess = append(e.preprocess, f)
+	}
+}
+
+// normalize will apply every normalizer to the value.
+func normalize(normalizers []Normalizer, s string) string {
+	for _, n := range normalizers {
+		s = n(s)
+	}
+
+	return s
+}
@@ -0,0 +1,53 @@
+package encoder
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOrdinalNormalizer(t *testing.T) {
+	encoder := NewOrdinal(false, WithNormalizer("trim_space", TrimSpace), WithNormalizer("lowercase", Lowercase), WithMissing(DefaultMissing))
+
+	code := encoder.Encode(" Red")
+	if encoder.Encode("RED ") != code || encoder.Decode(code) != "red" {
+		t.Error("normalized spellings did not share a code")
+	}
+	if !encoder.Contains("rEd") || !encoder.Snapshot().Contains(" red ") {
+		t.Error("lookups were not normalized")
+	}
+	if encoder.Encode(" NULL ") != MissingCode {
+		t.Error("normalized value was not checked against the missing policy")
+	}
+	if encoder.Length() != 2 {
+		t.Errorf("expected 2 values, got %d", encoder.Length())
+	}
+}
+
+func TestOrdinalNormalizerSerialized(t *testing.T) {
+	encoder := NewOrdinal(false, WithNormalizer("lower_trim", func(s string) string {
+		return Lowercase(TrimSpace(s))
+	}))
+	code := encoder.Encode(" Red ")
+
+	data, err := json.Marshal(encoder)
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	loaded := NewOrdinal(false)
+	if err := json.Unmarshal(data, loaded); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	if !loaded.Contains("RED") || loaded.Encode("RED") != code {
+		t.Error("loaded encoder did not normalize values")
+	}
+}
+
+func TestOneHotNormalizer(t *testing.T) {
+	encoder := NewOneHot(WithOneHotNormalizer("lowercase", Lowercase))
+	encoder.Encode("Red")
+	encoder.Encode("RED")
+
+	if encoder.Dimension() != 2 || !encoder.Contains("red") {
+		t.Error("normalized spellings did not share a dimension")
+	}
+}
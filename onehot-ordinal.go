@@ -0,0 +1,68 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"github.com/humilityai/sam"
+)
+
+// OneHotFromOrdinal will create a OneHot encoder from the
+// vocabulary of an Ordinal encoder, so that the value with
+// code `i` is hot in dimension `i` of its codeword (`i-1` if
+// the encoder drops the first dimension). The code 0 must be
+// the empty string, as in encoders created with `NewOrdinal(true)`
+// or `WithMissing`, since it is the reference category of the
+// one-hot encoder; otherwise an `ErrUnsupported` error is returned.
+func OneHotFromOrdinal(o *Ordinal, opts ...OneHotOption) (*OneHot, error) {
+	values := o.List()
+	if len(values) == 0 {
+		return NewOneHot(opts...), nil
+	}
+	if values[0] != "" {
+		return NewOneHot(opts...), ErrUnsupported
+	}
+
+	e := NewOneHot(opts...)
+	for _, v := range values[1:] {
+		e.decoder = append(e.decoder, v)
+		// reserved and expired codes decode to the empty
+		// string, which keeps the reference dimension
+		if _, ok := e.encoder[v]; !ok {
+			e.encoder[v] = len(e.decoder)
+		}
+	}
+
+	return e, nil
+}
+
+// Ordinal will create an Ordinal encoder from the vocabulary
+// of the encoder, so that the value hot in dimension `i` of its
+// codeword (`i+1` if the encoder drops the first dimension) has
+// the code `i`. The empty string has the code 0.
+func (e *OneHot) Ordinal(opts ...OrdinalOption) *Ordinal {
+	o := NewOrdinal(false, opts...)
+
+	values := make(sam.SliceString, len(e.decoder), len(e.decoder))
+	copy(values, e.decoder)
+	encoder := make(map[uint64]uint64, len(e.decoder))
+	for v, position := range e.encoder {
+		encoder[hashString(v)] = uint64(position - 1)
+	}
+
+	o.encoder = encoder
+	o.decoder = newArena(values)
+	o.loaded()
+
+	return o
+}
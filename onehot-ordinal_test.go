@@ -0,0 +1,44 @@
+package encoder
+
+import (
+	"testing"
+)
+
+func TestOneHotFromOrdinal(t *testing.T) {
+	ordinal := NewOrdinal(true)
+	ordinal.EncodeSlice([]string{"red", "green", "blue"})
+
+	onehot, err := OneHotFromOrdinal(ordinal)
+	if err != nil {
+		t.Fatalf("conversion error: %+v", err)
+	}
+	if onehot.Dimension() != 4 {
+		t.Errorf("expected dimension 4, got %d", onehot.Dimension())
+	}
+	for _, v := range []string{"red", "green", "blue"} {
+		if col, ok := onehot.Index(v); !ok || uint64(col) != ordinal.Encode(v) {
+			t.Errorf("%q is hot in dimension %d but has code %d", v, col, ordinal.Encode(v))
+		}
+	}
+
+	dropped, _ := OneHotFromOrdinal(ordinal, WithDropFirst())
+	if col, _ := dropped.Index("green"); uint64(col) != ordinal.Encode("green")-1 {
+		t.Error("dropped encoder did not shift dimensions by one")
+	}
+
+	back := onehot.Ordinal()
+	for _, v := range []string{"", "red", "green", "blue"} {
+		if back.Encode(v) != ordinal.Encode(v) {
+			t.Errorf("%q did not keep its code", v)
+		}
+	}
+
+	if _, err := OneHotFromOrdinal(NewOrdinal(false)); err != nil {
+		t.Error("an empty encoder should convert")
+	}
+	unset := NewOrdinal(false)
+	unset.Encode("red")
+	if _, err := OneHotFromOrdinal(unset); err != ErrUnsupported {
+		t.Error("expected unsupported error")
+	}
+}
@@ -1,213 +1,662 @@
-// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
-//
-// Licensed under the Apache License, Version 2.0 (the "License");
-// you may not use this file except in compliance with the License.
-// You may obtain a copy of the License at
-//
-//     http://www.apache.org/licenses/LICENSE-2.0
-//
-// Unless required by applicable law or agreed to in writing, software
-// distributed under the License is distributed on an "AS IS" BASIS,
-// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-// See the License for the specific language governing permissions and
-// limitations under the License.
-
-package encoder
-
-import (
-	"bytes"
-	"encoding/csv"
-	"encoding/json"
-	"strconv"
-
-	"github.com/humilityai/sam"
-)
-
-// OneHot will encode string values into
-// a unique one-hot vector (binary vector with a single 1).
-// The empty string is ALWAYS the 0-vector.
-// It will also allow for string values to be decoded.
-type OneHot struct {
-	encoder sam.MapStringInt
-	decoder sam.SliceString
-}
-
-// NewOneHot will return a one-hot encoder
-// that will set the empty string as the first
-// dimension of every one-hot binary codeword.
-// "Binary" here means that every value in the
-// codeword (integer slice) will be either a 0
-// or a 1.
-func NewOneHot() *OneHot {
-	e := &OneHot{
-		encoder: make(sam.MapStringInt),
-		decoder: make(sam.SliceString, 0),
-	}
-
-	// set empty string as first dimension
-	e.Encode("")
-
-	return e
-}
-
-// Encode will return the integer slice that represents
-// the binary encoding of the given string argument.
-// If the string argument does not already have a code
-// it will generate a new codeword for the given string
-// argument and add it to the encoder.
-func (e *OneHot) Encode(s string) []uint8 {
-	_, ok := e.encoder[s]
-	if !ok {
-		e.decoder = append(e.decoder, s)
-		e.encoder[s] = len(e.decoder)
-
-		return e.code(s)
-	}
-
-	return e.code(s)
-}
-
-// Decode will return the string for the given binary
-// codeword (one-hot code).
-// If the codeword argument is longer than the encoders codewords
-// then an `ErrLength` error will be returned.
-func (e *OneHot) Decode(code []uint8) (string, error) {
-	if len(code) > len(e.decoder) {
-		return "", ErrLength
-	}
-
-	var dim int
-	for i, v := range code {
-		if v == 1 {
-			dim = i
-			break
-		}
-	}
-
-	return e.decoder[dim], nil
-}
-
-// Contains will check if a string has been assigned
-// a one-hot code or not.
-func (e *OneHot) Contains(s string) bool {
-	_, ok := e.encoder[s]
-	return ok
-}
-
-// ContainsCode will check if a codeword is a valid
-// codeword or not.
-func (e *OneHot) ContainsCode(code []uint8) bool {
-	if len(e.decoder) > len(code) {
-		return false
-	}
-
-	return containsOne(code)
-}
-
-// Dimension returns the current dimension of
-// each one-hot codeword. The dimension increases
-// with every new string that gets encoded.
-func (e *OneHot) Dimension() int {
-	return len(e.decoder)
-}
-
-// MarshalJSON ...
-func (e *OneHot) MarshalJSON() ([]byte, error) {
-	return json.Marshal(e.decoder)
-}
-
-// UnmarshalJSON ...
-func (e *OneHot) UnmarshalJSON(data []byte) error {
-	s := make(sam.SliceString, 0)
-	err := json.Unmarshal(data, &s)
-	if err != nil {
-		return err
-	}
-
-	encoder := make(sam.MapStringInt)
-	for i, v := range s {
-		encoder[v] = i + 1
-	}
-
-	e = &OneHot{
-		encoder: encoder,
-		decoder: s,
-	}
-
-	return nil
-}
-
-// MarshalCSV ...
-func (e *OneHot) MarshalCSV() ([]byte, error) {
-	var lines [][]string
-
-	// header
-	lines = append(lines, []string{"value", "code"})
-
-	for value, code := range e.encoder {
-		line := []string{value, strconv.Itoa(code)}
-		lines = append(lines, line)
-	}
-
-	var b bytes.Buffer
-	w := csv.NewWriter(&b)
-	err := w.WriteAll(lines)
-	if err != nil {
-		return []byte{}, err
-	}
-
-	return b.Bytes(), nil
-}
-
-// UnmarshalCSV ...
-func (e *OneHot) UnmarshalCSV(data []byte) error {
-	var b bytes.Buffer
-	_, err := b.Write(data)
-	if err != nil {
-		return err
-	}
-
-	r := csv.NewReader(&b)
-	lines, err := r.ReadAll()
-	if err != nil {
-		return err
-	}
-
-	s := lines[1:]
-	decoder := make(sam.SliceString, len(s), len(s))
-	for _, line := range s {
-		if len(line) == 2 {
-			code, err := strconv.Atoi(line[1])
-			if err == nil {
-				e.encoder[line[0]] = code
-				decoder[code-1] = line[0]
-			}
-		}
-	}
-	e.decoder = decoder
-
-	return nil
-}
-
-func (e *OneHot) code(s string) (code []uint8) {
-	code = make([]uint8, len(e.decoder), len(e.decoder))
-	dim := e.encoder[s]
-
-	code[dim] = 1
-	return
-}
-
-func containsOne(code []uint8) bool {
-	contains := false
-	for _, v := range code {
-		if v == 1 {
-			if contains {
-				return false
-			}
-
-			contains = true
-		}
-	}
-
-	return contains
-}
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/humilityai/sam"
+)
+
+// OneHot will encode string values into
+// a unique one-hot vector (binary vector with a single 1).
+// The empty string is ALWAYS the 0-vector.
+// It will also allow for string values to be decoded.
+type OneHot struct {
+	encoder         sam.MapStringInt
+	decoder         sam.SliceString
+	dropFirst       bool
+	frozen          bool
+	smoothing       float32
+	preprocessNames []string
+	preprocess      []Normalizer
+	missing         MissingPolicy
+	onNew           NewCategoryFunc
+	created         time.Time
+	updated         time.Time
+}
+
+// OneHotOption configures optional behaviour
+// of a OneHot encoder at construction time.
+type OneHotOption func(*OneHot)
+
+// WithDropFirst will omit the dimension of the
+// reference category (the empty string) from every
+// codeword, so that codewords have k-1 dimensions
+// and the reference category is the all-zeros vector.
+func WithDropFirst() OneHotOption {
+	return func(e *OneHot) {
+		e.dropFirst = true
+	}
+}
+
+// WithLabelSmoothing will make EncodeSoft return smoothed
+// probability vectors for training neural networks: the hot
+// dimension is `1 - epsilon` and every other dimension is
+// `epsilon / (k - 1)`, where `k` is the dimension of the
+// codewords.
+func WithLabelSmoothing(epsilon float32) OneHotOption {
+	return func(e *OneHot) {
+		e.smoothing = epsilon
+	}
+}
+
+// WithOneHotMissing will encode every value considered
+// missing by the policy in the first (reference) dimension,
+// which is reserved for missing values. The empty string is
+// only missing if the policy says so; otherwise it is an
+// observed value with a dimension of its own.
+func WithOneHotMissing(policy MissingPolicy) OneHotOption {
+	return func(e *OneHot) {
+		e.missing = policy
+	}
+}
+
+// NewOneHot will return a one-hot encoder
+// that will set the empty string as the first
+// dimension of every one-hot binary codeword.
+// "Binary" here means that every value in the
+// codeword (integer slice) will be either a 0
+// or a 1.
+func NewOneHot(opts ...OneHotOption) *OneHot {
+	e := &OneHot{
+		encoder: make(sam.MapStringInt),
+		decoder: make(sam.SliceString, 0),
+		created: time.Now(),
+	}
+	e.updated = e.created
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	// reserve the first dimension for missing values
+	if e.missing != nil {
+		e.load([]string{""})
+		return e
+	}
+
+	// set empty string as first dimension
+	e.Encode("")
+
+	return e
+}
+
+// Encode will return the integer slice that represents
+// the binary encoding of the given string argument.
+// If the string argument does not already have a code
+// it will generate a new codeword for the given string
+// argument and add it to the encoder, unless the encoder
+// is frozen, in which case the all-zeros codeword is
+// returned.
+func (e *OneHot) Encode(s string) []uint8 {
+	s = e.prepare(s)
+	_, ok := e.encoder[s]
+	if !ok {
+		if e.frozen {
+			return make([]uint8, e.Dimension(), e.Dimension())
+		}
+
+		e.decoder = append(e.decoder, s)
+		e.encoder[s] = len(e.decoder)
+		e.updated = time.Now()
+		if e.onNew != nil {
+			e.onNew(s, uint64(len(e.decoder)-1))
+		}
+
+		return e.code(s)
+	}
+
+	return e.code(s)
+}
+
+// EncodeChecked will return the codeword of the given string
+// like Encode, but returns an `ErrFrozen` error along with the
+// all-zeros codeword if the encoder is frozen and the string
+// has not been encoded, so that unseen values can be told
+// apart from the reference category.
+func (e *OneHot) EncodeChecked(s string) ([]uint8, error) {
+	if e.frozen && !e.Contains(s) {
+		return make([]uint8, e.Dimension(), e.Dimension()), ErrFrozen
+	}
+
+	return e.Encode(s), nil
+}
+
+// EncodeInto will write the codeword of the given string into
+// the caller-provided `dst`, adding the string to the encoder
+// first if needed. If `dst` is not `Dimension()` long after the
+// string has been added an `ErrLength` error is returned.
+func (e *OneHot) EncodeInto(dst []uint8, s string) error {
+	if !e.Contains(s) {
+		e.Encode(s)
+	}
+
+	if len(dst) != e.Dimension() {
+		return ErrLength
+	}
+
+	for i := range dst {
+		dst[i] = 0
+	}
+	if col, ok := e.Index(s); ok {
+		dst[col] = 1
+	}
+
+	return nil
+}
+
+// EncodeSliceInto will write the codewords of every value in
+// `src` into the caller-provided `dst` as a row-major matrix of
+// `len(src)` rows and `Dimension()` columns. Every value is added
+// to the encoder first, so the dimension is final before any row
+// is written. If `dst` is not `len(src) * Dimension()` long an
+// `ErrLength` error is returned.
+func (e *OneHot) EncodeSliceInto(dst []uint8, src []string) error {
+	for _, v := range src {
+		if !e.Contains(v) {
+			e.Encode(v)
+		}
+	}
+
+	dimension := e.Dimension()
+	if len(dst) != len(src)*dimension {
+		return ErrLength
+	}
+
+	for i := range dst {
+		dst[i] = 0
+	}
+	for i, v := range src {
+		if col, ok := e.Index(v); ok {
+			dst[i*dimension+col] = 1
+		}
+	}
+
+	return nil
+}
+
+// OnNewCategory will register a callback that is called
+// whenever Encode adds a new value, and so a new dimension,
+// to the encoder, replacing any previously registered callback.
+// The code passed to the callback is the new dimension,
+// counting the dimension of the empty string.
+// A nil callback removes the callback.
+func (e *OneHot) OnNewCategory(f NewCategoryFunc) {
+	e.onNew = f
+}
+
+// Decode will return the string for the given binary
+// codeword (one-hot code).
+// If the codeword argument is longer than the encoders codewords
+// then an `ErrLength` error will be returned.
+// When the encoder drops the first dimension the all-zeros
+// codeword decodes to the reference category; otherwise, or
+// if the codeword holds a value other than 0 or 1, an
+// `ErrInvalidCodeword` error will be returned. If more than
+// one dimension is hot the first is decoded.
+func (e *OneHot) Decode(code []uint8) (string, error) {
+	if len(code) > e.Dimension() {
+		return "", ErrLength
+	}
+
+	dim := -1
+	for i, v := range code {
+		if v > 1 {
+			return "", ErrInvalidCodeword
+		}
+		if v == 1 && dim < 0 {
+			dim = i + e.offset()
+		}
+	}
+
+	if dim < 0 {
+		if !e.dropFirst {
+			return "", ErrInvalidCodeword
+		}
+		dim = 0
+	}
+
+	return e.decoder[dim], nil
+}
+
+// DecodeStrict will decode the codeword like Decode, but
+// returns an `ErrLength` error unless the codeword is exactly
+// `Dimension()` long and an `ErrInvalidCodeword` error if more
+// than one dimension is hot.
+func (e *OneHot) DecodeStrict(code []uint8) (string, error) {
+	if len(code) != e.Dimension() {
+		return "", ErrLength
+	}
+
+	if containsNonZero(code) && !containsOne(code) {
+		return "", ErrInvalidCodeword
+	}
+
+	return e.Decode(code)
+}
+
+// Contains will check if a string has been assigned
+// a one-hot code or not.
+func (e *OneHot) Contains(s string) bool {
+	s = e.prepare(s)
+	_, ok := e.encoder[s]
+	return ok
+}
+
+// ContainsCode will check if a codeword is a valid
+// codeword or not.
+func (e *OneHot) ContainsCode(code []uint8) bool {
+	if e.Dimension() > len(code) {
+		return false
+	}
+
+	if e.dropFirst && !containsNonZero(code) {
+		return true
+	}
+
+	return containsOne(code)
+}
+
+// Index will return the column of the hot dimension
+// of the codeword for the given string, without building
+// the codeword. The string is normalized and preprocessed
+// like it is by Encode. It returns false if the string has
+// not been encoded or if it is the dropped reference category.
+func (e *OneHot) Index(s string) (int, bool) {
+	dim, ok := e.encoder[e.prepare(s)]
+	if !ok {
+		return 0, false
+	}
+
+	dim = dim - 1 - e.offset()
+	if dim < 0 {
+		return 0, false
+	}
+
+	return dim, true
+}
+
+// Indices will return the coordinates of the hot
+// dimensions of the codewords for every value in
+// the slice of strings provided as an argument,
+// as parallel row and column slices (COO format).
+// Values without a hot dimension, either because
+// they have not been encoded or because they are the
+// dropped reference category, contribute no entry.
+// The matrix described has len(s) rows and
+// `Dimension()` columns.
+func (e *OneHot) Indices(s sam.SliceString) (rows, cols []int) {
+	rows = make([]int, 0, len(s))
+	cols = make([]int, 0, len(s))
+	for i, v := range s {
+		col, ok := e.Index(v)
+		if ok {
+			rows = append(rows, i)
+			cols = append(cols, col)
+		}
+	}
+
+	return
+}
+
+// Dimension returns the current dimension of
+// each one-hot codeword. The dimension increases
+// with every new string that gets encoded.
+func (e *OneHot) Dimension() int {
+	return len(e.decoder) - e.offset()
+}
+
+// FeatureNames will return a name for every dimension
+// of the codewords, in order, of the form `column=value`.
+func (e *OneHot) FeatureNames(column string) []string {
+	values := e.decoder[e.offset():]
+
+	names := make([]string, len(values), len(values))
+	for i, v := range values {
+		names[i] = column + "=" + v
+	}
+
+	return names
+}
+
+// Freeze will stop the encoder from adding new values, so
+// that its dimension stays fixed for inference. Unseen values
+// are encoded as the all-zeros codeword (as with scikit-learn's
+// `handle_unknown="ignore"`). When the encoder drops the first
+// dimension this is also the codeword of the reference category.
+func (e *OneHot) Freeze() {
+	e.frozen = true
+}
+
+// Frozen will return whether or not the
+// encoder has stopped adding new values.
+func (e *OneHot) Frozen() bool {
+	return e.frozen
+}
+
+// EncodeSoft will return the codeword of the given string as a
+// float32 vector, smoothed if the encoder was created with
+// WithLabelSmoothing, adding the string to the encoder like
+// Encode. Codewords without a hot dimension, such as that of a
+// dropped reference category, are returned as all zeros.
+func (e *OneHot) EncodeSoft(s string) []float32 {
+	code := e.Encode(s)
+
+	soft := make([]float32, len(code), len(code))
+	if !containsOne(code) {
+		return soft
+	}
+
+	off := float32(0)
+	on := float32(1)
+	if len(code) > 1 {
+		off = e.smoothing / float32(len(code)-1)
+		on = 1 - e.smoothing
+	}
+	for i, v := range code {
+		soft[i] = off
+		if v == 1 {
+			soft[i] = on
+		}
+	}
+
+	return soft
+}
+
+// DropFirst will return whether or not the encoder
+// omits the dimension of the reference category.
+func (e *OneHot) DropFirst() bool {
+	return e.dropFirst
+}
+
+// oneHotJSON is the JSON form of an encoder
+// with options that change its codewords.
+type oneHotJSON struct {
+	Values        []string `json:"values"`
+	DropFirst     bool     `json:"dropFirst,omitempty"`
+	Frozen        bool     `json:"frozen,omitempty"`
+	Smoothing     float32  `json:"labelSmoothing,omitempty"`
+	Preprocessors []string `json:"preprocessors,omitempty"`
+}
+
+// MarshalJSON will encode the values as an array indexed
+// by position, the empty string first, or, if the encoder
+// drops the first dimension, is frozen, smooths labels or has
+// preprocessors, as an object holding the array of values and
+// the options.
+func (e *OneHot) MarshalJSON() ([]byte, error) {
+	if !e.dropFirst && !e.frozen && e.smoothing == 0 && len(e.preprocessNames) == 0 {
+		return json.Marshal(e.decoder)
+	}
+
+	return json.Marshal(oneHotJSON{
+		Values:        e.decoder,
+		DropFirst:     e.dropFirst,
+		Frozen:        e.frozen,
+		Smoothing:     e.smoothing,
+		Preprocessors: e.preprocessNames,
+	})
+}
+
+// UnmarshalJSON will return an `ErrPreprocessor` error if
+// the encoder uses a preprocessor that is not registered.
+func (e *OneHot) UnmarshalJSON(data []byte) error {
+	var o oneHotJSON
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		err := json.Unmarshal(trimmed, &o)
+		if err != nil {
+			return err
+		}
+	} else {
+		err := json.Unmarshal(data, &o.Values)
+		if err != nil {
+			return err
+		}
+	}
+
+	preprocess, err := lookupPreprocessors(o.Preprocessors)
+	if err != nil {
+		return err
+	}
+
+	e.load(o.Values)
+	e.dropFirst, e.frozen, e.smoothing = o.DropFirst, o.Frozen, o.Smoothing
+	e.preprocessNames, e.preprocess = o.Preprocessors, preprocess
+
+	return nil
+}
+
+// MarshalCSV ...
+func (e *OneHot) MarshalCSV() ([]byte, error) {
+	return e.MarshalCSVDialect(CSVDialect{})
+}
+
+// MarshalCSVDialect will write the rows of MarshalCSV
+// in the given dialect.
+func (e *OneHot) MarshalCSVDialect(d CSVDialect) ([]byte, error) {
+	var lines [][]string
+
+	// rows are written in position order so that
+	// the output is the same for the same encoder
+	for i, value := range e.decoder {
+		if e.encoder[value] != i+1 {
+			continue
+		}
+		lines = append(lines, []string{value, strconv.Itoa(i + 1)})
+	}
+
+	return d.write(lines)
+}
+
+// UnmarshalCSV will replace the values of the encoder with
+// the rows of the CSV. Codes are the 1-based positions of
+// the values; an `ErrBounds` error is returned for a code
+// outside the rows of the CSV.
+func (e *OneHot) UnmarshalCSV(data []byte) error {
+	return e.UnmarshalCSVDialect(data, CSVDialect{})
+}
+
+// UnmarshalCSVDialect will read rows in the given
+// dialect in the same way as UnmarshalCSV.
+func (e *OneHot) UnmarshalCSVDialect(data []byte, d CSVDialect) error {
+	lines, err := d.read(data)
+	if err != nil {
+		return err
+	}
+
+	decoder := make(sam.SliceString, len(lines), len(lines))
+	for _, line := range lines {
+		code, err := strconv.Atoi(line[1])
+		if err != nil {
+			return err
+		}
+		if code < 1 || code > len(decoder) {
+			return ErrBounds
+		}
+		decoder[code-1] = line[0]
+	}
+	e.load(decoder)
+
+	return nil
+}
+
+// GobEncode ...
+func (e *OneHot) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+
+	enc := gob.NewEncoder(&buf)
+
+	eCopy := struct {
+		Decoder       []string
+		DropFirst     bool
+		Frozen        bool
+		Smoothing     float32
+		Preprocessors []string
+		Integrity     *snapshotIntegrity
+	}{
+		Decoder:       e.decoder,
+		DropFirst:     e.dropFirst,
+		Frozen:        e.frozen,
+		Smoothing:     e.smoothing,
+		Preprocessors: e.preprocessNames,
+		Integrity:     newSnapshotIntegrity(e.decoder),
+	}
+
+	err := enc.Encode(eCopy)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// GobDecode will return an `ErrCorruptSnapshot` error if the
+// entry count or checksum recorded in the snapshot do not
+// match its contents, and an `ErrPreprocessor` error if the
+// encoder uses a preprocessor that is not registered.
+func (e *OneHot) GobDecode(data []byte) error {
+	var buf bytes.Buffer
+	_, err := buf.Write(data)
+	if err != nil {
+		return err
+	}
+
+	var eCopy struct {
+		Decoder       []string
+		DropFirst     bool
+		Frozen        bool
+		Smoothing     float32
+		Preprocessors []string
+		Integrity     *snapshotIntegrity
+	}
+
+	dec := gob.NewDecoder(&buf)
+	err = dec.Decode(&eCopy)
+	if err != nil {
+		return err
+	}
+
+	if eCopy.Integrity != nil && !eCopy.Integrity.verify(eCopy.Decoder) {
+		return ErrCorruptSnapshot
+	}
+
+	preprocess, err := lookupPreprocessors(eCopy.Preprocessors)
+	if err != nil {
+		return err
+	}
+
+	e.load(eCopy.Decoder)
+	e.dropFirst, e.frozen, e.smoothing = eCopy.DropFirst, eCopy.Frozen, eCopy.Smoothing
+	e.preprocessNames, e.preprocess = eCopy.Preprocessors, preprocess
+
+	return nil
+}
+
+// load will replace the values of the encoder, keeping
+// the first position of any value that is repeated.
+func (e *OneHot) load(values []string) {
+	if len(values) == 0 {
+		values = []string{""}
+	}
+
+	encoder := make(sam.MapStringInt, len(values))
+	for i, v := range values {
+		if i == 0 && v == "" && e.missing != nil {
+			// the dimension of missing values
+			v = missingCategory
+		}
+		if _, ok := encoder[v]; !ok {
+			encoder[v] = i + 1
+		}
+	}
+
+	e.encoder = encoder
+	e.decoder = values
+	e.updated = time.Now()
+	if e.created.IsZero() {
+		e.created = e.updated
+	}
+}
+
+func (e *OneHot) code(s string) (code []uint8) {
+	code = make([]uint8, e.Dimension(), e.Dimension())
+	dim := e.encoder[s] - 1 - e.offset()
+
+	if dim >= 0 {
+		code[dim] = 1
+	}
+	return
+}
+
+// prepare will return the value that is encoded
+// in place of the given string.
+func (e *OneHot) prepare(s string) string {
+	s = normalize(e.preprocess, s)
+	if e.missing != nil && e.missing(s) {
+		return missingCategory
+	}
+
+	return s
+}
+
+// offset is the number of leading dimensions
+// omitted from every codeword.
+func (e *OneHot) offset() int {
+	if e.dropFirst {
+		return 1
+	}
+
+	return 0
+}
+
+func containsNonZero(code []uint8) bool {
+	for _, v := range code {
+		if v != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsOne(code []uint8) bool {
+	contains := false
+	for _, v := range code {
+		if v == 1 {
+			if contains {
+				return false
+			}
+
+			contains = true
+		}
+	}
+
+	return contains
+}
@@ -0,0 +1,274 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestOneHotEncode(t *testing.T) {
+	encoder := NewOneHot()
+	code := encoder.Encode("hello world")
+	if len(code) != 2 || code[1] != 1 {
+		t.Errorf("code was %v and not [0 1]", code)
+	}
+
+	value, err := encoder.Decode(code)
+	if err != nil {
+		t.Errorf("decode error: %+v", err)
+	}
+	if value != "hello world" {
+		t.Error("decoded value did not equal original value")
+	}
+}
+
+func TestOneHotDropFirst(t *testing.T) {
+	encoder := NewOneHot(WithDropFirst())
+	code := encoder.Encode("hello world")
+	if len(code) != 1 || code[0] != 1 {
+		t.Errorf("code was %v and not [1]", code)
+	}
+
+	reference := encoder.Encode("")
+	if len(reference) != 1 || reference[0] != 0 {
+		t.Errorf("reference code was %v and not [0]", reference)
+	}
+
+	for _, value := range []string{"hello world", ""} {
+		decoded, err := encoder.Decode(encoder.Encode(value))
+		if err != nil {
+			t.Errorf("decode error: %+v", err)
+		}
+		if decoded != value {
+			t.Errorf("decoded value %q did not equal original value %q", decoded, value)
+		}
+	}
+
+	if !encoder.ContainsCode([]uint8{0}) {
+		t.Error("all-zeros code should be valid when dropping first")
+	}
+}
+
+func TestOneHotIndices(t *testing.T) {
+	encoder := NewOneHot()
+	encoder.Encode("red")
+	encoder.Encode("blue")
+
+	col, ok := encoder.Index("blue")
+	if !ok || col != 2 {
+		t.Errorf("index was %d and not 2", col)
+	}
+
+	rows, cols := encoder.Indices([]string{"blue", "green", "red"})
+	if len(rows) != 2 || rows[1] != 2 || cols[1] != 1 {
+		t.Errorf("indices were %v %v and not [0 2] [2 1]", rows, cols)
+	}
+}
+
+func TestOneHotIndicesNormalized(t *testing.T) {
+	encoder := NewOneHot(WithOneHotNormalizer("lowercase", Lowercase))
+	encoder.Encode("Red")
+
+	col, ok := encoder.Index("RED")
+	if !ok || col != 1 {
+		t.Errorf("index was %d and not 1", col)
+	}
+
+	dst := make([]uint8, 2)
+	err := encoder.EncodeInto(dst, "rEd")
+	if err != nil || dst[1] != 1 {
+		t.Errorf("codeword was %v and not [0 1]", dst)
+	}
+}
+
+func TestOneHotFeatureNames(t *testing.T) {
+	encoder := NewOneHot(WithDropFirst())
+	encoder.Encode("red")
+	encoder.Encode("blue")
+
+	names := encoder.FeatureNames("color")
+	if len(names) != 2 || names[0] != "color=red" || names[1] != "color=blue" {
+		t.Errorf("feature names were %v", names)
+	}
+}
+
+func TestOneHotSliceInto(t *testing.T) {
+	encoder := NewOneHot()
+	values := []string{"red", "green"}
+
+	dst := make([]uint8, 6)
+	err := encoder.EncodeSliceInto(dst, values)
+	if err != nil {
+		t.Fatalf("encode error: %+v", err)
+	}
+
+	expected := []uint8{0, 1, 0, 0, 0, 1}
+	for i, v := range expected {
+		if dst[i] != v {
+			t.Fatalf("codes were %v and not %v", dst, expected)
+		}
+	}
+
+	code := make([]uint8, 3)
+	err = encoder.EncodeInto(code, "green")
+	if err != nil || code[2] != 1 {
+		t.Errorf("code was %v and not [0 0 1]", code)
+	}
+}
+
+func TestOneHotDecodeInvalid(t *testing.T) {
+	encoder := NewOneHot()
+	encoder.Encode("red")
+	encoder.Encode("blue")
+
+	if _, err := encoder.Decode([]uint8{0, 0, 0}); err != ErrInvalidCodeword {
+		t.Error("all-zeros code should be invalid")
+	}
+	if _, err := encoder.Decode([]uint8{0, 2, 0}); err != ErrInvalidCodeword {
+		t.Error("non-binary code should be invalid")
+	}
+	if value, err := encoder.Decode([]uint8{0, 1, 1}); err != nil || value != "red" {
+		t.Error("decode should return the first hot dimension")
+	}
+
+	if _, err := encoder.DecodeStrict([]uint8{0, 1, 1}); err != ErrInvalidCodeword {
+		t.Error("strict decode should reject multiple hot dimensions")
+	}
+	if _, err := encoder.DecodeStrict([]uint8{0, 1}); err != ErrLength {
+		t.Error("strict decode should reject short codes")
+	}
+	if value, err := encoder.DecodeStrict([]uint8{0, 0, 1}); err != nil || value != "blue" {
+		t.Error("strict decode did not decode a valid code")
+	}
+}
+
+func TestOneHotFreeze(t *testing.T) {
+	encoder := NewOneHot()
+	encoder.Encode("red")
+	encoder.Freeze()
+
+	code := encoder.Encode("blue")
+	if len(code) != 2 || containsNonZero(code) {
+		t.Errorf("unseen value was encoded as %v and not [0 0]", code)
+	}
+	if encoder.Dimension() != 2 || encoder.Contains("blue") {
+		t.Error("frozen encoder added an unseen value")
+	}
+
+	dst := make([]uint8, 4)
+	if err := encoder.EncodeSliceInto(dst, []string{"red", "green"}); err != nil {
+		t.Fatalf("encode error: %+v", err)
+	}
+	if dst[1] != 1 || containsNonZero(dst[2:]) {
+		t.Errorf("unexpected frozen codewords %v", dst)
+	}
+}
+
+func TestOneHotSerialization(t *testing.T) {
+	encoder := NewOneHot(WithDropFirst())
+	encoder.Encode("red")
+	encoder.Encode("blue")
+	encoder.Freeze()
+
+	check := func(name string, loaded *OneHot, options bool) {
+		if loaded.Dimension() != encoder.Dimension() {
+			t.Errorf("%s: dimension %d, expected %d", name, loaded.Dimension(), encoder.Dimension())
+		}
+		for _, v := range []string{"", "red", "blue"} {
+			col, _ := loaded.Index(v)
+			expected, _ := encoder.Index(v)
+			if col != expected {
+				t.Errorf("%s: %q did not keep its dimension", name, v)
+			}
+		}
+		if options && (!loaded.DropFirst() || !loaded.Frozen()) {
+			t.Errorf("%s: options were not restored", name)
+		}
+	}
+
+	b, err := json.Marshal(encoder)
+	if err != nil {
+		t.Fatalf("json marshal error: %+v", err)
+	}
+	var fromJSON OneHot
+	if err := json.Unmarshal(b, &fromJSON); err != nil {
+		t.Fatalf("json unmarshal error: %+v", err)
+	}
+	check("json", &fromJSON, true)
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(encoder); err != nil {
+		t.Fatalf("gob encode error: %+v", err)
+	}
+	fromGob := NewOneHot()
+	if err := gob.NewDecoder(&buf).Decode(fromGob); err != nil {
+		t.Fatalf("gob decode error: %+v", err)
+	}
+	check("gob", fromGob, true)
+
+	b, err = encoder.MarshalCSV()
+	if err != nil {
+		t.Fatalf("csv marshal error: %+v", err)
+	}
+	fromCSV := NewOneHot(WithDropFirst())
+	if err := fromCSV.UnmarshalCSV(b); err != nil {
+		t.Fatalf("csv unmarshal error: %+v", err)
+	}
+	check("csv", fromCSV, false)
+
+	if err := fromCSV.UnmarshalCSV([]byte("value,code\nred,5\n")); err != ErrBounds {
+		t.Error("expected bounds error")
+	}
+}
+
+func TestOneHotLabelSmoothing(t *testing.T) {
+	encoder := NewOneHot(WithLabelSmoothing(0.1))
+	encoder.Encode("cat")
+	encoder.Encode("dog")
+
+	soft := encoder.EncodeSoft("cat")
+	expected := []float32{0.05, 0.9, 0.05}
+	for i := range expected {
+		if math.Abs(float64(soft[i]-expected[i])) > 1e-6 {
+			t.Errorf("soft codeword was %v and not %v", soft, expected)
+			break
+		}
+	}
+
+	hard := NewOneHot()
+	hard.Encode("cat")
+	if soft := hard.EncodeSoft("cat"); soft[0] != 0 || soft[1] != 1 {
+		t.Errorf("unsmoothed soft codeword was %v", soft)
+	}
+
+	b, err := json.Marshal(encoder)
+	if err != nil {
+		t.Fatalf("json marshal error: %+v", err)
+	}
+	var fromJSON OneHot
+	if err := json.Unmarshal(b, &fromJSON); err != nil {
+		t.Fatalf("json unmarshal error: %+v", err)
+	}
+	if soft := fromJSON.EncodeSoft("dog"); math.Abs(float64(soft[2]-0.9)) > 1e-6 {
+		t.Error("label smoothing did not round trip")
+	}
+}
+
+func TestOneHotEncodeChecked(t *testing.T) {
+	encoder := NewOneHot()
+	encoder.Encode("red")
+	if _, err := encoder.EncodeChecked("blue"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	encoder.Freeze()
+	code, err := encoder.EncodeChecked("green")
+	if err != ErrFrozen || len(code) != 3 || containsNonZero(code) {
+		t.Errorf("expected frozen error and zeros, got %v: %v", code, err)
+	}
+	if code, err := encoder.EncodeChecked("red"); err != nil || code[1] != 1 {
+		t.Errorf("unexpected codeword %v: %v", code, err)
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+)
+
+// ONNX-ML operator domain.
+const onnxMLDomain = "ai.onnx.ml"
+
+// ONNX AttributeProto types.
+const (
+	onnxAttributeInt     = 2
+	onnxAttributeInts    = 7
+	onnxAttributeFloat   = 1
+	onnxAttributeFloats  = 6
+	onnxAttributeStrings = 8
+)
+
+// ONNXNode will return the encoder as a serialized ONNX
+// `NodeProto` for the ai.onnx.ml `LabelEncoder` operator,
+// mapping every value and alias to its code. Values that
+// have not been encoded map to -1, as do codes that hold
+// no value, such as reserved codes.
+func (e *Ordinal) ONNXNode(name, input, output string) []byte {
+	e.RLock()
+	defer e.RUnlock()
+
+	keys := make([]string, 0, e.decoder.len()+len(e.aliases))
+	codes := make([]int64, 0, e.decoder.len()+len(e.aliases))
+	for code := 0; code < e.decoder.len(); code++ {
+		value := e.decoder.get(code)
+		if c, ok := e.encoder[hashString(value)]; ok && c == uint64(code) {
+			keys = append(keys, value)
+			codes = append(codes, int64(code))
+		}
+	}
+	for _, alias := range e.sortedAliases() {
+		keys = append(keys, alias)
+		codes = append(codes, int64(e.encoder[hashString(alias)]))
+	}
+
+	return onnxNode(name, "LabelEncoder", input, output,
+		onnxStringsAttribute("keys_strings", keys),
+		onnxIntsAttribute("values_int64s", codes),
+		onnxIntAttribute("default_int64", -1),
+	)
+}
+
+// ONNXNode will return the encoder as a serialized ONNX
+// `NodeProto` for the ai.onnx.ml `OneHotEncoder` operator.
+// Values that have not been encoded map to the all-zeros
+// codeword, as does the reference category when the encoder
+// drops the first dimension.
+func (e *OneHot) ONNXNode(name, input, output string) []byte {
+	return onnxNode(name, "OneHotEncoder", input, output,
+		onnxStringsAttribute("cats_strings", e.decoder[e.offset():]),
+		onnxIntAttribute("zeros", 1),
+	)
+}
+
+// ONNXNode will return the encoder as a serialized ONNX
+// `NodeProto` for the ai.onnx.ml `LabelEncoder` operator.
+// Values without a code map to `NaN`.
+func (e *JamesSteinRegression) ONNXNode(name, input, output string) []byte {
+	return onnxFloatLabelEncoder(name, input, output, e.encoder)
+}
+
+// ONNXNode will return the encoder as a serialized ONNX
+// `NodeProto` for the ai.onnx.ml `LabelEncoder` operator.
+// Values without a code map to `NaN`.
+func (e *GLMMRegression) ONNXNode(name, input, output string) []byte {
+	return onnxFloatLabelEncoder(name, input, output, e.encoder)
+}
+
+// ONNXNode will return the encoder as a serialized ONNX
+// `NodeProto` for the ai.onnx.ml `LabelEncoder` operator.
+// Values without a code map to `NaN`.
+func (e *GLMMClassification) ONNXNode(name, input, output string) []byte {
+	return onnxFloatLabelEncoder(name, input, output, e.encoder)
+}
+
+// ONNXNode will return the encoder as a serialized ONNX
+// `NodeProto` for the ai.onnx.ml `LabelEncoder` operator.
+// Values without a code map to `NaN`.
+func (e *ProbabilityRatio) ONNXNode(name, input, output string) []byte {
+	return onnxFloatLabelEncoder(name, input, output, e.encoder)
+}
+
+// ONNXNode will return the encoder as a serialized ONNX
+// `NodeProto` for the ai.onnx.ml `LabelEncoder` operator.
+// Values without a code map to `NaN`.
+func (e *LogOdds) ONNXNode(name, input, output string) []byte {
+	return onnxFloatLabelEncoder(name, input, output, e.encoder)
+}
+
+func onnxFloatLabelEncoder(name, input, output string, encoder map[string]float64) []byte {
+	keys := make([]string, 0, len(encoder))
+	for k := range encoder {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	values := make([]float32, len(keys), len(keys))
+	for i, k := range keys {
+		values[i] = float32(encoder[k])
+	}
+
+	return onnxNode(name, "LabelEncoder", input, output,
+		onnxStringsAttribute("keys_strings", keys),
+		onnxFloatsAttribute("values_floats", values),
+		onnxFloatAttribute("default_float", float32(math.NaN())),
+	)
+}
+
+// onnxNode will serialize a NodeProto in the ai.onnx.ml domain.
+func onnxNode(name, opType, input, output string, attributes ...[]byte) []byte {
+	var b []byte
+	b = protoBytes(b, 1, []byte(input))
+	b = protoBytes(b, 2, []byte(output))
+	b = protoBytes(b, 3, []byte(name))
+	b = protoBytes(b, 4, []byte(opType))
+	for _, attribute := range attributes {
+		b = protoBytes(b, 5, attribute)
+	}
+	b = protoBytes(b, 7, []byte(onnxMLDomain))
+
+	return b
+}
+
+func onnxIntAttribute(name string, v int64) []byte {
+	var b []byte
+	b = protoBytes(b, 1, []byte(name))
+	b = protoVarint(b, 3, uint64(v))
+	b = protoVarint(b, 20, onnxAttributeInt)
+	return b
+}
+
+func onnxFloatAttribute(name string, v float32) []byte {
+	var b []byte
+	b = protoBytes(b, 1, []byte(name))
+	b = protoTag(b, 2, 5)
+	b = appendFloat32(b, v)
+	b = protoVarint(b, 20, onnxAttributeFloat)
+	return b
+}
+
+func onnxIntsAttribute(name string, v []int64) []byte {
+	var packed []byte
+	for _, i := range v {
+		packed = appendUvarint(packed, uint64(i))
+	}
+
+	var b []byte
+	b = protoBytes(b, 1, []byte(name))
+	b = protoBytes(b, 8, packed)
+	b = protoVarint(b, 20, onnxAttributeInts)
+	return b
+}
+
+func onnxFloatsAttribute(name string, v []float32) []byte {
+	var packed []byte
+	for _, f := range v {
+		packed = appendFloat32(packed, f)
+	}
+
+	var b []byte
+	b = protoBytes(b, 1, []byte(name))
+	b = protoBytes(b, 7, packed)
+	b = protoVarint(b, 20, onnxAttributeFloats)
+	return b
+}
+
+func onnxStringsAttribute(name string, v []string) []byte {
+	var b []byte
+	b = protoBytes(b, 1, []byte(name))
+	for _, s := range v {
+		b = protoBytes(b, 9, []byte(s))
+	}
+	b = protoVarint(b, 20, onnxAttributeStrings)
+	return b
+}
+
+func protoTag(b []byte, field, wireType uint64) []byte {
+	return appendUvarint(b, field<<3|wireType)
+}
+
+func protoVarint(b []byte, field, v uint64) []byte {
+	b = protoTag(b, field, 0)
+	return appendUvarint(b, v)
+}
+
+func protoBytes(b []byte, field uint64, v []byte) []byte {
+	b = protoTag(b, field, 2)
+	b = appendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+func appendFloat32(b []byte, v float32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], math.Float32bits(v))
+	return append(b, buf[:]...)
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
@@ -0,0 +1,33 @@
+package encoder
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestOrdinalONNXNode(t *testing.T) {
+	encoder := NewOrdinal(true)
+	encoder.Encode("red")
+
+	node := encoder.ONNXNode("ordinal", "color", "color_code")
+	if !bytes.HasPrefix(node, []byte("\x0a\x05color\x12\x0acolor_code")) {
+		t.Errorf("node did not begin with input and output: %q", node)
+	}
+
+	for _, s := range []string{"LabelEncoder", "keys_strings", "red", "ai.onnx.ml"} {
+		if !bytes.Contains(node, []byte(s)) {
+			t.Errorf("node did not contain %q", s)
+		}
+	}
+}
+
+func TestOneHotONNXNode(t *testing.T) {
+	encoder := NewOneHot(WithDropFirst())
+	encoder.Encode("red")
+
+	node := encoder.ONNXNode("onehot", "color", "color_onehot")
+	expected := []byte("\x4a\x03red")
+	if !bytes.Contains(node, expected) || bytes.Contains(node, []byte("\x4a\x00")) {
+		t.Errorf("node categories were not [red]: %q", node)
+	}
+}
@@ -1,326 +1,1010 @@
-// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
-//
-// Licensed under the Apache License, Version 2.0 (the "License");
-// you may not use this file except in compliance with the License.
-// You may obtain a copy of the License at
-//
-//     http://www.apache.org/licenses/LICENSE-2.0
-//
-// Unless required by applicable law or agreed to in writing, software
-// distributed under the License is distributed on an "AS IS" BASIS,
-// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-// See the License for the specific language governing permissions and
-// limitations under the License.
-
-package encoder
-
-import (
-	"bytes"
-	"encoding/csv"
-	"encoding/gob"
-	"encoding/json"
-	"fmt"
-	"hash/fnv"
-	"io"
-	"strconv"
-	"sync"
-
-	"github.com/humilityai/sam"
-)
-
-// Ordinal will encode string values into
-// a unique integer value.
-// The empty string is ALWAYS the 0 value.
-// It will also allow for string values to be decoded.
-type Ordinal struct {
-	encoder map[uint64]uint64
-	decoder sam.SliceString
-	*sync.RWMutex
-}
-
-// NewOrdinal will create a new ordinal encoder.
-// If the `init` boolean is specified as true,
-// then the encoder will intialize with the
-// empty string `""` encoded as the `0` value.
-func NewOrdinal(init bool) *Ordinal {
-	e := &Ordinal{
-		encoder: make(map[uint64]uint64),
-		decoder: make(sam.SliceString, 0),
-		RWMutex: &sync.RWMutex{},
-	}
-
-	// set empty string as 0
-	if init {
-		e.Encode("")
-	}
-
-	return e
-}
-
-// Contains will return whether or not a string
-// has been assigned an ordinal code or not.
-func (e *Ordinal) Contains(s string) bool {
-	e.RLock()
-	defer e.RUnlock()
-
-	hasher := fnv.New64a()
-	_, err := hasher.Write([]byte(s))
-	if err != nil {
-		return false
-	}
-	hashedKey := hasher.Sum64()
-
-	_, ok := e.encoder[hashedKey]
-	return ok
-}
-
-// ContainsCode ...
-func (e *Ordinal) ContainsCode(code int) bool {
-	e.RLock()
-	defer e.RUnlock()
-
-	if len(e.decoder) >= code {
-		return false
-	}
-
-	return true
-}
-
-// Encode ...
-func (e *Ordinal) Encode(s string) uint64 {
-	e.Lock()
-	defer e.Unlock()
-
-	hasher := fnv.New64a()
-	_, err := hasher.Write([]byte(s))
-	if err != nil {
-		return 0
-	}
-	hashedKey := hasher.Sum64()
-
-	v, ok := e.encoder[hashedKey]
-	if !ok {
-		code := uint64(len(e.decoder))
-		e.decoder = append(e.decoder, s)
-		e.encoder[hashedKey] = code
-		return code
-	}
-
-	return v
-}
-
-// EncodeStringer --
-func (e *Ordinal) EncodeStringer(s fmt.Stringer) uint64 {
-	return e.Encode(s.String())
-}
-
-// EncodeBytes --
-func (e *Ordinal) EncodeBytes(b []byte) uint64 {
-	return e.Encode(string(b[:]))
-}
-
-// Decode will return an empty string if supplied integer
-// argument is not a valid code.
-func (e *Ordinal) Decode(i uint64) string {
-	e.RLock()
-	defer e.RUnlock()
-
-	if i > uint64(len(e.decoder)-1) || i < 0 {
-		return ""
-	}
-
-	return e.decoder[i]
-}
-
-// DecodeSlice will decode all the values in
-// the slice of integers provided as an argument.
-// If a string value has no existing encoding then
-// it will be returned as the empty string.
-func (e *Ordinal) DecodeSlice(s sam.SliceInt) sam.SliceString {
-	e.RLock()
-	defer e.RUnlock()
-
-	values := make(sam.SliceString, len(s), len(s))
-	for i, v := range s {
-		values[i] = e.Decode(uint64(v))
-	}
-
-	return values
-}
-
-// EncodeSlice will encode all the values in the slice of strings
-// provided as an argument.
-func (e *Ordinal) EncodeSlice(s sam.SliceString) []uint64 {
-	e.Lock()
-	defer e.Unlock()
-
-	codes := make([]uint64, len(s), len(s))
-	for i, v := range s {
-		codes[i] = e.Encode(v)
-	}
-
-	return codes
-}
-
-// Length ...
-func (e *Ordinal) Length() int {
-	e.RLock()
-	defer e.RUnlock()
-
-	return len(e.decoder)
-}
-
-// List ...
-func (e *Ordinal) List() sam.SliceString {
-	e.RLock()
-	defer e.RUnlock()
-
-	return e.decoder
-}
-
-// MarshalJSON ...
-func (e *Ordinal) MarshalJSON() ([]byte, error) {
-	return json.Marshal(e.decoder)
-}
-
-// UnmarshalJSON ...
-func (e *Ordinal) UnmarshalJSON(data []byte) error {
-	s := make(sam.SliceString, 0)
-	err := json.Unmarshal(data, &s)
-	if err != nil {
-		return err
-	}
-
-	hasher := fnv.New64a()
-	encoder := make(map[uint64]uint64)
-	for idx, str := range s {
-		_, err := hasher.Write([]byte(str))
-		if err != nil {
-			return err
-		}
-		hashedKey := hasher.Sum64()
-		encoder[hashedKey] = uint64(idx)
-		hasher.Reset()
-	}
-
-	e.encoder = encoder
-	e.decoder = s
-
-	return nil
-}
-
-// MarshalCSV ...
-func (e *Ordinal) MarshalCSV() ([]byte, error) {
-	var lines [][]string
-
-	// header
-	lines = append(lines, []string{"value", "code"})
-
-	for idx, value := range e.decoder {
-		line := []string{value, strconv.Itoa(idx)}
-		lines = append(lines, line)
-	}
-
-	var b bytes.Buffer
-	w := csv.NewWriter(&b)
-	err := w.WriteAll(lines)
-	if err != nil {
-		return []byte{}, err
-	}
-
-	return b.Bytes(), nil
-}
-
-// UnmarshalCSV ...
-func (e *Ordinal) UnmarshalCSV(data []byte) error {
-	r := csv.NewReader(bytes.NewReader(data))
-
-	decoder := make(sam.SliceString, 0)
-	for i := 0; ; i++ {
-		if i == 0 {
-			_, err := r.Read()
-			if err != nil && err != io.EOF {
-				return err
-			}
-			continue
-		}
-
-		line, err := r.Read()
-		if err == io.EOF {
-			break
-		} else if err == nil {
-			code, err := strconv.Atoi(line[1])
-			if err == nil {
-				hasher := fnv.New64a()
-				_, err := hasher.Write([]byte(line[0]))
-				if err != nil {
-					return err
-				}
-				hashedKey := hasher.Sum64()
-				e.encoder[hashedKey] = uint64(code)
-				if code > len(decoder)-1 {
-					newCap := len(decoder) + (code - (len(decoder) - 1))
-					newArray := make(sam.SliceString, newCap, newCap)
-					copy(newArray, decoder)
-					decoder = newArray
-				}
-				decoder[code] = line[0]
-			} else {
-				return err
-			}
-		}
-	}
-
-	e.decoder = decoder
-
-	return nil
-}
-
-// GobEncode ...
-func (e *Ordinal) GobEncode() ([]byte, error) {
-	e.Lock()
-	defer e.Unlock()
-
-	var buf bytes.Buffer
-
-	enc := gob.NewEncoder(&buf)
-
-	eCopy := struct {
-		Encoder map[uint64]uint64
-		Decoder []string
-	}{
-		Encoder: e.encoder,
-		Decoder: e.decoder,
-	}
-
-	err := enc.Encode(eCopy)
-	if err != nil {
-		return []byte{}, err
-	}
-
-	return buf.Bytes(), nil
-}
-
-// GobDecode ...
-func (e *Ordinal) GobDecode(data []byte) error {
-	var buf bytes.Buffer
-	_, err := buf.Write(data)
-	if err != nil {
-		return err
-	}
-
-	var eCopy struct {
-		Encoder map[uint64]uint64
-		Decoder []string
-	}
-
-	dec := gob.NewDecoder(&buf)
-	err = dec.Decode(&eCopy)
-	if err != nil {
-		return err
-	}
-
-	e.encoder = eCopy.Encoder
-	e.decoder = sam.SliceString(eCopy.Decoder)
-	return nil
-}
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/humilityai/sam"
+)
+
+// Ordinal will encode string values into
+// a unique integer value, assigned in the order
+// the values are first seen. Code 0 is the empty
+// string if the encoder was initialized with it,
+// or `MissingCode` if it was created `WithMissing`.
+// It will also allow for string values to be decoded.
+type Ordinal struct {
+	encoder         map[uint64]uint64
+	decoder         *arena
+	trie            *trie
+	preprocessNames []string
+	preprocess      []Normalizer
+	missing         MissingPolicy
+	onNew           NewCategoryFunc
+	metrics         Metrics
+	wal             io.Writer
+	walErr          error
+	reserved        map[uint64]bool
+	canonical       bool
+	counts          map[uint64]int
+	aliases         map[string]string
+	ttl             time.Duration
+	lastSeen        map[uint64]time.Time
+	expired         map[uint64]bool
+	changes         uint64
+	history         []change
+	historyStart    uint64
+	created         time.Time
+	updated         time.Time
+	*sync.RWMutex
+}
+
+// NewCategoryFunc is called with every new
+// value an encoder assigns a code to.
+type NewCategoryFunc func(value string, code uint64)
+
+// OrdinalOption configures optional behaviour
+// of an Ordinal encoder at construction time.
+type OrdinalOption func(*Ordinal)
+
+// WithTrieIndex will maintain a trie over every
+// encoded value so that prefix searches do not
+// need to scan the entire vocabulary.
+func WithTrieIndex() OrdinalOption {
+	return func(e *Ordinal) {
+		e.trie = newTrie()
+	}
+}
+
+// WithMissing will encode every value considered missing by
+// the policy as `MissingCode`, a code reserved for missing
+// values, so they never share a code with observed values.
+// The empty string is only missing if the policy says so;
+// otherwise it is an observed value with a code of its own.
+func WithMissing(policy MissingPolicy) OrdinalOption {
+	return func(e *Ordinal) {
+		e.missing = policy
+	}
+}
+
+// WithMetrics will report every encoding, the vocabulary
+// size and the time spent waiting for the encoder's write
+// lock to the given metrics hook.
+func WithMetrics(m Metrics) OrdinalOption {
+	return func(e *Ordinal) {
+		e.metrics = m
+	}
+}
+
+// NewOrdinal will create a new ordinal encoder.
+// If the `init` boolean is specified as true,
+// then the encoder will intialize with the
+// empty string `""` encoded as the `0` value,
+// unless the encoder was created `WithMissing`,
+// in which case `0` is `MissingCode`.
+func NewOrdinal(init bool, opts ...OrdinalOption) *Ordinal {
+	e := &Ordinal{
+		encoder: make(map[uint64]uint64),
+		decoder: newArena(nil),
+		created: time.Now(),
+		RWMutex: &sync.RWMutex{},
+	}
+	e.updated = e.created
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	// reserve 0 for missing values
+	if e.missing != nil {
+		e.encoder[missingHash] = uint64(e.decoder.append(""))
+	}
+
+	// set empty string as 0
+	if init {
+		e.Encode("")
+	}
+
+	return e
+}
+
+// NewOrdinalFromMap will create an ordinal encoder from an
+// existing table of values and codes. The codes must be dense,
+// from 0 to len(m)-1 with each code used exactly once, or from
+// 1 to len(m) if the encoder is created `WithMissing`, as 0 is
+// then `MissingCode`. Otherwise an `ErrCodeTaken` error is
+// returned for a code used more than once or for `MissingCode`,
+// or an `ErrNotDense` error for a code outside that range.
+func NewOrdinalFromMap(m map[string]uint64, opts ...OrdinalOption) (*Ordinal, error) {
+	e := NewOrdinal(false, opts...)
+	decoder, err := denseValues(m, e.firstCode())
+	if err != nil {
+		return NewOrdinal(false), err
+	}
+
+	encoder := make(map[uint64]uint64, len(m))
+	for value, code := range m {
+		encoder[hashString(value)] = code
+	}
+
+	e.encoder = encoder
+	e.decoder = newArena(decoder)
+	e.loaded()
+
+	return e, nil
+}
+
+// firstCode will return the first code a table of
+// values may use, which is after `MissingCode` if
+// the encoder reserves it.
+func (e *Ordinal) firstCode() uint64 {
+	if e.missing != nil {
+		return MissingCode + 1
+	}
+
+	return 0
+}
+
+// denseValues will return the values of the table indexed
+// by their codes, which must be dense from `first`, with
+// the errors of NewOrdinalFromMap. Codes below `first`
+// are held by the empty string.
+func denseValues(m map[string]uint64, first uint64) (sam.SliceString, error) {
+	length := first + uint64(len(m))
+	for _, code := range m {
+		if code < first {
+			return nil, ErrCodeTaken
+		}
+		if code >= length {
+			return nil, ErrNotDense
+		}
+	}
+
+	values := make(sam.SliceString, length, length)
+	assigned := make([]bool, length, length)
+	for value, code := range m {
+		if assigned[code] {
+			return nil, ErrCodeTaken
+		}
+
+		assigned[code] = true
+		values[code] = value
+	}
+
+	return values, nil
+}
+
+// NewOrdinalFrom will create an ordinal encoder that starts
+// with the vocabulary, aliases, normalizers, preprocessors and
+// missing policy of an existing encoder. Every value keeps its
+// existing code and new values are assigned codes after the
+// existing ones, so models trained on the existing codes remain
+// valid. The existing encoder is not modified.
+func NewOrdinalFrom(existing *Ordinal, opts ...OrdinalOption) *Ordinal {
+	e := NewOrdinal(false, opts...)
+
+	existing.RLock()
+	e.encoder = copyCodes(existing.encoder)
+	e.decoder = existing.decoder.clone()
+	e.missing = existing.missing
+	e.preprocessNames = existing.preprocessNames
+	e.preprocess = existing.preprocess
+	if existing.aliases != nil {
+		e.aliases = make(map[string]string, len(existing.aliases))
+		for alias, canonical := range existing.aliases {
+			e.aliases[alias] = canonical
+		}
+	}
+	for code := range existing.reserved {
+		e.reserve(code, code+1)
+	}
+	if e.expired != nil {
+		for code := range existing.expired {
+			e.expired[code] = true
+		}
+	}
+	existing.RUnlock()
+
+	if e.lastSeen != nil {
+		now := time.Now()
+		for _, code := range e.encoder {
+			e.lastSeen[code] = now
+		}
+	}
+	e.loaded()
+
+	return e
+}
+
+// Contains will return whether or not a string
+// has been assigned an ordinal code or not.
+func (e *Ordinal) Contains(s string) bool {
+	e.RLock()
+	defer e.RUnlock()
+
+	return e.contains(s)
+}
+
+func (e *Ordinal) contains(s string) bool {
+	s = e.prepare(s)
+
+	hasher := fnv.New64a()
+	_, err := hasher.Write([]byte(s))
+	if err != nil {
+		return false
+	}
+	hashedKey := hasher.Sum64()
+
+	_, ok := e.encoder[hashedKey]
+	return ok
+}
+
+// ContainsCode ...
+func (e *Ordinal) ContainsCode(code int) bool {
+	e.RLock()
+	defer e.RUnlock()
+
+	if e.decoder.len() >= code {
+		return false
+	}
+
+	return true
+}
+
+// Encode ...
+func (e *Ordinal) Encode(s string) uint64 {
+	e.lock()
+	defer e.Unlock()
+
+	return e.encode(s)
+}
+
+// EncodeChecked will return the code of the given string like
+// Encode, but returns any error that occurs while encoding it
+// rather than the code 0, which is also the code of a value.
+// If the record of a new value cannot be written to the
+// write-ahead log the value is still encoded, and its code
+// is returned along with the error.
+func (e *Ordinal) EncodeChecked(s string) (uint64, error) {
+	e.lock()
+	defer e.Unlock()
+
+	return e.encodeChecked(s)
+}
+
+func (e *Ordinal) encode(s string) uint64 {
+	code, _ := e.encodeChecked(s)
+	return code
+}
+
+func (e *Ordinal) encodeChecked(s string) (uint64, error) {
+	s = e.prepare(s)
+
+	hasher := fnv.New64a()
+	_, err := hasher.Write([]byte(s))
+	if err != nil {
+		return 0, err
+	}
+	hashedKey := hasher.Sum64()
+
+	v, ok := e.encoder[hashedKey]
+	if e.metrics != nil {
+		e.metrics.Encoded(ok)
+	}
+	if !ok {
+		code := uint64(e.decoder.append(s))
+		return code, e.insert(s, hashedKey, code)
+	}
+
+	if e.lastSeen != nil {
+		e.lastSeen[v] = time.Now()
+	}
+	if e.counts != nil {
+		e.counts[v]++
+	}
+
+	return v, nil
+}
+
+// insert will index the prepared value under the code
+// it has been given in the decoder, recording it as a
+// new value.
+func (e *Ordinal) insert(s string, hashedKey, code uint64) error {
+	var err error
+	e.encoder[hashedKey] = code
+	e.updated = time.Now()
+	if e.lastSeen != nil {
+		e.lastSeen[code] = e.updated
+	}
+	if e.counts != nil {
+		e.counts[code]++
+	}
+	if e.trie != nil {
+		e.trie.insert(s, code)
+	}
+	if e.wal != nil {
+		err = e.logWAL(s, code)
+	}
+	if e.onNew != nil {
+		e.onNew(s, code)
+	}
+	if e.metrics != nil {
+		e.metrics.VocabularySize(e.decoder.len())
+	}
+
+	return err
+}
+
+// OnNewCategory will register a callback that is called
+// whenever Encode assigns a code to a new value, replacing
+// any previously registered callback. The callback is
+// called while the encoder is locked, so it must not call
+// the encoder. A nil callback removes the callback.
+func (e *Ordinal) OnNewCategory(f NewCategoryFunc) {
+	e.Lock()
+	defer e.Unlock()
+
+	e.onNew = f
+}
+
+// EncodeStringer --
+func (e *Ordinal) EncodeStringer(s fmt.Stringer) uint64 {
+	return e.Encode(s.String())
+}
+
+// EncodeBytes --
+func (e *Ordinal) EncodeBytes(b []byte) uint64 {
+	return e.Encode(string(b[:]))
+}
+
+// Decode will return an empty string if supplied integer
+// argument is not a valid code.
+func (e *Ordinal) Decode(i uint64) string {
+	e.RLock()
+	defer e.RUnlock()
+
+	return e.decode(i)
+}
+
+func (e *Ordinal) decode(i uint64) string {
+	if i >= uint64(e.decoder.len()) {
+		return ""
+	}
+
+	return e.decoder.get(int(i))
+}
+
+// DecodeChecked will return the string for the given code,
+// or an `ErrBounds` error if the code is not a valid code,
+// so that invalid codes can be told apart from a legitimately
+// encoded empty string.
+func (e *Ordinal) DecodeChecked(i uint64) (string, error) {
+	e.RLock()
+	defer e.RUnlock()
+
+	if i >= uint64(e.decoder.len()) {
+		return "", ErrBounds
+	}
+
+	if e.expired[i] {
+		return "", ErrExpired
+	}
+
+	return e.decoder.get(int(i)), nil
+}
+
+// DecodeSlice will decode all the values in
+// the slice of integers provided as an argument.
+// If a string value has no existing encoding then
+// it will be returned as the empty string.
+func (e *Ordinal) DecodeSlice(s sam.SliceInt) sam.SliceString {
+	e.RLock()
+	defer e.RUnlock()
+
+	values := make(sam.SliceString, len(s), len(s))
+	for i, v := range s {
+		values[i] = e.decode(uint64(v))
+	}
+
+	return values
+}
+
+// EncodeSlice will encode all the values in the slice of strings
+// provided as an argument.
+func (e *Ordinal) EncodeSlice(s sam.SliceString) []uint64 {
+	e.lock()
+	defer e.Unlock()
+
+	codes := make([]uint64, len(s), len(s))
+	for i, v := range s {
+		codes[i] = e.encode(v)
+	}
+
+	return codes
+}
+
+// EncodeSliceChecked will encode all the values in the slice
+// like EncodeSlice, returning the first error that occurs.
+// See EncodeChecked.
+func (e *Ordinal) EncodeSliceChecked(s sam.SliceString) ([]uint64, error) {
+	e.lock()
+	defer e.Unlock()
+
+	var first error
+	codes := make([]uint64, len(s), len(s))
+	for i, v := range s {
+		code, err := e.encodeChecked(v)
+		if err != nil && first == nil {
+			first = err
+		}
+		codes[i] = code
+	}
+
+	return codes, first
+}
+
+// EncodeSliceInto will encode all the values in `src` into
+// the caller-provided `dst`, so that buffers can be reused
+// between batches. If `dst` is not the same length as `src`
+// an `ErrLength` error is returned and nothing is encoded.
+func (e *Ordinal) EncodeSliceInto(dst []uint64, src []string) error {
+	if len(dst) != len(src) {
+		return ErrLength
+	}
+
+	e.lock()
+	defer e.Unlock()
+
+	for i, v := range src {
+		dst[i] = e.encode(v)
+	}
+
+	return nil
+}
+
+// DecodeSliceInto will decode all the codes in `src` into
+// the caller-provided `dst`. Invalid codes decode to the
+// empty string. If `dst` is not the same length as `src`
+// an `ErrLength` error is returned and nothing is decoded.
+func (e *Ordinal) DecodeSliceInto(dst []string, src []uint64) error {
+	if len(dst) != len(src) {
+		return ErrLength
+	}
+
+	e.RLock()
+	defer e.RUnlock()
+
+	for i, v := range src {
+		dst[i] = e.decode(v)
+	}
+
+	return nil
+}
+
+// Length ...
+func (e *Ordinal) Length() int {
+	e.RLock()
+	defer e.RUnlock()
+
+	return e.decoder.len()
+}
+
+// List will return a copy of every encoded
+// value, indexed by code.
+func (e *Ordinal) List() sam.SliceString {
+	e.RLock()
+	defer e.RUnlock()
+
+	return e.decoder.strings()
+}
+
+// Range will call `f` with every code and its value,
+// in ascending code order, until `f` returns false.
+// The codes are those of the vocabulary when Range is
+// called. The encoder is not locked while ranging, so
+// `f` may call the encoder, including to encode values.
+func (e *Ordinal) Range(f func(code uint64, value string) bool) {
+	e.RLock()
+	decoder := e.decoder.view()
+	e.RUnlock()
+
+	for code := 0; code < decoder.len(); code++ {
+		if !f(uint64(code), decoder.get(code)) {
+			return
+		}
+	}
+}
+
+// ordinalJSON is the JSON form of an encoder with aliases,
+// preprocessors, reserved or expired codes, or canonical
+// serialization.
+type ordinalJSON struct {
+	Values        []string           `json:"values"`
+	Aliases       map[string]string  `json:"aliases,omitempty"`
+	Preprocessors []string           `json:"preprocessors,omitempty"`
+	Reserved      []uint64           `json:"reserved,omitempty"`
+	Expired       []uint64           `json:"expired,omitempty"`
+	Changes       uint64             `json:"changes,omitempty"`
+	Canonical     bool               `json:"canonical,omitempty"`
+	Integrity     *snapshotIntegrity `json:"integrity,omitempty"`
+}
+
+// MarshalJSON will encode the values as an array indexed
+// by code, or, if the encoder has aliases, preprocessors,
+// reserved or expired codes, or canonical serialization, as an
+// object holding the array of values, the alias table, the
+// names of the preprocessors, the reserved and expired codes,
+// the number of changes counted by Version, whether
+// serialization is canonical and the entry count and
+// checksum of the whole object. The array form, shared with
+// other tools, has no checksum.
+func (e *Ordinal) MarshalJSON() ([]byte, error) {
+	o, ok := e.jsonObject()
+	if !ok {
+		return json.Marshal(e.decoder.strings())
+	}
+
+	o.Values = e.decoder.strings()
+	o.Integrity = newOrdinalIntegrity(o.Values, o.tables())
+	return json.Marshal(o)
+}
+
+// jsonObject will return the JSON object form of the encoder
+// without its values and integrity, and whether the encoder
+// needs it rather than the array form.
+func (e *Ordinal) jsonObject() (ordinalJSON, bool) {
+	o := ordinalJSON{
+		Aliases:       e.aliases,
+		Preprocessors: e.preprocessNames,
+		Reserved:      sortedCodes(e.reserved),
+		Expired:       sortedCodes(e.expired),
+		Changes:       e.changes,
+		Canonical:     e.canonical,
+	}
+
+	return o, len(o.Aliases) > 0 || len(o.Preprocessors) > 0 || len(o.Reserved) > 0 || len(o.Expired) > 0 || o.Changes > 0 || o.Canonical
+}
+
+func (o *ordinalJSON) tables() ordinalTables {
+	return ordinalTables{
+		aliases:       o.Aliases,
+		preprocessors: o.Preprocessors,
+		reserved:      o.Reserved,
+		expired:       o.Expired,
+		changes:       o.Changes,
+	}
+}
+
+// UnmarshalJSON will also accept an object mapping every
+// value to its code, the form written by most other tools,
+// returning the errors of NewOrdinalFromMap if the codes
+// are not dense. It will return an
+// `ErrCorruptSnapshot` error if an object does not match its
+// entry count or checksum, and an `ErrPreprocessor` error if
+// the encoder uses a preprocessor that is not registered.
+// The encoder is left unchanged if an error is returned.
+func (e *Ordinal) UnmarshalJSON(data []byte) error {
+	var o ordinalJSON
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		if !isOrdinalJSON(trimmed) {
+			return e.unmarshalJSONMap(trimmed)
+		}
+
+		err := json.Unmarshal(trimmed, &o)
+		if err != nil {
+			return err
+		}
+		if o.Integrity != nil && !o.Integrity.verifyOrdinal(o.Values, o.tables()) {
+			return ErrCorruptSnapshot
+		}
+	} else {
+		err := json.Unmarshal(data, &o.Values)
+		if err != nil {
+			return err
+		}
+	}
+
+	preprocess, err := lookupPreprocessors(o.Preprocessors)
+	if err != nil {
+		return err
+	}
+
+	e.loadJSON(&o, newArena(o.Values), preprocess)
+	return nil
+}
+
+// loadJSON will replace the contents of the encoder
+// with a JSON snapshot that has been read and checked,
+// whose values are held by the decoder.
+func (e *Ordinal) loadJSON(o *ordinalJSON, decoder *arena, preprocess []Normalizer) {
+	e.encoder = e.indexValues(decoder, codeSet(o.Reserved), codeSet(o.Expired))
+	e.decoder = decoder
+	e.restoreReserved(o.Reserved)
+	e.restoreExpired(o.Expired)
+	e.changes = o.Changes
+	e.preprocessNames, e.preprocess = o.Preprocessors, preprocess
+	e.canonical = e.canonical || o.Canonical
+	e.loaded()
+	e.restoreAliases(o.Aliases)
+}
+
+// isOrdinalJSON will return whether the JSON object is the
+// form written by MarshalJSON rather than a map of codes.
+func isOrdinalJSON(data []byte) bool {
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		// let the caller report the error
+		return true
+	}
+
+	values, ok := fields["values"]
+	return ok && len(values) > 0 && values[0] == '['
+}
+
+// unmarshalJSONMap will load a JSON object
+// mapping every value to its code.
+func (e *Ordinal) unmarshalJSONMap(data []byte) error {
+	var m map[string]uint64
+	err := json.Unmarshal(data, &m)
+	if err != nil {
+		return err
+	}
+
+	values, err := denseValues(m, e.firstCode())
+	if err != nil {
+		return err
+	}
+
+	codes := make([]uint64, len(values), len(values))
+	for code := range codes {
+		codes[code] = uint64(code)
+	}
+
+	err = e.loadRows(values, codes)
+	if err != nil {
+		return err
+	}
+
+	e.preprocessNames, e.preprocess = nil, nil
+	return nil
+}
+
+// MarshalCSV will write a row for every value followed by
+// a row for every alias, holding the code of its value.
+func (e *Ordinal) MarshalCSV() ([]byte, error) {
+	return e.MarshalCSVDialect(CSVDialect{})
+}
+
+// MarshalCSVDialect will write the rows of MarshalCSV
+// in the given dialect.
+func (e *Ordinal) MarshalCSVDialect(d CSVDialect) ([]byte, error) {
+	var lines [][]string
+
+	for idx, value := range e.decoder.strings() {
+		line := []string{value, strconv.Itoa(idx)}
+		lines = append(lines, line)
+	}
+
+	for _, alias := range e.sortedAliases() {
+		code := e.encoder[hashString(alias)]
+		lines = append(lines, []string{alias, strconv.FormatUint(code, 10)})
+	}
+
+	return d.write(lines)
+}
+
+// UnmarshalCSV will treat every row holding the code
+// of an earlier row as an alias of that row's value.
+func (e *Ordinal) UnmarshalCSV(data []byte) error {
+	return e.UnmarshalCSVDialect(data, CSVDialect{})
+}
+
+// UnmarshalCSVDialect will read rows in the given
+// dialect in the same way as UnmarshalCSV.
+func (e *Ordinal) UnmarshalCSVDialect(data []byte, d CSVDialect) error {
+	lines, err := d.read(data)
+	if err != nil {
+		return err
+	}
+
+	values := make([]string, len(lines), len(lines))
+	codes := make([]uint64, len(lines), len(lines))
+	for i, line := range lines {
+		code, err := strconv.ParseUint(line[1], 10, 64)
+		if err != nil {
+			return err
+		}
+		values[i], codes[i] = line[0], code
+	}
+
+	return e.loadRows(values, codes)
+}
+
+// loadRows will replace the contents of the encoder with
+// the values and their codes. Codes without a value decode
+// to the empty string, and every value holding the code of
+// an earlier value is an alias of that value. Rows hold no
+// reserved or expired codes. Every code must be below the
+// number of rows, otherwise an `ErrCorruptSnapshot` error is
+// returned and the encoder is left unchanged.
+func (e *Ordinal) loadRows(values []string, codes []uint64) error {
+	var length uint64
+	for _, code := range codes {
+		if code >= uint64(len(codes)) {
+			return ErrCorruptSnapshot
+		}
+		if code >= length {
+			length = code + 1
+		}
+	}
+
+	e.restoreReserved(nil)
+	e.restoreExpired(nil)
+	e.changes = 0
+	encoder := make(map[uint64]uint64, len(values))
+	decoder := make(sam.SliceString, length, length)
+	assigned := make([]bool, length, length)
+	aliases := make(map[string]string)
+	for i, value := range values {
+		code := codes[i]
+		if !e.placeholder(code, value, nil, nil) {
+			encoder[hashString(value)] = code
+		}
+		if assigned[code] {
+			aliases[value] = decoder[code]
+			continue
+		}
+		assigned[code] = true
+		decoder[code] = value
+	}
+
+	e.encoder = encoder
+	e.decoder = newArena(decoder)
+	e.loaded()
+	e.restoreAliases(aliases)
+	return nil
+}
+
+// ordinalGob is the Gob form of an encoder. Canonical
+// snapshots hold the code table and aliases as sorted
+// slices instead of maps, which Gob writes in random order.
+type ordinalGob struct {
+	Encoder       map[uint64]uint64
+	Decoder       []string
+	Integrity     *snapshotIntegrity
+	Aliases       map[string]string
+	Preprocessors []string
+	Codes         []uint64
+	AliasPairs    []string
+	Reserved      []uint64
+	Expired       []uint64
+	Changes       uint64
+	Canonical     bool
+}
+
+// GobEncode ...
+func (e *Ordinal) GobEncode() ([]byte, error) {
+	e.Lock()
+	defer e.Unlock()
+
+	var buf bytes.Buffer
+
+	enc := gob.NewEncoder(&buf)
+
+	eCopy := ordinalGob{
+		Encoder:       e.encoder,
+		Decoder:       e.decoder.strings(),
+		Aliases:       e.aliases,
+		Preprocessors: e.preprocessNames,
+		Reserved:      sortedCodes(e.reserved),
+		Expired:       sortedCodes(e.expired),
+		Changes:       e.changes,
+		Canonical:     e.canonical,
+	}
+	eCopy.Integrity = newOrdinalIntegrity(eCopy.Decoder, eCopy.tables())
+	if e.canonical {
+		eCopy.canonicalize()
+	}
+
+	err := enc.Encode(eCopy)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// GobDecode will return an `ErrCorruptSnapshot` error if the
+// entry count or checksums recorded in the snapshot do not
+// match its contents, and an `ErrPreprocessor` error if the
+// encoder uses a preprocessor that is not registered. The
+// code table is rebuilt from the checked values rather than
+// read from the snapshot.
+func (e *Ordinal) GobDecode(data []byte) error {
+	var buf bytes.Buffer
+	_, err := buf.Write(data)
+	if err != nil {
+		return err
+	}
+
+	var eCopy ordinalGob
+
+	dec := gob.NewDecoder(&buf)
+	err = dec.Decode(&eCopy)
+	if err != nil {
+		return err
+	}
+	eCopy.uncanonicalize()
+
+	// snapshots written before checksums
+	// were added have no integrity record
+	if eCopy.Integrity != nil && !eCopy.Integrity.verifyOrdinal(eCopy.Decoder, eCopy.tables()) {
+		return ErrCorruptSnapshot
+	}
+
+	preprocess, err := lookupPreprocessors(eCopy.Preprocessors)
+	if err != nil {
+		return err
+	}
+
+	e.decoder = newArena(eCopy.Decoder)
+	e.encoder = e.indexValues(e.decoder, codeSet(eCopy.Reserved), codeSet(eCopy.Expired))
+	e.restoreReserved(eCopy.Reserved)
+	e.restoreExpired(eCopy.Expired)
+	e.changes = eCopy.Changes
+	e.preprocessNames, e.preprocess = eCopy.Preprocessors, preprocess
+	e.canonical = e.canonical || eCopy.Canonical
+	e.loaded()
+	e.restoreAliases(eCopy.Aliases)
+	return nil
+}
+
+// FindPrefix will return the codes of every encoded
+// value that begins with the given prefix, in
+// ascending code order.
+// If the encoder was created with `WithTrieIndex`
+// the trie is used, otherwise the vocabulary is scanned.
+func (e *Ordinal) FindPrefix(p string) []uint64 {
+	e.RLock()
+	defer e.RUnlock()
+
+	if e.trie != nil {
+		return e.trie.prefix(p)
+	}
+
+	codes := make([]uint64, 0)
+	for code := 0; code < e.decoder.len(); code++ {
+		if !e.expired[uint64(code)] && strings.HasPrefix(e.decoder.get(code), p) {
+			codes = append(codes, uint64(code))
+		}
+	}
+
+	return codes
+}
+
+// FindContains will return the codes of every encoded
+// value that contains the given substring, in
+// ascending code order.
+func (e *Ordinal) FindContains(sub string) []uint64 {
+	e.RLock()
+	defer e.RUnlock()
+
+	codes := make([]uint64, 0)
+	for code := 0; code < e.decoder.len(); code++ {
+		if !e.expired[uint64(code)] && strings.Contains(e.decoder.get(code), sub) {
+			codes = append(codes, uint64(code))
+		}
+	}
+
+	return codes
+}
+
+// lock will acquire the write lock, reporting
+// the time spent waiting to the metrics hook.
+func (e *Ordinal) lock() {
+	if e.metrics == nil {
+		e.Lock()
+		return
+	}
+
+	start := time.Now()
+	e.Lock()
+	e.metrics.LockWait(time.Since(start))
+}
+
+// prepare will return the value that is encoded
+// in place of the given string.
+func (e *Ordinal) prepare(s string) string {
+	s = normalize(e.preprocess, s)
+	if e.missing != nil && e.missing(s) {
+		return missingCategory
+	}
+
+	return s
+}
+
+func (g *ordinalGob) tables() ordinalTables {
+	return ordinalTables{
+		aliases:       g.Aliases,
+		preprocessors: g.Preprocessors,
+		reserved:      g.Reserved,
+		expired:       g.Expired,
+		changes:       g.Changes,
+	}
+}
+
+// placeholder will return whether the code of a loaded
+// vocabulary holds no value, as is the case for the code
+// of missing values and the given reserved and expired codes.
+func (e *Ordinal) placeholder(code uint64, value string, reserved, expired map[uint64]bool) bool {
+	if value != "" {
+		return false
+	}
+
+	return (code == MissingCode && e.missing != nil) || reserved[code] || expired[code]
+}
+
+// indexValues will return the code table of a loaded
+// vocabulary. Unassigned codes are serialized as the
+// empty string, so the first code of every value is kept.
+func (e *Ordinal) indexValues(decoder *arena, reserved, expired map[uint64]bool) map[uint64]uint64 {
+	encoder := make(map[uint64]uint64, decoder.len())
+	for code := 0; code < decoder.len(); code++ {
+		value := decoder.get(code)
+		if e.placeholder(uint64(code), value, reserved, expired) {
+			continue
+		}
+
+		hashedKey := hashString(value)
+		if _, ok := encoder[hashedKey]; !ok {
+			encoder[hashedKey] = uint64(code)
+		}
+	}
+
+	return encoder
+}
+
+// loaded will rebuild any state derived from the
+// decoder after the decoder has been replaced.
+func (e *Ordinal) loaded() {
+	e.updated = time.Now()
+	e.history, e.historyStart = nil, e.version()
+	if e.missing != nil && e.decoder.len() > 0 && e.decoder.get(int(MissingCode)) == "" {
+		e.encoder[missingHash] = MissingCode
+	}
+
+	if e.trie == nil {
+		return
+	}
+
+	e.trie = newTrie()
+	for code := 0; code < e.decoder.len(); code++ {
+		value := e.decoder.get(code)
+		if c, ok := e.encoder[hashString(value)]; ok && c == uint64(code) {
+			e.trie.insert(value, uint64(code))
+		}
+	}
+}
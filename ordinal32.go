@@ -0,0 +1,256 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"encoding/json"
+	"math"
+	"sync"
+)
+
+// Ordinal32 will encode string values into a unique integer
+// value like Ordinal, but stores codes, and the offsets of its
+// values, as 32-bit integers. This roughly halves the memory
+// of the code table for vocabularies of fewer than 4 billion
+// values whose values total less than 4 GiB. Codes are still
+// returned as uint64 so the encoders are interchangeable.
+type Ordinal32 struct {
+	encoder map[uint64]uint32
+	data    []byte
+	ends    []uint32
+	*sync.RWMutex
+}
+
+// NewOrdinal32 will create a new 32-bit ordinal encoder.
+// If the `init` boolean is specified as true, then the
+// encoder will intialize with the empty string `""`
+// encoded as the `0` value.
+func NewOrdinal32(init bool) *Ordinal32 {
+	e := &Ordinal32{
+		encoder: make(map[uint64]uint32),
+		RWMutex: &sync.RWMutex{},
+	}
+
+	if init {
+		e.Encode("")
+	}
+
+	return e
+}
+
+// Compact will return a 32-bit copy of the encoder, or an
+// `ErrCapacity` error if its codes or values do not fit in
+// 32 bits. Options of the encoder, such as normalizers, and
+// aliases are not copied.
+func (e *Ordinal) Compact() (*Ordinal32, error) {
+	e.RLock()
+	defer e.RUnlock()
+
+	decoder := e.decoder.flat()
+	if uint64(decoder.len()) > math.MaxUint32 || uint64(len(decoder.data)) > math.MaxUint32 {
+		return NewOrdinal32(false), ErrCapacity
+	}
+
+	c := NewOrdinal32(false)
+	c.data = append([]byte{}, decoder.data...)
+	c.ends = make([]uint32, len(decoder.ends), len(decoder.ends))
+	for i, end := range decoder.ends {
+		c.ends[i] = uint32(end)
+	}
+	for code := range c.ends {
+		v := c.get(code)
+		if _, ok := c.encoder[hashString(v)]; !ok {
+			c.encoder[hashString(v)] = uint32(code)
+		}
+	}
+
+	return c, nil
+}
+
+// Encode will return the code of the given string, assigning
+// it the next code if it has none. If the encoder is full the
+// string is not added and 0 is returned, which is also the code
+// of a value; use EncodeChecked to get an `ErrCapacity` error
+// instead.
+func (e *Ordinal32) Encode(s string) uint64 {
+	code, _ := e.EncodeChecked(s)
+	return code
+}
+
+// EncodeChecked will return the code of the given string,
+// assigning it the next code if it has none, or an
+// `ErrCapacity` error if the codes or values of the encoder
+// would no longer fit in 32 bits.
+func (e *Ordinal32) EncodeChecked(s string) (uint64, error) {
+	e.Lock()
+	defer e.Unlock()
+
+	return e.encode(s)
+}
+
+func (e *Ordinal32) encode(s string) (uint64, error) {
+	key := hashString(s)
+	if code, ok := e.encoder[key]; ok {
+		return uint64(code), nil
+	}
+
+	if uint64(len(e.ends)) >= math.MaxUint32 || uint64(len(e.data))+uint64(len(s)) > math.MaxUint32 {
+		return 0, ErrCapacity
+	}
+
+	code := uint32(len(e.ends))
+	e.data = append(e.data, s...)
+	e.ends = append(e.ends, uint32(len(e.data)))
+	e.encoder[key] = code
+
+	return uint64(code), nil
+}
+
+// EncodeSlice will encode all the values in the slice of strings
+// provided as an argument, or return an `ErrCapacity` error if
+// the encoder is full.
+func (e *Ordinal32) EncodeSlice(s []string) ([]uint64, error) {
+	e.Lock()
+	defer e.Unlock()
+
+	codes := make([]uint64, len(s), len(s))
+	for i, v := range s {
+		code, err := e.encode(v)
+		if err != nil {
+			return codes[:i], err
+		}
+		codes[i] = code
+	}
+
+	return codes, nil
+}
+
+// Decode will return an empty string if supplied integer
+// argument is not a valid code.
+func (e *Ordinal32) Decode(i uint64) string {
+	value, _ := e.DecodeChecked(i)
+	return value
+}
+
+// DecodeChecked will return the string for the given code,
+// or an `ErrBounds` error if the code is not a valid code.
+func (e *Ordinal32) DecodeChecked(i uint64) (string, error) {
+	e.RLock()
+	defer e.RUnlock()
+
+	if i >= uint64(len(e.ends)) {
+		return "", ErrBounds
+	}
+
+	return e.get(int(i)), nil
+}
+
+// get will return the string with code i.
+func (e *Ordinal32) get(i int) string {
+	var start uint32
+	if i > 0 {
+		start = e.ends[i-1]
+	}
+
+	return string(e.data[start:e.ends[i]])
+}
+
+// Contains will return whether or not a string
+// has been assigned an ordinal code or not.
+func (e *Ordinal32) Contains(s string) bool {
+	e.RLock()
+	defer e.RUnlock()
+
+	_, ok := e.encoder[hashString(s)]
+	return ok
+}
+
+// Length will return the number of encoded values.
+func (e *Ordinal32) Length() int {
+	e.RLock()
+	defer e.RUnlock()
+
+	return len(e.ends)
+}
+
+// List will return a copy of every encoded
+// value, indexed by code.
+func (e *Ordinal32) List() []string {
+	e.RLock()
+	defer e.RUnlock()
+
+	values := make([]string, len(e.ends), len(e.ends))
+	for code := range values {
+		values[code] = e.get(code)
+	}
+
+	return values
+}
+
+// SizeBytes will return an estimate of the
+// memory held by the encoder in bytes.
+func (e *Ordinal32) SizeBytes() int {
+	e.RLock()
+	defer e.RUnlock()
+
+	// entries hold a 32-bit rather than 64-bit code
+	return len(e.encoder)*(mapEntryBytes-4) + cap(e.data) + cap(e.ends)*4
+}
+
+// MarshalJSON will encode the values as an array
+// indexed by code, the format used by Ordinal.
+func (e *Ordinal32) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.List())
+}
+
+// UnmarshalJSON will replace the contents of the encoder
+// with the values of the array, in code order. Every
+// position keeps its code, as with Ordinal: a repeated
+// value encodes to its first code, and the codes of its
+// repeats decode to it. An `ErrCapacity` error is returned,
+// and the encoder left unchanged, if the values do not fit
+// in 32 bits.
+func (e *Ordinal32) UnmarshalJSON(data []byte) error {
+	s := make([]string, 0)
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+
+	var size uint64
+	for _, v := range s {
+		size += uint64(len(v))
+	}
+	if uint64(len(s)) > math.MaxUint32 || size > math.MaxUint32 {
+		return ErrCapacity
+	}
+
+	if e.RWMutex == nil {
+		e.RWMutex = &sync.RWMutex{}
+	}
+	e.Lock()
+	defer e.Unlock()
+
+	e.encoder = make(map[uint64]uint32, len(s))
+	e.data, e.ends = make([]byte, 0, size), make([]uint32, 0, len(s))
+	for code, v := range s {
+		e.data = append(e.data, v...)
+		e.ends = append(e.ends, uint32(len(e.data)))
+		if _, ok := e.encoder[hashString(v)]; !ok {
+			e.encoder[hashString(v)] = uint32(code)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package encoder
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOrdinal32(t *testing.T) {
+	e := NewOrdinal32(true)
+	codes, err := e.EncodeSlice([]string{"red", "green", "red", "blue"})
+	if err != nil {
+		t.Fatalf("encode error: %+v", err)
+	}
+	expected := []uint64{1, 2, 1, 3}
+	for i := range expected {
+		if codes[i] != expected[i] {
+			t.Errorf("codes were %v and not %v", codes, expected)
+		}
+	}
+	if e.Decode(2) != "green" || e.Decode(0) != "" || e.Length() != 4 || !e.Contains("blue") {
+		t.Error("unexpected encoder contents")
+	}
+	if _, err := e.DecodeChecked(4); err != ErrBounds {
+		t.Error("expected bounds error")
+	}
+
+	var _ OrdinalEncoder = e
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	var loaded Ordinal32
+	if err := json.Unmarshal(b, &loaded); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	if err := Compatible(e, &loaded); err != nil || loaded.Length() != 4 {
+		t.Error("encoder did not round trip")
+	}
+}
+
+func TestOrdinalCompact(t *testing.T) {
+	o := NewOrdinal(true)
+	o.EncodeSlice([]string{"red", "green", "blue"})
+
+	c, err := o.Compact()
+	if err != nil {
+		t.Fatalf("compact error: %+v", err)
+	}
+	if err := Compatible(o, c); err != nil || c.Length() != o.Length() {
+		t.Error("compact copy has different codes")
+	}
+	if c.Encode("yellow") != 4 || o.Contains("yellow") {
+		t.Error("compact copy is not independent")
+	}
+	if c.SizeBytes() >= o.SizeBytes()+len("yellow")+mapEntryBytes {
+		t.Error("compact copy is not smaller")
+	}
+}
+
+func TestOrdinal32RepeatedValues(t *testing.T) {
+	e := NewOrdinal32(false)
+	if err := json.Unmarshal([]byte(`["", "a", "", "b"]`), e); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	if e.Length() != 4 || e.Encode("b") != 3 || e.Encode("") != 0 {
+		t.Error("repeated values shifted codes")
+	}
+	if s, err := e.DecodeChecked(2); err != nil || s != "" {
+		t.Errorf("repeat position decoded to %q, %+v", s, err)
+	}
+}
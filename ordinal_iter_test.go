@@ -0,0 +1,25 @@
+//go:build go1.23
+// +build go1.23
+
+package encoder
+
+import (
+	"testing"
+)
+
+func TestOrdinalAll(t *testing.T) {
+	encoder := NewOrdinal(true)
+	encoder.Encode("red")
+
+	var codes []uint64
+	for code, value := range encoder.All() {
+		if encoder.Decode(code) != value {
+			t.Errorf("code %d did not decode to %q", code, value)
+		}
+		codes = append(codes, code)
+	}
+
+	if len(codes) != 2 {
+		t.Errorf("iterated codes were %v and not [0 1]", codes)
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// WriteJSON will write the encoder to `w` in the form written
+// by MarshalJSON, one value at a time, so the whole payload is
+// never held in memory.
+func (e *Ordinal) WriteJSON(w io.Writer) error {
+	e.RLock()
+	defer e.RUnlock()
+
+	o, object := e.jsonObject()
+	b := bufio.NewWriter(w)
+	if object {
+		_, err := b.WriteString(`{"values":`)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := b.WriteByte('[')
+	if err != nil {
+		return err
+	}
+
+	rows := newRowsIntegrity()
+	for code := 0; code < e.decoder.len(); code++ {
+		if code > 0 {
+			err = b.WriteByte(',')
+			if err != nil {
+				return err
+			}
+		}
+
+		value, err := json.Marshal(e.decoder.get(code))
+		if err != nil {
+			return err
+		}
+		_, err = b.Write(value)
+		if err != nil {
+			return err
+		}
+		rows.add(e.decoder.get(code))
+	}
+
+	err = b.WriteByte(']')
+	if err != nil {
+		return err
+	}
+
+	if object {
+		o.Integrity = rows.record().withTables(o.tables())
+		fields, err := json.Marshal(o)
+		if err != nil {
+			return err
+		}
+		// the values have been written in place of the
+		// first field, so write the fields that follow it
+		_, err = b.Write(bytes.TrimPrefix(fields, []byte(`{"values":null`)))
+		if err != nil {
+			return err
+		}
+	}
+
+	return b.Flush()
+}
+
+// ReadJSON will replace the contents of the encoder with
+// the encoder read from `r`, in either form written by
+// WriteJSON. Values are decoded one at a time, so the
+// payload is never held in memory. An `ErrFormat` error is
+// returned if the data is not in one of those forms, and
+// UnmarshalJSON's errors are returned for invalid
+// snapshots. The encoder is left unchanged if an error is
+// returned.
+func (e *Ordinal) ReadJSON(r io.Reader) error {
+	dec := json.NewDecoder(bufio.NewReader(r))
+
+	e.Lock()
+	defer e.Unlock()
+
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	var o ordinalJSON
+	decoder := newArena(nil)
+	rows := newRowsIntegrity()
+	switch token {
+	case json.Delim('['):
+		err = readJSONValues(dec, decoder, rows)
+	case json.Delim('{'):
+		err = readJSONObject(dec, &o, decoder, rows)
+	default:
+		err = ErrFormat
+	}
+	if err != nil {
+		return err
+	}
+
+	if o.Integrity != nil && !(rows.matches(o.Integrity) && o.Integrity.verifyTables(o.tables())) {
+		return ErrCorruptSnapshot
+	}
+	preprocess, err := lookupPreprocessors(o.Preprocessors)
+	if err != nil {
+		return err
+	}
+
+	e.loadJSON(&o, decoder, preprocess)
+	return nil
+}
+
+// readJSONValues will append the values of the array,
+// whose opening delimiter has been read, to the decoder.
+func readJSONValues(dec *json.Decoder, decoder *arena, rows *rowsIntegrity) error {
+	for dec.More() {
+		var value string
+		err := dec.Decode(&value)
+		if err != nil {
+			return err
+		}
+
+		decoder.append(value)
+		rows.add(value)
+	}
+
+	_, err := dec.Token()
+	return err
+}
+
+// readJSONObject will read the fields of the object form,
+// whose opening delimiter has been read, streaming its values
+// into the decoder.
+func readJSONObject(dec *json.Decoder, o *ordinalJSON, decoder *arena, rows *rowsIntegrity) error {
+	var values bool
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch token {
+		case "values":
+			token, err = dec.Token()
+			if err != nil {
+				return err
+			}
+			if token != json.Delim('[') {
+				return ErrFormat
+			}
+			err = readJSONValues(dec, decoder, rows)
+			values = true
+		case "aliases":
+			err = dec.Decode(&o.Aliases)
+		case "preprocessors":
+			err = dec.Decode(&o.Preprocessors)
+		case "reserved":
+			err = dec.Decode(&o.Reserved)
+		case "expired":
+			err = dec.Decode(&o.Expired)
+		case "changes":
+			err = dec.Decode(&o.Changes)
+		case "canonical":
+			err = dec.Decode(&o.Canonical)
+		case "integrity":
+			err = dec.Decode(&o.Integrity)
+		default:
+			// a map of values to codes
+			return ErrFormat
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if !values {
+		return ErrFormat
+	}
+
+	_, err := dec.Token()
+	return err
+}
@@ -1,6 +1,9 @@
 package encoder
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
 	"testing"
 )
 
@@ -104,3 +107,392 @@ func TestOrdinalGob(t *testing.T) {
 		t.Error("decoded value did not equal original value")
 	}
 }
+
+func TestOrdinalFindPrefix(t *testing.T) {
+	for _, encoder := range []*Ordinal{NewOrdinal(false), NewOrdinal(false, WithTrieIndex())} {
+		for _, v := range []string{"apple", "apricot", "banana", "grape"} {
+			encoder.Encode(v)
+		}
+
+		codes := encoder.FindPrefix("ap")
+		if len(codes) != 2 || codes[0] != 0 || codes[1] != 1 {
+			t.Errorf("prefix codes were %v and not [0 1]", codes)
+		}
+
+		codes = encoder.FindContains("ap")
+		if len(codes) != 3 || codes[2] != 3 {
+			t.Errorf("substring codes were %v and not [0 1 3]", codes)
+		}
+	}
+}
+
+func TestOrdinalList(t *testing.T) {
+	encoder := NewOrdinal(true)
+	values := []string{"", "red", "green", "blue"}
+	for _, v := range values {
+		encoder.Encode(v)
+	}
+
+	list := encoder.List()
+	if len(list) != len(values) {
+		t.Fatalf("list length was %d and not %d", len(list), len(values))
+	}
+
+	for i, v := range values {
+		if list[i] != v || encoder.Decode(uint64(i)) != v {
+			t.Errorf("code %d did not decode to %q", i, v)
+		}
+	}
+
+	list[1] = "purple"
+	if encoder.Decode(1) != "red" {
+		t.Error("modifying the list modified the encoder")
+	}
+}
+
+func TestOrdinalSliceInto(t *testing.T) {
+	encoder := NewOrdinal(false)
+	values := []string{"red", "green", "red"}
+
+	codes := make([]uint64, len(values))
+	err := encoder.EncodeSliceInto(codes, values)
+	if err != nil {
+		t.Fatalf("encode error: %+v", err)
+	}
+	if codes[0] != 0 || codes[1] != 1 || codes[2] != 0 {
+		t.Errorf("codes were %v and not [0 1 0]", codes)
+	}
+
+	decoded := make([]string, len(codes))
+	err = encoder.DecodeSliceInto(decoded, codes)
+	if err != nil {
+		t.Fatalf("decode error: %+v", err)
+	}
+	for i, v := range values {
+		if decoded[i] != v {
+			t.Errorf("decoded value %q did not equal original value %q", decoded[i], v)
+		}
+	}
+
+	if encoder.EncodeSliceInto(codes[:1], values) != ErrLength {
+		t.Error("expected length error")
+	}
+}
+
+func TestOrdinalEncodeSlice(t *testing.T) {
+	encoder := NewOrdinal(true)
+	codes := encoder.EncodeSlice([]string{"red", "green"})
+	if len(codes) != 2 || codes[1] != 2 {
+		t.Errorf("codes were %v and not [1 2]", codes)
+	}
+}
+
+func TestOrdinalDecodeChecked(t *testing.T) {
+	encoder := NewOrdinal(true)
+
+	value, err := encoder.DecodeChecked(0)
+	if err != nil || value != "" {
+		t.Errorf("code 0 decoded to %q with error %+v", value, err)
+	}
+
+	_, err = encoder.DecodeChecked(1)
+	if err != ErrBounds {
+		t.Error("expected bounds error")
+	}
+}
+
+func TestOrdinalRange(t *testing.T) {
+	encoder := NewOrdinal(true)
+	encoder.Encode("red")
+	encoder.Encode("green")
+
+	var values []string
+	encoder.Range(func(code uint64, value string) bool {
+		values = append(values, value)
+		return code < 1
+	})
+
+	if len(values) != 2 || values[1] != "red" {
+		t.Errorf("ranged values were %v and not [\"\" red]", values)
+	}
+}
+
+func TestOrdinalRangeReentrant(t *testing.T) {
+	encoder := NewOrdinal(true)
+	encoder.Encode("red")
+
+	var ranged int
+	encoder.Range(func(code uint64, value string) bool {
+		ranged++
+		encoder.Encode(value + "!")
+		return true
+	})
+
+	if ranged != 2 || encoder.Length() != 4 {
+		t.Errorf("ranged %d codes into %d values and not 2 into 4", ranged, encoder.Length())
+	}
+}
+
+func TestOrdinalOnNewCategory(t *testing.T) {
+	encoder := NewOrdinal(true)
+
+	var values []string
+	var codes []uint64
+	encoder.OnNewCategory(func(value string, code uint64) {
+		values = append(values, value)
+		codes = append(codes, code)
+	})
+
+	encoder.Encode("red")
+	encoder.Encode("red")
+	encoder.Encode("")
+
+	if len(values) != 1 || values[0] != "red" || codes[0] != 1 {
+		t.Errorf("callback received %v %v and not [red] [1]", values, codes)
+	}
+}
+
+func TestOrdinalStreamJSON(t *testing.T) {
+	encoder := NewOrdinal(true)
+	encoder.Encode("hello \"world\"")
+	encoder.Encode("goodbye")
+
+	var buf bytes.Buffer
+	err := encoder.WriteJSON(&buf)
+	if err != nil {
+		t.Fatalf("write json error: %+v", err)
+	}
+
+	data, _ := encoder.MarshalJSON()
+	if buf.String() != string(data) {
+		t.Errorf("streamed json %s did not match marshaled json %s", buf.String(), data)
+	}
+
+	newEncoder := NewOrdinal(false)
+	err = newEncoder.ReadJSON(&buf)
+	if err != nil {
+		t.Fatalf("read json error: %+v", err)
+	}
+
+	if newEncoder.Decode(1) != "hello \"world\"" || newEncoder.Encode("goodbye") != 2 {
+		t.Error("decoded values did not equal original values")
+	}
+
+	err = newEncoder.ReadJSON(bytes.NewReader([]byte(`{"a": 1}`)))
+	if err != ErrFormat {
+		t.Error("expected format error")
+	}
+}
+
+func TestOrdinalStreamJSONTables(t *testing.T) {
+	encoder := NewOrdinal(false)
+	encoder.Encode("red")
+	encoder.Alias("red", "rouge")
+	encoder.Reserve(2, 4)
+
+	var buf bytes.Buffer
+	err := encoder.WriteJSON(&buf)
+	if err != nil {
+		t.Fatalf("write json error: %+v", err)
+	}
+
+	data, _ := encoder.MarshalJSON()
+	if buf.String() != string(data) {
+		t.Errorf("streamed json %s did not match marshaled json %s", buf.String(), data)
+	}
+
+	newEncoder := NewOrdinal(false)
+	err = newEncoder.ReadJSON(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("read json error: %+v", err)
+	}
+	if newEncoder.Encode("rouge") != 0 || newEncoder.Encode("blue") != 4 {
+		t.Error("aliases or reserved codes were not read")
+	}
+
+	truncated := NewOrdinal(false)
+	truncated.Encode("green")
+	err = truncated.ReadJSON(bytes.NewReader(buf.Bytes()[:buf.Len()-2]))
+	if err == nil {
+		t.Fatal("expected an error for a truncated stream")
+	}
+	if truncated.Length() != 1 || truncated.Decode(0) != "green" {
+		t.Error("a failed read changed the encoder")
+	}
+
+	corrupt := bytes.Replace(buf.Bytes(), []byte(`"rouge"`), []byte(`"rojo"`), 1)
+	err = NewOrdinal(false).ReadJSON(bytes.NewReader(corrupt))
+	if err != ErrCorruptSnapshot {
+		t.Errorf("error was %+v and not a corrupt snapshot error", err)
+	}
+}
+
+func TestOrdinalGobCorrupt(t *testing.T) {
+	encoder := NewOrdinal(false)
+	encoder.Encode("hello world")
+	data, err := encoder.GobEncode()
+	if err != nil {
+		t.Fatalf("gob encode error: %+v", err)
+	}
+
+	corrupt := bytes.Replace(data, []byte("hello world"), []byte("hello w0rld"), 1)
+	err = NewOrdinal(false).GobDecode(corrupt)
+	if err != ErrCorruptSnapshot {
+		t.Errorf("error was %+v and not a corrupt snapshot error", err)
+	}
+}
+
+func TestOrdinalSnapshotTables(t *testing.T) {
+	encoder := NewOrdinal(false)
+	encoder.EncodeSlice([]string{"hello", "world"})
+	encoder.Alias("hello", "hi")
+	data, err := encoder.GobEncode()
+	if err != nil {
+		t.Fatalf("gob encode error: %+v", err)
+	}
+
+	var g ordinalGob
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&g); err != nil {
+		t.Fatalf("gob decode error: %+v", err)
+	}
+	g.Encoder[hashString("hello")] = 1
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(g); err != nil {
+		t.Fatalf("gob encode error: %+v", err)
+	}
+	loaded := NewOrdinal(false)
+	if err := loaded.GobDecode(buf.Bytes()); err != nil {
+		t.Fatalf("gob decode error: %+v", err)
+	}
+	if loaded.Encode("hello") != 0 || loaded.Encode("hi") != 0 {
+		t.Error("code table was read from the snapshot")
+	}
+
+	g.Aliases["hi"] = "world"
+	buf.Reset()
+	if err := gob.NewEncoder(&buf).Encode(g); err != nil {
+		t.Fatalf("gob encode error: %+v", err)
+	}
+	if err := NewOrdinal(false).GobDecode(buf.Bytes()); err != ErrCorruptSnapshot {
+		t.Errorf("expected corrupt snapshot error for a modified alias, got %v", err)
+	}
+
+	data, err = json.Marshal(encoder)
+	if err != nil {
+		t.Fatalf("json marshal error: %+v", err)
+	}
+	corrupt := bytes.Replace(data, []byte(`"hi":"hello"`), []byte(`"hi":"world"`), 1)
+	if err := json.Unmarshal(corrupt, NewOrdinal(false)); err != ErrCorruptSnapshot {
+		t.Errorf("expected corrupt snapshot error for a modified alias, got %v", err)
+	}
+}
+
+func TestNewOrdinalFromMap(t *testing.T) {
+	encoder, err := NewOrdinalFromMap(map[string]uint64{"red": 1, "green": 0, "blue": 2})
+	if err != nil {
+		t.Fatalf("from map error: %+v", err)
+	}
+
+	if encoder.Decode(1) != "red" || encoder.Encode("blue") != 2 || encoder.Encode("purple") != 3 {
+		t.Error("encoder did not use the codes from the map")
+	}
+
+	_, err = NewOrdinalFromMap(map[string]uint64{"red": 0, "green": 0})
+	if err != ErrCodeTaken {
+		t.Error("expected code taken error")
+	}
+
+	_, err = NewOrdinalFromMap(map[string]uint64{"red": 0, "green": 2})
+	if err != ErrNotDense {
+		t.Error("expected not dense error")
+	}
+
+	missing, err := NewOrdinalFromMap(map[string]uint64{"red": 1, "green": 2}, WithMissing(DefaultMissing))
+	if err != nil {
+		t.Fatalf("new ordinal from map error: %+v", err)
+	}
+	if missing.Encode("") != MissingCode || missing.Encode("red") != 1 || missing.Encode("blue") != 3 {
+		t.Error("encoder did not keep the missing code")
+	}
+
+	_, err = NewOrdinalFromMap(map[string]uint64{"red": 0, "green": 1}, WithMissing(DefaultMissing))
+	if err != ErrCodeTaken {
+		t.Error("expected code taken error for the missing code")
+	}
+}
+
+func TestOrdinalUnmarshalJSONMap(t *testing.T) {
+	encoder := NewOrdinal(false)
+	err := encoder.UnmarshalJSON([]byte(`{"red": 0, "blue": 2, "values": 1}`))
+	if err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	if encoder.Decode(2) != "blue" || encoder.Decode(1) != "values" || encoder.Encode("red") != 0 {
+		t.Error("map did not load with its codes")
+	}
+
+	err = encoder.UnmarshalJSON([]byte(`{"red": 0, "blue": 0}`))
+	if err != ErrCodeTaken {
+		t.Error("expected code taken error")
+	}
+
+	err = encoder.UnmarshalJSON([]byte(`{"red": 0, "blue": 4000000000}`))
+	if err != ErrNotDense {
+		t.Errorf("error was %+v and not a not dense error", err)
+	}
+}
+
+func TestNewOrdinalFrom(t *testing.T) {
+	existing := NewOrdinal(true)
+	existing.EncodeSlice([]string{"red", "green", "blue"})
+	existing.Alias("red", "crimson")
+
+	e := NewOrdinalFrom(existing, WithCounts())
+	for _, v := range existing.List() {
+		if e.Encode(v) != existing.Encode(v) {
+			t.Errorf("%q was assigned a new code", v)
+		}
+	}
+	if e.Encode("crimson") != existing.Encode("red") {
+		t.Error("expected aliases to be copied")
+	}
+
+	code := e.Encode("violet")
+	if code != uint64(existing.Length()) {
+		t.Errorf("new value received code %d and not %d", code, existing.Length())
+	}
+	if existing.Contains("violet") {
+		t.Error("expected existing encoder to be unchanged")
+	}
+	if e.Count("violet") != 1 {
+		t.Error("expected options to apply to the new encoder")
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, ErrFormat
+}
+
+func TestOrdinalEncodeChecked(t *testing.T) {
+	encoder := NewOrdinal(true)
+	code, err := encoder.EncodeChecked("red")
+	if err != nil || code != 1 {
+		t.Errorf("code was %d and not 1: %v", code, err)
+	}
+
+	encoder.SetWAL(failingWriter{})
+	code, err = encoder.EncodeChecked("green")
+	if err != ErrFormat || code != 2 {
+		t.Errorf("expected write error with code 2, got %d: %v", code, err)
+	}
+
+	codes, err := encoder.EncodeSliceChecked([]string{"red", "blue"})
+	if err != ErrFormat || len(codes) != 2 || codes[0] != 1 || codes[1] != 3 {
+		t.Errorf("unexpected codes %v: %v", codes, err)
+	}
+	if codes, err := encoder.EncodeSliceChecked([]string{"red", "blue"}); err != nil {
+		t.Errorf("unexpected error for encoded values %v: %v", codes, err)
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+)
+
+// PCA projects encoded numeric rows onto the principal
+// components of the rows it was fitted to, optionally
+// whitening them so every component has unit variance.
+type PCA struct {
+	mean       []float64
+	components [][]float64
+	variances  []float64
+	whiten     bool
+}
+
+// NewPCA will fit a PCA to the rows, keeping the `components`
+// directions of highest variance, or every direction if
+// `components` is 0. If `whiten` is true, transformed rows are
+// scaled so every component has unit variance. An `ErrNoData`
+// error is returned if there are no rows, an `ErrLength` error
+// if the rows are not all the same length, and an `ErrBounds`
+// error if there are more components than columns.
+func NewPCA(rows [][]float64, components int, whiten bool) (*PCA, error) {
+	if len(rows) == 0 || len(rows[0]) == 0 {
+		return &PCA{}, ErrNoData
+	}
+	d := len(rows[0])
+	if components < 0 || components > d {
+		return &PCA{}, ErrBounds
+	}
+	if components == 0 {
+		components = d
+	}
+
+	mean := make([]float64, d, d)
+	for _, row := range rows {
+		if len(row) != d {
+			return &PCA{}, ErrLength
+		}
+		for j, v := range row {
+			mean[j] += v
+		}
+	}
+	for j := range mean {
+		mean[j] /= float64(len(rows))
+	}
+
+	n := float64(len(rows) - 1)
+	if n == 0 {
+		n = 1
+	}
+	covariance := make([][]float64, d, d)
+	for i := range covariance {
+		covariance[i] = make([]float64, d, d)
+	}
+	for _, row := range rows {
+		for i := 0; i < d; i++ {
+			for j := i; j < d; j++ {
+				covariance[i][j] += (row[i] - mean[i]) * (row[j] - mean[j]) / n
+			}
+		}
+	}
+	for i := 0; i < d; i++ {
+		for j := 0; j < i; j++ {
+			covariance[i][j] = covariance[j][i]
+		}
+	}
+
+	values, vectors := symmetricEigen(covariance)
+
+	p := &PCA{
+		mean:       mean,
+		components: make([][]float64, components, components),
+		variances:  make([]float64, components, components),
+		whiten:     whiten,
+	}
+	for k := 0; k < components; k++ {
+		p.variances[k] = math.Max(values[k], 0)
+		p.components[k] = vectors[k]
+	}
+
+	return p, nil
+}
+
+// symmetricEigen will return the eigenvalues of the symmetric
+// matrix in descending order with their unit eigenvectors,
+// using the cyclic Jacobi method. The matrix is overwritten.
+// The sign of every eigenvector is chosen so that its element
+// of largest magnitude is positive.
+func symmetricEigen(a [][]float64) ([]float64, [][]float64) {
+	d := len(a)
+	v := make([][]float64, d, d)
+	for i := range v {
+		v[i] = make([]float64, d, d)
+		v[i][i] = 1
+	}
+
+	for sweep := 0; sweep < 100; sweep++ {
+		var off float64
+		for i := 0; i < d; i++ {
+			for j := i + 1; j < d; j++ {
+				off += a[i][j] * a[i][j]
+			}
+		}
+		if off < 1e-22 {
+			break
+		}
+
+		for p := 0; p < d; p++ {
+			for q := p + 1; q < d; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+
+				for k := 0; k < d; k++ {
+					akp, akq := a[k][p], a[k][q]
+					a[k][p] = c*akp - s*akq
+					a[k][q] = s*akp + c*akq
+				}
+				for k := 0; k < d; k++ {
+					apk, aqk := a[p][k], a[q][k]
+					a[p][k] = c*apk - s*aqk
+					a[q][k] = s*apk + c*aqk
+				}
+				for k := 0; k < d; k++ {
+					vkp, vkq := v[k][p], v[k][q]
+					v[k][p] = c*vkp - s*vkq
+					v[k][q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+
+	order := make([]int, d, d)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return a[order[i]][order[i]] > a[order[j]][order[j]]
+	})
+
+	values := make([]float64, d, d)
+	vectors := make([][]float64, d, d)
+	for k, i := range order {
+		values[k] = a[i][i]
+
+		vector := make([]float64, d, d)
+		var largest float64
+		for j := 0; j < d; j++ {
+			vector[j] = v[j][i]
+			if math.Abs(vector[j]) > math.Abs(largest) {
+				largest = vector[j]
+			}
+		}
+		if largest < 0 {
+			for j := range vector {
+				vector[j] = -vector[j]
+			}
+		}
+		vectors[k] = vector
+	}
+
+	return values, vectors
+}
+
+// Dimension will return the number of components.
+func (p *PCA) Dimension() int {
+	return len(p.components)
+}
+
+// ExplainedVariance will return the variance of the
+// fitted rows along every component, in order.
+func (p *PCA) ExplainedVariance() []float64 {
+	return append([]float64{}, p.variances...)
+}
+
+// Transform will return the row projected onto the components.
+// An `ErrLength` error is returned if the row does not have the
+// length of the fitted rows.
+func (p *PCA) Transform(row []float64) ([]float64, error) {
+	if len(row) != len(p.mean) {
+		return []float64{}, ErrLength
+	}
+
+	projected := make([]float64, len(p.components), len(p.components))
+	for k, component := range p.components {
+		for j, v := range row {
+			projected[k] += (v - p.mean[j]) * component[j]
+		}
+		if p.whiten && p.variances[k] > 0 {
+			projected[k] /= math.Sqrt(p.variances[k])
+		}
+	}
+
+	return projected, nil
+}
+
+// Inverse will map a projected row back to the space of the
+// fitted rows. An `ErrLength` error is returned if the row
+// does not have one element per component.
+func (p *PCA) Inverse(projected []float64) ([]float64, error) {
+	if len(projected) != len(p.components) {
+		return []float64{}, ErrLength
+	}
+
+	row := append([]float64{}, p.mean...)
+	for k, component := range p.components {
+		z := projected[k]
+		if p.whiten {
+			z *= math.Sqrt(p.variances[k])
+		}
+		for j, c := range component {
+			row[j] += z * c
+		}
+	}
+
+	return row, nil
+}
+
+type pcaJSON struct {
+	Mean       []float64   `json:"mean"`
+	Components [][]float64 `json:"components"`
+	Variances  []float64   `json:"variances"`
+	Whiten     bool        `json:"whiten,omitempty"`
+}
+
+// MarshalJSON ...
+func (p *PCA) MarshalJSON() ([]byte, error) {
+	return json.Marshal(pcaJSON{
+		Mean:       p.mean,
+		Components: p.components,
+		Variances:  p.variances,
+		Whiten:     p.whiten,
+	})
+}
+
+// UnmarshalJSON will return an `ErrLength` error if the
+// components and variances do not match the mean.
+func (p *PCA) UnmarshalJSON(data []byte) error {
+	var fitted pcaJSON
+	err := json.Unmarshal(data, &fitted)
+	if err != nil {
+		return err
+	}
+	if len(fitted.Variances) != len(fitted.Components) {
+		return ErrLength
+	}
+	for _, component := range fitted.Components {
+		if len(component) != len(fitted.Mean) {
+			return ErrLength
+		}
+	}
+
+	p.mean = fitted.Mean
+	p.components = fitted.Components
+	p.variances = fitted.Variances
+	p.whiten = fitted.Whiten
+	return nil
+}
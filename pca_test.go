@@ -0,0 +1,68 @@
+package encoder
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestPCA(t *testing.T) {
+	// points along the line y = 2x with a little noise
+	rows := [][]float64{
+		{1, 2.1}, {2, 3.9}, {3, 6.05}, {4, 8}, {5, 9.95},
+	}
+
+	p, err := NewPCA(rows, 1, false)
+	if err != nil {
+		t.Fatalf("fit error: %+v", err)
+	}
+	c := p.components[0]
+	if math.Abs(c[1]/c[0]-2) > 0.05 {
+		t.Errorf("first component %v is not along y = 2x", c)
+	}
+
+	full, _ := NewPCA(rows, 0, false)
+	for _, row := range rows {
+		z, _ := full.Transform(row)
+		back, _ := full.Inverse(z)
+		for j := range row {
+			if math.Abs(back[j]-row[j]) > 1e-9 {
+				t.Fatalf("inverse of %v was %v", row, back)
+			}
+		}
+	}
+
+	white, _ := NewPCA(rows, 0, true)
+	var sum [2]float64
+	for _, row := range rows {
+		z, _ := white.Transform(row)
+		sum[0] += z[0] * z[0]
+		sum[1] += z[1] * z[1]
+	}
+	for k := range sum {
+		if v := sum[k] / float64(len(rows)-1); math.Abs(v-1) > 1e-9 {
+			t.Errorf("whitened variance of component %d was %f", k, v)
+		}
+	}
+
+	b, err := json.Marshal(white)
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	var loaded PCA
+	if err := json.Unmarshal(b, &loaded); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	z1, _ := white.Transform(rows[2])
+	z2, _ := loaded.Transform(rows[2])
+	if z1[0] != z2[0] || z1[1] != z2[1] {
+		t.Error("components did not round trip")
+	}
+
+	if _, err := NewPCA([][]float64{{1, 2}, {1}}, 0, false); err != ErrLength {
+		t.Error("expected length error")
+	}
+	if _, err := NewPCA(rows, 3, false); err != ErrBounds {
+		t.Error("expected bounds error")
+	}
+}
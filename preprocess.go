@@ -0,0 +1,124 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+var (
+	preprocessors = map[string]Normalizer{
+		"lowercase":         Lowercase,
+		"trim_space":        TrimSpace,
+		"strip_punctuation": StripPunctuation,
+		// TextVectorization standardizations
+		"keras_lowercase":         KerasLowercase,
+		"keras_strip_punctuation": KerasStripPunctuation,
+	}
+	preprocessorsMu = &sync.RWMutex{}
+)
+
+// RegisterPreprocessor will register a preprocessing function
+// under the given name, replacing any function registered under
+// the same name. Encoders store the names of their preprocessors
+// when they are serialized, so the same functions must be
+// registered in every program that loads them.
+func RegisterPreprocessor(name string, f Normalizer) {
+	preprocessorsMu.Lock()
+	defer preprocessorsMu.Unlock()
+
+	preprocessors[name] = f
+}
+
+// StripPunctuation will remove every punctuation character.
+func StripPunctuation(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsPunct(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// SetPreprocessors will apply the registered preprocessors with
+// the given names, in order, to every value before it is encoded
+// or looked up, replacing any previous preprocessors. Values that
+// are already encoded are not rewritten, so preprocessors should
+// be set before encoding. An `ErrPreprocessor` error is returned
+// if a name is not registered.
+func (e *Ordinal) SetPreprocessors(names ...string) error {
+	funcs, err := lookupPreprocessors(names)
+	if err != nil {
+		return err
+	}
+
+	e.lock()
+	defer e.Unlock()
+
+	e.preprocessNames = names
+	e.preprocess = funcs
+	return nil
+}
+
+// Preprocessors will return the names of
+// the encoder's preprocessors, in order.
+func (e *Ordinal) Preprocessors() []string {
+	e.RLock()
+	defer e.RUnlock()
+
+	return append([]string{}, e.preprocessNames...)
+}
+
+// SetPreprocessors will apply the registered preprocessors with
+// the given names, in order, to every value before it is encoded
+// or looked up, replacing any previous preprocessors. Values that
+// are already encoded are not rewritten, so preprocessors should
+// be set before encoding. An `ErrPreprocessor` error is returned
+// if a name is not registered.
+func (e *OneHot) SetPreprocessors(names ...string) error {
+	funcs, err := lookupPreprocessors(names)
+	if err != nil {
+		return err
+	}
+
+	e.preprocessNames = names
+	e.preprocess = funcs
+	return nil
+}
+
+// Preprocessors will return the names of
+// the encoder's preprocessors, in order.
+func (e *OneHot) Preprocessors() []string {
+	return append([]string{}, e.preprocessNames...)
+}
+
+// lookupPreprocessors will return the
+// registered functions with the given names.
+func lookupPreprocessors(names []string) ([]Normalizer, error) {
+	preprocessorsMu.RLock()
+	defer preprocessorsMu.RUnlock()
+
+	funcs := make([]Normalizer, len(names), len(names))
+	for i, name := range names {
+		f, ok := preprocessors[name]
+		if !ok {
+			return nil, ErrPreprocessor
+		}
+		funcs[i] = f
+	}
+
+	return funcs, nil
+}
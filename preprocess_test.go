@@ -0,0 +1,52 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestOrdinalPreprocessors(t *testing.T) {
+	RegisterPreprocessor("test_strip_ids", func(s string) string {
+		return strings.TrimRight(s, "0123456789")
+	})
+
+	encoder := NewOrdinal(false)
+	if err := encoder.SetPreprocessors("missing"); err != ErrPreprocessor {
+		t.Error("expected preprocessor error")
+	}
+	if err := encoder.SetPreprocessors("strip_punctuation", "test_strip_ids", "lowercase"); err != nil {
+		t.Fatalf("set preprocessors error: %+v", err)
+	}
+
+	code := encoder.Encode("Host-42")
+	if encoder.Encode("host17") != code || encoder.Decode(code) != "host" {
+		t.Error("preprocessors were not applied")
+	}
+
+	b, err := json.Marshal(encoder)
+	if err != nil {
+		t.Fatalf("json marshal error: %+v", err)
+	}
+	fromJSON := NewOrdinal(false)
+	if err := json.Unmarshal(b, fromJSON); err != nil {
+		t.Fatalf("json unmarshal error: %+v", err)
+	}
+	if fromJSON.Encode("HOST.9") != code || len(fromJSON.Preprocessors()) != 3 {
+		t.Error("json did not restore preprocessors")
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(encoder); err != nil {
+		t.Fatalf("gob encode error: %+v", err)
+	}
+	fromGob := NewOrdinal(false)
+	if err := gob.NewDecoder(&buf).Decode(fromGob); err != nil {
+		t.Fatalf("gob decode error: %+v", err)
+	}
+	if !fromGob.Snapshot().Contains("host-1") {
+		t.Error("gob did not restore preprocessors")
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"math"
+)
+
+// ProbabilityRatio is a one way encoder.
+// You cannot decode ProbabilityRatio values
+// as some values may be encoded with the same
+// numerical code.
+// ProbabilityRatio is a target-based encoder for
+// binary targets that encodes every category with
+// the smoothed ratio P(y=1|category) / P(y=0|category).
+type ProbabilityRatio struct {
+	encoder   map[string]float64
+	smoothing float64
+	missing   MissingPolicy
+}
+
+// LogOdds is a one way encoder.
+// You cannot decode LogOdds values
+// as some values may be encoded with the same
+// numerical code.
+// LogOdds is a target-based encoder for binary
+// targets that encodes every category with the
+// natural log of its smoothed probability ratio.
+type LogOdds struct {
+	encoder   map[string]float64
+	smoothing float64
+	missing   MissingPolicy
+	// overall is the log-odds of the whole target,
+	// with twice the smoothing of a category.
+	overall float64
+}
+
+// NewProbabilityRatio will create a ProbabilityRatio encoder.
+// The `smoothing` value is added to both the positive and
+// negative counts of every category (additive smoothing) so
+// that categories without negative observations still
+// receive a finite code.
+func NewProbabilityRatio(values []string, target []bool, smoothing float64, opts ...TargetOption) (*ProbabilityRatio, error) {
+	if len(target) != len(values) {
+		return &ProbabilityRatio{}, ErrTargetLength
+	}
+
+	return newProbabilityRatio(values, target, nil, smoothing, newTargetOptions(opts)), nil
+}
+
+// NewProbabilityRatioWeighted will create a ProbabilityRatio
+// encoder where every observation counts with its weight,
+// e.g. to undo importance sampling or rebalance classes.
+// Categories without weight are not encoded.
+// An `ErrWeightLength` error is returned if the weights are
+// not the same length as the values, and an `ErrWeight`
+// error if a weight is negative or not finite.
+func NewProbabilityRatioWeighted(values []string, target []bool, weights []float64, smoothing float64, opts ...TargetOption) (*ProbabilityRatio, error) {
+	if len(target) != len(values) {
+		return &ProbabilityRatio{}, ErrTargetLength
+	}
+	err := checkWeights(values, weights)
+	if err != nil {
+		return &ProbabilityRatio{}, err
+	}
+
+	return newProbabilityRatio(values, target, weights, smoothing, newTargetOptions(opts)), nil
+}
+
+func newProbabilityRatio(values []string, target []bool, weights []float64, smoothing float64, o targetOptions) *ProbabilityRatio {
+	counts, positives := weightBinaryTarget(o.categories(values), target, weights)
+
+	encoder := make(map[string]float64)
+	for k, n := range counts {
+		// categories without weight were not observed
+		if n > 0 {
+			encoder[k] = probabilityRatio(positives[k], n, smoothing)
+		}
+	}
+
+	return &ProbabilityRatio{
+		encoder:   encoder,
+		smoothing: smoothing,
+		missing:   o.missing,
+	}
+}
+
+// NewLogOdds will create a LogOdds encoder.
+// The `smoothing` value is applied in the same
+// way as for the ProbabilityRatio encoder.
+func NewLogOdds(values []string, target []bool, smoothing float64, opts ...TargetOption) (*LogOdds, error) {
+	if len(target) != len(values) {
+		return &LogOdds{}, ErrTargetLength
+	}
+
+	return newLogOdds(values, target, nil, smoothing, newTargetOptions(opts)), nil
+}
+
+// NewLogOddsWeighted will create a LogOdds encoder where
+// every observation counts with its weight. Categories
+// without weight are not encoded. An `ErrWeightLength`
+// error is returned if the weights are not the same length
+// as the values, and an `ErrWeight` error if a weight is
+// negative or not finite.
+func NewLogOddsWeighted(values []string, target []bool, weights []float64, smoothing float64, opts ...TargetOption) (*LogOdds, error) {
+	if len(target) != len(values) {
+		return &LogOdds{}, ErrTargetLength
+	}
+	err := checkWeights(values, weights)
+	if err != nil {
+		return &LogOdds{}, err
+	}
+
+	return newLogOdds(values, target, weights, smoothing, newTargetOptions(opts)), nil
+}
+
+func newLogOdds(values []string, target []bool, weights []float64, smoothing float64, o targetOptions) *LogOdds {
+	counts, positives := weightBinaryTarget(o.categories(values), target, weights)
+
+	var total, totalPositives float64
+	encoder := make(map[string]float64)
+	for k, n := range counts {
+		if n > 0 {
+			encoder[k] = math.Log(probabilityRatio(positives[k], n, smoothing))
+			total += n
+			totalPositives += positives[k]
+		}
+	}
+
+	return &LogOdds{
+		encoder:   encoder,
+		smoothing: smoothing,
+		missing:   o.missing,
+		overall:   math.Log(probabilityRatio(totalPositives, total, 2*smoothing)),
+	}
+}
+
+// Get will retrieve the code for the given categorical value.
+func (e *ProbabilityRatio) Get(s string) (float64, bool) {
+	v, ok := e.encoder[missingKey(e.missing, s)]
+	return v, ok
+}
+
+// Smoothing will return the smoothing value used
+// when creating the ProbabilityRatio encoder.
+func (e *ProbabilityRatio) Smoothing() float64 {
+	return e.smoothing
+}
+
+// Get will retrieve the code for the given categorical value.
+func (e *LogOdds) Get(s string) (float64, bool) {
+	v, ok := e.encoder[missingKey(e.missing, s)]
+	return v, ok
+}
+
+// Smoothing will return the smoothing value used
+// when creating the LogOdds encoder.
+func (e *LogOdds) Smoothing() float64 {
+	return e.smoothing
+}
+
+// probabilityRatio will return (positives + smoothing) / (negatives + smoothing).
+// A category with no negatives and no smoothing will be +Inf.
+func probabilityRatio(positives, count, smoothing float64) float64 {
+	negatives := count - positives
+	return (positives + smoothing) / (negatives + smoothing)
+}
+
+// weightBinaryTarget will return the total weight of the
+// observations and of the positive observations for every
+// category. Without weights every observation weighs 1.
+func weightBinaryTarget(values []string, target []bool, weights []float64) (counts, positives map[string]float64) {
+	counts = make(map[string]float64)
+	positives = make(map[string]float64)
+	for i := 0; i < len(values); i++ {
+		w := weight(weights, i)
+		counts[values[i]] += w
+		if target[i] {
+			positives[values[i]] += w
+		}
+	}
+
+	return
+}
+
+// weight will return the weight of the i-th
+// observation, which is 1 without weights.
+func weight(weights []float64, i int) float64 {
+	if weights == nil {
+		return 1
+	}
+
+	return weights[i]
+}
+
+// checkWeights will return an `ErrWeightLength` error if
+// there is not a weight for every value, and an `ErrWeight`
+// error if a weight is negative or not finite.
+func checkWeights(values []string, weights []float64) error {
+	if len(weights) != len(values) {
+		return ErrWeightLength
+	}
+
+	for _, w := range weights {
+		if w < 0 || math.IsInf(w, 0) || math.IsNaN(w) {
+			return ErrWeight
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package encoder
+
+import (
+	"math"
+	"testing"
+)
+
+func TestProbabilityRatio(t *testing.T) {
+	values := []string{"a", "a", "a", "b", "b"}
+	target := []bool{true, true, false, false, false}
+
+	encoder, err := NewProbabilityRatio(values, target, 1)
+	if err != nil {
+		t.Fatalf("probability ratio error: %+v", err)
+	}
+
+	if a, _ := encoder.Get("a"); a != 1.5 {
+		t.Errorf("code was %f and not 1.5", a)
+	}
+
+	logOdds, err := NewLogOdds(values, target, 1)
+	if err != nil {
+		t.Fatalf("log odds error: %+v", err)
+	}
+
+	if b, _ := logOdds.Get("b"); b != math.Log(1.0/3.0) {
+		t.Errorf("code was %f and not log(1/3)", b)
+	}
+
+	_, err = NewLogOdds(values, target[1:], 1)
+	if err != ErrTargetLength {
+		t.Error("expected target length error")
+	}
+}
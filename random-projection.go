@@ -0,0 +1,138 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// RandomProjection maps one-hot and multi-hot codewords of
+// very high-cardinality columns down to a fixed low dimension
+// with a sparse random matrix (Achlioptas; Li, Hastie and
+// Church), which approximately preserves distances between
+// codewords by the Johnson-Lindenstrauss lemma.
+//
+// The elements of the matrix are derived from the seed as they
+// are needed rather than stored, so the projection takes no
+// memory, is the same in every process created with the same
+// seed and accepts codewords of any dimension.
+type RandomProjection struct {
+	input  int
+	output int
+	seed   uint64
+}
+
+// NewRandomProjection will create a projection of codewords with
+// `input` dimensions down to `output` dimensions. Each element of
+// the matrix is non-zero with probability `1/sqrt(input)`.
+// An `ErrCapacity` error is returned if either dimension is not
+// positive.
+func NewRandomProjection(input, output int, seed uint64) (*RandomProjection, error) {
+	if input < 1 || output < 1 {
+		return &RandomProjection{}, ErrCapacity
+	}
+
+	return &RandomProjection{
+		input:  input,
+		output: output,
+		seed:   seed,
+	}, nil
+}
+
+// Dimension will return the dimension of projected codewords.
+func (p *RandomProjection) Dimension() int {
+	return p.output
+}
+
+// Project will return the projection of the codeword.
+func (p *RandomProjection) Project(codeword []uint8) []float64 {
+	projected := make([]float64, p.output, p.output)
+	for i, v := range codeword {
+		if v != 0 {
+			p.add(projected, i, float64(v))
+		}
+	}
+
+	return projected
+}
+
+// ProjectIndices will return the projection of the multi-hot
+// codeword whose hot dimensions are given, such as the column
+// indices of a row of a CSR matrix.
+func (p *RandomProjection) ProjectIndices(hot []int) []float64 {
+	projected := make([]float64, p.output, p.output)
+	for _, i := range hot {
+		p.add(projected, i, 1)
+	}
+
+	return projected
+}
+
+// add will add column `i` of the matrix, scaled by `v`,
+// to the projected codeword.
+func (p *RandomProjection) add(projected []float64, i int, v float64) {
+	s := math.Sqrt(float64(p.input))
+	scale := math.Sqrt(s/float64(p.output)) * v
+
+	for j := range projected {
+		// uniform in [0, 1) from the top 53 bits
+		u := float64(splitmix64(p.seed^uint64(i)*0x9e3779b97f4a7c15^uint64(j)*0xc2b2ae3d27d4eb4f)>>11) / (1 << 53)
+		switch {
+		case u < 0.5/s:
+			projected[j] -= scale
+		case u < 1/s:
+			projected[j] += scale
+		}
+	}
+}
+
+// splitmix64 will return a well mixed 64-bit value of x.
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+type randomProjectionJSON struct {
+	Input  int    `json:"input"`
+	Output int    `json:"output"`
+	Seed   uint64 `json:"seed"`
+}
+
+// MarshalJSON ...
+func (p *RandomProjection) MarshalJSON() ([]byte, error) {
+	return json.Marshal(randomProjectionJSON{
+		Input:  p.input,
+		Output: p.output,
+		Seed:   p.seed,
+	})
+}
+
+// UnmarshalJSON will return an `ErrCapacity` error
+// if either dimension is not positive.
+func (p *RandomProjection) UnmarshalJSON(data []byte) error {
+	var c randomProjectionJSON
+	err := json.Unmarshal(data, &c)
+	if err != nil {
+		return err
+	}
+	if c.Input < 1 || c.Output < 1 {
+		return ErrCapacity
+	}
+
+	p.input, p.output, p.seed = c.Input, c.Output, c.Seed
+	return nil
+}
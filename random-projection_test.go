@@ -0,0 +1,57 @@
+package encoder
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestRandomProjection(t *testing.T) {
+	p, err := NewRandomProjection(10000, 256, 42)
+	if err != nil {
+		t.Fatalf("create error: %+v", err)
+	}
+
+	codeword := make([]uint8, 10000, 10000)
+	codeword[7], codeword[9000] = 1, 1
+	projected := p.Project(codeword)
+	if len(projected) != p.Dimension() {
+		t.Fatalf("projection has %d dimensions and not %d", len(projected), p.Dimension())
+	}
+	indices := p.ProjectIndices([]int{7, 9000})
+	for j := range projected {
+		if projected[j] != indices[j] {
+			t.Fatal("dense and index projections differ")
+		}
+	}
+
+	// squared norms are preserved in expectation
+	var norm float64
+	for i := 0; i < 1000; i++ {
+		for _, v := range p.ProjectIndices([]int{i}) {
+			norm += v * v
+		}
+	}
+	if mean := norm / 1000; math.Abs(mean-1) > 0.1 {
+		t.Errorf("mean squared norm was %f and not about 1", mean)
+	}
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	var loaded RandomProjection
+	if err := json.Unmarshal(b, &loaded); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	again := loaded.ProjectIndices([]int{7, 9000})
+	for j := range projected {
+		if projected[j] != again[j] {
+			t.Fatal("projection was not deterministic")
+		}
+	}
+
+	if _, err := NewRandomProjection(0, 2, 1); err != ErrCapacity {
+		t.Error("expected capacity error")
+	}
+}
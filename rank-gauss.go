@@ -0,0 +1,153 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+)
+
+// rankGaussClip keeps transformed values finite by
+// clipping probabilities away from 0 and 1.
+const rankGaussClip = 1e-7
+
+// RankGauss transforms numeric values to follow a standard
+// normal distribution: every value is mapped to its rank in
+// the fitted data (the empirical CDF) and then through the
+// inverse of the normal CDF. The fitted quantiles are kept
+// so that new values are transformed consistently and
+// transformed values can be mapped back.
+type RankGauss struct {
+	quantiles []float64
+}
+
+// NewRankGauss will fit a RankGauss transform to the values,
+// keeping `quantiles` evenly spaced quantiles of the data
+// (at least 2). An `ErrNoData` error is returned if there
+// are no values.
+func NewRankGauss(values []float64, quantiles int) (*RankGauss, error) {
+	if len(values) == 0 {
+		return &RankGauss{}, ErrNoData
+	}
+	if quantiles < 2 {
+		quantiles = 2
+	}
+
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+
+	fitted := make([]float64, quantiles, quantiles)
+	for k := range fitted {
+		fitted[k] = quantile(sorted, float64(k)/float64(quantiles-1))
+	}
+
+	return &RankGauss{
+		quantiles: fitted,
+	}, nil
+}
+
+// Transform will return the normal score of the value.
+// Values outside the fitted data are clipped to its range.
+func (t *RankGauss) Transform(x float64) float64 {
+	p := t.cdf(x)
+	p = math.Min(math.Max(p, rankGaussClip), 1-rankGaussClip)
+
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
+
+// TransformSlice will return the normal scores of the values.
+func (t *RankGauss) TransformSlice(values []float64) []float64 {
+	scores := make([]float64, len(values), len(values))
+	for i, v := range values {
+		scores[i] = t.Transform(v)
+	}
+
+	return scores
+}
+
+// Inverse will map a normal score back to the scale
+// of the fitted data.
+func (t *RankGauss) Inverse(z float64) float64 {
+	p := 0.5 * (1 + math.Erf(z/math.Sqrt2))
+
+	return quantile(t.quantiles, p)
+}
+
+// quantile will return the p-th quantile of the sorted
+// values, interpolating linearly between neighbours.
+func quantile(sorted []float64, p float64) float64 {
+	position := p * float64(len(sorted)-1)
+	i := int(position)
+	if i >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	fraction := position - float64(i)
+
+	return sorted[i] + fraction*(sorted[i+1]-sorted[i])
+}
+
+// cdf will return the position of the value among the
+// fitted quantiles as a probability. Values equal to
+// several quantiles take the middle of their positions.
+func (t *RankGauss) cdf(x float64) float64 {
+	q := t.quantiles
+	last := float64(len(q) - 1)
+
+	if x <= q[0] && x < q[len(q)-1] {
+		return 0
+	}
+	if x >= q[len(q)-1] && x > q[0] {
+		return 1
+	}
+
+	lo := sort.SearchFloat64s(q, x)
+	if lo < len(q) && q[lo] == x {
+		hi := lo
+		for hi+1 < len(q) && q[hi+1] == x {
+			hi++
+		}
+		return float64(lo+hi) / 2 / last
+	}
+
+	fraction := (x - q[lo-1]) / (q[lo] - q[lo-1])
+	return (float64(lo-1) + fraction) / last
+}
+
+// MarshalJSON will encode the fitted quantiles.
+func (t *RankGauss) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Quantiles []float64 `json:"quantiles"`
+	}{
+		Quantiles: t.quantiles,
+	})
+}
+
+// UnmarshalJSON will return an `ErrNoData` error
+// if there are fewer than 2 quantiles.
+func (t *RankGauss) UnmarshalJSON(data []byte) error {
+	var fitted struct {
+		Quantiles []float64 `json:"quantiles"`
+	}
+	err := json.Unmarshal(data, &fitted)
+	if err != nil {
+		return err
+	}
+	if len(fitted.Quantiles) < 2 {
+		return ErrNoData
+	}
+
+	t.quantiles = fitted.Quantiles
+	return nil
+}
@@ -0,0 +1,51 @@
+package encoder
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestRankGauss(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5, 100, 1000}
+	rg, err := NewRankGauss(values, 7)
+	if err != nil {
+		t.Fatalf("fit error: %+v", err)
+	}
+
+	if z := rg.Transform(4); math.Abs(z) > 1e-9 {
+		t.Errorf("median was transformed to %f and not 0", z)
+	}
+	scores := rg.TransformSlice(values)
+	for i := 1; i < len(scores); i++ {
+		if scores[i] <= scores[i-1] {
+			t.Errorf("scores are not increasing: %v", scores)
+		}
+	}
+	if math.Abs(scores[1]+scores[5]) > 1e-9 {
+		t.Error("scores are not symmetric")
+	}
+	if z := rg.Transform(1e9); math.IsInf(z, 0) || z != rg.Transform(1000) {
+		t.Errorf("out of range value was transformed to %f", z)
+	}
+
+	if x := rg.Inverse(rg.Transform(4.5)); math.Abs(x-4.5) > 1e-6 {
+		t.Errorf("inverse was %f and not 4.5", x)
+	}
+
+	b, err := json.Marshal(rg)
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	var loaded RankGauss
+	if err := json.Unmarshal(b, &loaded); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	if loaded.Transform(3) != rg.Transform(3) {
+		t.Error("fitted quantiles did not round trip")
+	}
+
+	if _, err := NewRankGauss(nil, 10); err != ErrNoData {
+		t.Error("expected no data error")
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/humilityai/sam"
+)
+
+// ReadOnlyOrdinal is a frozen view of an Ordinal encoder.
+// It cannot encode new values, and since it never changes
+// it can be read from many goroutines without locking.
+type ReadOnlyOrdinal struct {
+	encoder   map[uint64]uint64
+	decoder   *arena
+	normalize []Normalizer
+	missing   MissingPolicy
+}
+
+// Clone will return a deep copy of the encoder
+// that can be mutated independently.
+func (e *Ordinal) Clone() *Ordinal {
+	e.RLock()
+	defer e.RUnlock()
+
+	c := &Ordinal{
+		encoder:         copyCodes(e.encoder),
+		decoder:         e.decoder.clone(),
+		preprocessNames: e.preprocessNames,
+		preprocess:      e.preprocess,
+		missing:         e.missing,
+		canonical:       e.canonical,
+		created:         e.created,
+		updated:         e.updated,
+		RWMutex:         &sync.RWMutex{},
+	}
+	if e.aliases != nil {
+		c.aliases = make(map[string]string, len(e.aliases))
+		for alias, canonical := range e.aliases {
+			c.aliases[alias] = canonical
+		}
+	}
+	if e.reserved != nil {
+		c.reserved = make(map[uint64]bool, len(e.reserved))
+		for code := range e.reserved {
+			c.reserved[code] = true
+		}
+	}
+	if e.counts != nil {
+		c.counts = make(map[uint64]int, len(e.counts))
+		for code, count := range e.counts {
+			c.counts[code] = count
+		}
+	}
+	if e.lastSeen != nil {
+		c.ttl = e.ttl
+		c.lastSeen = make(map[uint64]time.Time, len(e.lastSeen))
+		for code, seen := range e.lastSeen {
+			c.lastSeen[code] = seen
+		}
+		c.expired = make(map[uint64]bool, len(e.expired))
+		for code := range e.expired {
+			c.expired[code] = true
+		}
+	}
+	if e.trie != nil {
+		c.trie = newTrie()
+		c.loaded()
+		c.updated = e.updated
+	}
+	c.changes = e.changes
+	c.history = append([]change{}, e.history...)
+	c.historyStart = e.historyStart
+
+	return c
+}
+
+// NewReadOnlyOrdinal will create a read-only view of the
+// fitted encoder, for serving code that must not grow the
+// vocabulary. It is equivalent to `e.Snapshot()`.
+func NewReadOnlyOrdinal(e *Ordinal) *ReadOnlyOrdinal {
+	return e.Snapshot()
+}
+
+// Snapshot will return a read-only view of the encoder's
+// current vocabulary. Values encoded after the snapshot
+// is taken are not visible in the snapshot.
+func (e *Ordinal) Snapshot() *ReadOnlyOrdinal {
+	e.RLock()
+	defer e.RUnlock()
+
+	return &ReadOnlyOrdinal{
+		encoder:   copyCodes(e.encoder),
+		decoder:   e.decoder.view(),
+		normalize: append([]Normalizer{}, e.preprocess...),
+		missing:   e.missing,
+	}
+}
+
+// Lookup will return the code of the given string
+// and whether or not the string has a code.
+func (e *ReadOnlyOrdinal) Lookup(s string) (uint64, bool) {
+	s = normalize(e.normalize, s)
+	if e.missing != nil && e.missing(s) {
+		s = missingCategory
+	}
+
+	code, ok := e.encoder[hashString(s)]
+	return code, ok
+}
+
+// Contains will return whether or not a string
+// has been assigned an ordinal code or not.
+func (e *ReadOnlyOrdinal) Contains(s string) bool {
+	_, ok := e.Lookup(s)
+	return ok
+}
+
+// Decode will return an empty string if supplied integer
+// argument is not a valid code.
+func (e *ReadOnlyOrdinal) Decode(i uint64) string {
+	value, _ := e.DecodeChecked(i)
+	return value
+}
+
+// DecodeChecked will return the string for the given code,
+// or an `ErrBounds` error if the code is not a valid code.
+func (e *ReadOnlyOrdinal) DecodeChecked(i uint64) (string, error) {
+	if i >= uint64(e.decoder.len()) {
+		return "", ErrBounds
+	}
+
+	return e.decoder.get(int(i)), nil
+}
+
+// Transform will return the code of the string as a
+// single feature. Unseen strings are encoded as NaN.
+func (e *ReadOnlyOrdinal) Transform(s string) []float64 {
+	code, ok := e.Lookup(s)
+	if !ok {
+		return []float64{math.NaN()}
+	}
+
+	return []float64{float64(code)}
+}
+
+// Length will return the number of encoded values.
+func (e *ReadOnlyOrdinal) Length() int {
+	return e.decoder.len()
+}
+
+// Range will call `f` with every code and its value,
+// in ascending code order, until `f` returns false.
+func (e *ReadOnlyOrdinal) Range(f func(code uint64, value string) bool) {
+	for code := 0; code < e.decoder.len(); code++ {
+		if !f(uint64(code), e.decoder.get(code)) {
+			return
+		}
+	}
+}
+
+// ReadOnlyOneHot is a frozen view of a OneHot encoder.
+// It cannot encode new values, and since it never changes
+// it can be read from many goroutines without locking.
+type ReadOnlyOneHot struct {
+	onehot *OneHot
+}
+
+// NewReadOnlyOneHot will create a read-only view of the
+// fitted encoder, for serving code that must not grow the
+// vocabulary. It is equivalent to `e.Snapshot()`.
+func NewReadOnlyOneHot(e *OneHot) *ReadOnlyOneHot {
+	return e.Snapshot()
+}
+
+// Snapshot will return a read-only view of the encoder's
+// current vocabulary. Values encoded after the snapshot
+// is taken are not visible in the snapshot.
+func (e *OneHot) Snapshot() *ReadOnlyOneHot {
+	encoder := make(sam.MapStringInt, len(e.encoder))
+	for value, dim := range e.encoder {
+		encoder[value] = dim
+	}
+
+	return &ReadOnlyOneHot{
+		onehot: &OneHot{
+			encoder:         encoder,
+			decoder:         append(sam.SliceString{}, e.decoder...),
+			dropFirst:       e.dropFirst,
+			frozen:          true,
+			smoothing:       e.smoothing,
+			preprocessNames: e.preprocessNames,
+			preprocess:      e.preprocess,
+			missing:         e.missing,
+			created:         e.created,
+			updated:         e.updated,
+		},
+	}
+}
+
+// Contains will check if a string has been assigned
+// a one-hot code or not.
+func (e *ReadOnlyOneHot) Contains(s string) bool {
+	return e.onehot.Contains(s)
+}
+
+// Decode will return the string of the codeword.
+// See `OneHot.Decode`.
+func (e *ReadOnlyOneHot) Decode(code []uint8) (string, error) {
+	return e.onehot.Decode(code)
+}
+
+// Transform will return the codeword of the string as
+// features. Unseen strings are encoded as all zeros.
+func (e *ReadOnlyOneHot) Transform(s string) []float64 {
+	return e.onehot.Transform(s)
+}
+
+// Dimension returns the dimension of each codeword.
+func (e *ReadOnlyOneHot) Dimension() int {
+	return e.onehot.Dimension()
+}
+
+// FeatureNames will return a name for every dimension
+// of the codewords, in order, of the form `column=value`.
+func (e *ReadOnlyOneHot) FeatureNames(column string) []string {
+	return e.onehot.FeatureNames(column)
+}
+
+func copyCodes(codes map[uint64]uint64) map[uint64]uint64 {
+	c := make(map[uint64]uint64, len(codes))
+	for k, v := range codes {
+		c[k] = v
+	}
+
+	return c
+}
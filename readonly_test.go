@@ -0,0 +1,75 @@
+package encoder
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestOrdinalClone(t *testing.T) {
+	encoder := NewOrdinal(true, WithTrieIndex())
+	encoder.Encode("red")
+
+	clone := encoder.Clone()
+	clone.Encode("green")
+
+	if encoder.Contains("green") {
+		t.Error("encoding into the clone modified the original")
+	}
+	if codes := clone.FindPrefix("gr"); len(codes) != 1 || codes[0] != 2 {
+		t.Errorf("clone prefix codes were %v and not [2]", codes)
+	}
+}
+
+func TestOrdinalSnapshot(t *testing.T) {
+	encoder := NewOrdinal(true)
+	encoder.Encode("red")
+
+	snapshot := encoder.Snapshot()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			encoder.Encode(string(rune('a' + i%26)))
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		if code, ok := snapshot.Lookup("red"); !ok || code != 1 {
+			t.Fatalf("snapshot code was %d and not 1", code)
+		}
+		if snapshot.Decode(1) != "red" {
+			t.Fatal("snapshot did not decode code 1")
+		}
+	}
+	wg.Wait()
+
+	if snapshot.Length() != 2 || snapshot.Contains("a") {
+		t.Error("snapshot changed after it was taken")
+	}
+}
+
+func TestOneHotSnapshot(t *testing.T) {
+	encoder := NewOneHot()
+	encoder.Encode("red")
+	encoder.Encode("green")
+
+	snapshot := NewReadOnlyOneHot(encoder)
+	encoder.Encode("blue")
+
+	features := snapshot.Transform("blue")
+	if len(features) != 3 || features[0]+features[1]+features[2] != 0 {
+		t.Errorf("unseen features were %v and not all zeros", features)
+	}
+	if snapshot.Contains("blue") || snapshot.Dimension() != 3 {
+		t.Error("snapshot changed after it was taken")
+	}
+	if v, err := snapshot.Decode(snapshot.onehot.code("green")); err != nil || v != "green" {
+		t.Errorf("decoded %q and not green: %v", v, err)
+	}
+	if f := NewReadOnlyOrdinal(NewOrdinal(true)).Transform("red"); !math.IsNaN(f[0]) {
+		t.Errorf("unseen ordinal feature was %v and not NaN", f)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"sort"
+)
+
+// RecordEncoder will encode records, such as the rows of
+// ingested CSV or JSON, into feature vectors, fitting an
+// encoder for every field of its spec. Features are ordered
+// by field name, so vectors are aligned whatever the order
+// of the fields in the spec or the records.
+type RecordEncoder struct {
+	fields  []string
+	columns []*column
+}
+
+// NewRecordEncoder will fit an encoder of the kind given by the
+// spec to every field of the spec. Fields missing from a record
+// are encoded as the empty string, and fields not in the spec
+// are ignored. An `ErrUnsupported` error is returned for an
+// unknown kind.
+func NewRecordEncoder(rows []map[string]string, spec map[string]ColumnKind) (*RecordEncoder, error) {
+	fields := make([]string, 0, len(spec))
+	for field := range spec {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	e := &RecordEncoder{
+		fields:  fields,
+		columns: make([]*column, len(fields), len(fields)),
+	}
+	for i, field := range fields {
+		values := make([]string, len(rows), len(rows))
+		for r, row := range rows {
+			values[r] = row[field]
+		}
+
+		c, err := fitColumn(spec[field], values)
+		if err != nil {
+			return &RecordEncoder{}, err
+		}
+		e.columns[i] = c
+	}
+
+	return e, nil
+}
+
+// Transform will return the features of the record.
+func (e *RecordEncoder) Transform(row map[string]string) []float64 {
+	features := make([]float64, 0, e.Dimension())
+	for i, field := range e.fields {
+		features = e.columns[i].transform(features, row[field])
+	}
+
+	return features
+}
+
+// TransformSlice will return the features of every record.
+func (e *RecordEncoder) TransformSlice(rows []map[string]string) [][]float64 {
+	features := make([][]float64, len(rows), len(rows))
+	for i, row := range rows {
+		features[i] = e.Transform(row)
+	}
+
+	return features
+}
+
+// Fields will return the encoded fields in
+// the order of their features.
+func (e *RecordEncoder) Fields() []string {
+	return append([]string{}, e.fields...)
+}
+
+// Dimension will return the number of features.
+func (e *RecordEncoder) Dimension() int {
+	var dimension int
+	for _, c := range e.columns {
+		dimension += c.dimension()
+	}
+
+	return dimension
+}
+
+// FeatureNames will return a name for every feature, in order:
+// `field=value` for every value of a one-hot field and the name
+// of the field otherwise.
+func (e *RecordEncoder) FeatureNames() []string {
+	names := make([]string, 0, e.Dimension())
+	for i, c := range e.columns {
+		names = append(names, c.featureNames(e.fields[i])...)
+	}
+
+	return names
+}
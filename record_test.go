@@ -0,0 +1,47 @@
+package encoder
+
+import "testing"
+
+func TestRecordEncoder(t *testing.T) {
+	rows := []map[string]string{
+		{"country": "US", "age": "20", "id": "1"},
+		{"country": "FR", "age": "40", "id": "2"},
+		{"age": "30"},
+	}
+	spec := map[string]ColumnKind{
+		"country": KindOneHot,
+		"age":     KindMinMax,
+	}
+
+	e, err := NewRecordEncoder(rows, spec)
+	if err != nil {
+		t.Fatalf("fit error: %+v", err)
+	}
+
+	names := e.FeatureNames()
+	expected := []string{"age", "country=", "country=US", "country=FR"}
+	if len(names) != len(expected) {
+		t.Fatalf("feature names were %v and not %v", names, expected)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("feature names were %v and not %v", names, expected)
+		}
+	}
+
+	features := e.TransformSlice(rows)
+	want := [][]float64{{0, 0, 1, 0}, {1, 0, 0, 1}, {0.5, 1, 0, 0}}
+	for i := range want {
+		for j := range want[i] {
+			if features[i][j] != want[i][j] {
+				t.Errorf("features of row %d were %v and not %v", i, features[i], want[i])
+				break
+			}
+		}
+	}
+
+	spec["age"] = KindNumeric
+	if _, err := NewRecordEncoder([]map[string]string{{"age": "old"}}, spec); err == nil {
+		t.Error("expected parse error")
+	}
+}
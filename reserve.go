@@ -0,0 +1,133 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+// MaxReserve is the largest number of codes that one call to
+// Reserve or AssignCode may add to an encoder. Every code up
+// to the last one is held by the encoder, so larger gaps would
+// take memory in proportion to the code rather than the values.
+const MaxReserve = 1 << 20
+
+// Reserve will reserve the codes in the range [from, to) so
+// that they are never assigned to a value by Encode. Reserved
+// codes can only be assigned with AssignCode, and decode to
+// the empty string until they are. If `from` is above every
+// code, the codes between them are reserved too.
+// An `ErrCodeTaken` error is returned if any code in the range
+// is already assigned to a value, and an `ErrReserveRange` error
+// if the range would add more than MaxReserve codes.
+func (e *Ordinal) Reserve(from, to uint64) error {
+	e.lock()
+	defer e.Unlock()
+
+	if length := uint64(e.decoder.len()); to > length && to-length > MaxReserve {
+		return ErrReserveRange
+	}
+	for code := from; code < to && code < uint64(e.decoder.len()); code++ {
+		if !e.reserved[code] {
+			return ErrCodeTaken
+		}
+	}
+
+	e.reserve(from, to)
+	return nil
+}
+
+// AssignCode will assign the given code to the given value,
+// so that sentinel values can be given contractually stable
+// codes before values are encoded. The code must be a reserved
+// code or a code above every assigned code; codes skipped over
+// are reserved. The value is recorded like a value assigned a
+// code by Encode, and assigning a reserved code advances the
+// encoder's Version.
+// Assigning a value the code it already has does nothing.
+// An `ErrDuplicateValue` error is returned if the value already
+// has a different code, and an `ErrCodeTaken` error if the code
+// is already assigned to another value. An `ErrReserveRange`
+// error is returned if the code would add more than MaxReserve
+// codes to the encoder. If the record of the value cannot be
+// written to the write-ahead log the code is still assigned,
+// and the error is returned.
+func (e *Ordinal) AssignCode(s string, code uint64) error {
+	e.lock()
+	defer e.Unlock()
+
+	return e.assign(e.prepare(s), code)
+}
+
+// assign will assign the code to the prepared value.
+func (e *Ordinal) assign(s string, code uint64) error {
+	hashedKey := hashString(s)
+	if current, ok := e.encoder[hashedKey]; ok {
+		if current != code {
+			return ErrDuplicateValue
+		}
+		return nil
+	}
+
+	length := uint64(e.decoder.len())
+	switch {
+	case code < length && !e.reserved[code]:
+		return ErrCodeTaken
+	case code < length:
+		delete(e.reserved, code)
+		e.decoder.set(int(code), s)
+		e.changed(code)
+	case code-length >= MaxReserve:
+		return ErrReserveRange
+	default:
+		e.reserve(length, code)
+		e.decoder.append(s)
+	}
+
+	return e.insert(s, hashedKey, code)
+}
+
+// IsReserved will return whether or not the code
+// is reserved and not yet assigned to a value.
+func (e *Ordinal) IsReserved(code uint64) bool {
+	e.RLock()
+	defer e.RUnlock()
+
+	return e.reserved[code]
+}
+
+// reserve will mark the codes in [from, to) as reserved,
+// extending the decoder with placeholders as needed. Codes
+// between the last code and `from` are reserved as well.
+func (e *Ordinal) reserve(from, to uint64) {
+	if length := uint64(e.decoder.len()); from > length {
+		from = length
+	}
+	if e.reserved == nil {
+		e.reserved = make(map[uint64]bool)
+	}
+
+	for code := from; code < to; code++ {
+		e.reserved[code] = true
+	}
+	for uint64(e.decoder.len()) < to {
+		e.decoder.append("")
+	}
+}
+
+// restoreReserved will replace the reserved codes of
+// the encoder with those of a loaded vocabulary.
+func (e *Ordinal) restoreReserved(codes []uint64) {
+	e.reserved = nil
+	if len(codes) > 0 {
+		e.reserved = codeSet(codes)
+	}
+}
@@ -0,0 +1,151 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestOrdinalAssignCode(t *testing.T) {
+	encoder := NewOrdinal(true)
+
+	err := encoder.Reserve(1, 4)
+	if err != nil {
+		t.Fatalf("reserve error: %+v", err)
+	}
+	err = encoder.AssignCode("UNK", 1)
+	if err != nil {
+		t.Fatalf("assign error: %+v", err)
+	}
+
+	if code := encoder.Encode("red"); code != 4 {
+		t.Errorf("code was %d and not 4", code)
+	}
+	if code := encoder.Encode("UNK"); code != 1 {
+		t.Errorf("code was %d and not 1", code)
+	}
+	if !encoder.IsReserved(2) || encoder.IsReserved(1) {
+		t.Error("reserved codes were not tracked")
+	}
+
+	err = encoder.AssignCode("PAD", 10)
+	if err != nil {
+		t.Fatalf("assign error: %+v", err)
+	}
+	if encoder.Decode(10) != "PAD" || !encoder.IsReserved(7) {
+		t.Error("assigning a code above every code did not reserve the gap")
+	}
+
+	if encoder.AssignCode("blue", 4) != ErrCodeTaken {
+		t.Error("expected code taken error")
+	}
+	if encoder.AssignCode("UNK", 2) != ErrDuplicateValue {
+		t.Error("expected duplicate value error")
+	}
+	if encoder.Reserve(0, 2) != ErrCodeTaken {
+		t.Error("expected code taken error")
+	}
+}
+
+func TestOrdinalReserveGap(t *testing.T) {
+	encoder := NewOrdinal(true)
+
+	err := encoder.Reserve(3, 5)
+	if err != nil {
+		t.Fatalf("reserve error: %+v", err)
+	}
+	for code := uint64(1); code < 5; code++ {
+		if !encoder.IsReserved(code) {
+			t.Errorf("code %d was not reserved", code)
+		}
+	}
+
+	if err := encoder.Reserve(0, 1<<40); err != ErrReserveRange {
+		t.Errorf("expected reserve range error, got %v", err)
+	}
+	if err := encoder.AssignCode("x", 1<<40); err != ErrReserveRange {
+		t.Errorf("expected reserve range error, got %v", err)
+	}
+	if encoder.Length() != 5 || encoder.Contains("x") {
+		t.Error("rejected reservation changed the encoder")
+	}
+}
+
+func TestOrdinalAssignCodeRecorded(t *testing.T) {
+	var log bytes.Buffer
+	encoder := NewOrdinal(true, WithTrieIndex())
+	encoder.SetWAL(&log)
+
+	var assigned []uint64
+	encoder.OnNewCategory(func(value string, code uint64) {
+		assigned = append(assigned, code)
+	})
+
+	err := encoder.Reserve(1, 3)
+	if err != nil {
+		t.Fatalf("reserve error: %+v", err)
+	}
+	view := encoder.Snapshot()
+	err = encoder.AssignCode("UNK", 2)
+	if err != nil {
+		t.Fatalf("assign error: %+v", err)
+	}
+	err = encoder.AssignCode("PAD", 5)
+	if err != nil {
+		t.Fatalf("assign error: %+v", err)
+	}
+
+	if len(assigned) != 2 || assigned[0] != 2 || assigned[1] != 5 {
+		t.Errorf("new category codes were %v and not [2 5]", assigned)
+	}
+	if codes := encoder.FindPrefix("UN"); len(codes) != 1 || codes[0] != 2 {
+		t.Errorf("prefix codes were %v and not [2]", codes)
+	}
+	if view.Contains("UNK") || view.Decode(2) != "" {
+		t.Error("snapshot saw a code assigned after it was taken")
+	}
+
+	restored := NewOrdinal(true)
+	err = restored.ReplayWAL(bytes.NewReader(log.Bytes()))
+	if err != nil {
+		t.Fatalf("replay error: %+v", err)
+	}
+	if restored.Encode("UNK") != 2 || restored.Encode("PAD") != 5 || !restored.IsReserved(4) {
+		t.Error("replayed encoder did not restore assigned codes")
+	}
+}
+
+func TestOrdinalReservedSerialized(t *testing.T) {
+	encoder := NewOrdinal(false)
+	encoder.Encode("red")
+	err := encoder.Reserve(1, 3)
+	if err != nil {
+		t.Fatalf("reserve error: %+v", err)
+	}
+
+	data, err := json.Marshal(encoder)
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	restored := NewOrdinal(false)
+	err = json.Unmarshal(data, restored)
+	if err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	if restored.Contains("") || !restored.IsReserved(1) || !restored.IsReserved(2) {
+		t.Error("reserved codes were not restored from JSON")
+	}
+
+	data, err = encoder.GobEncode()
+	if err != nil {
+		t.Fatalf("gob encode error: %+v", err)
+	}
+	restored = NewOrdinal(false)
+	err = restored.GobDecode(data)
+	if err != nil {
+		t.Fatalf("gob decode error: %+v", err)
+	}
+	if !restored.IsReserved(2) || restored.AssignCode("UNK", 2) != nil {
+		t.Error("reserved codes were not restored from gob")
+	}
+}
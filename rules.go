@@ -0,0 +1,74 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"regexp"
+)
+
+// Rules maps every input matching a regular expression to a
+// canonical category, so that fields with embedded identifiers
+// (e.g. `ERR-1234`) encode as a single category. Rules are used
+// with an encoder through their Apply method:
+//
+//	rules := NewRules()
+//	rules.Add(`^ERR-\d+`, "error_code")
+//	e := NewOrdinal(false, WithNormalizer("error_codes", rules.Apply))
+//
+// Rules must not be added while an encoder is using them.
+type Rules struct {
+	rules []rule
+}
+
+type rule struct {
+	pattern  *regexp.Regexp
+	category string
+}
+
+// NewRules will return an empty set of rules.
+func NewRules() *Rules {
+	return &Rules{
+		rules: make([]rule, 0),
+	}
+}
+
+// Add will map every input matching the pattern to the
+// category. Rules are checked in the order they are added.
+// An error is returned if the pattern does not compile.
+func (r *Rules) Add(pattern, category string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+
+	r.rules = append(r.rules, rule{pattern: re, category: category})
+	return nil
+}
+
+// Apply will return the category of the first rule
+// matching the input, or the input if no rule matches.
+func (r *Rules) Apply(s string) string {
+	for _, rule := range r.rules {
+		if rule.pattern.MatchString(s) {
+			return rule.category
+		}
+	}
+
+	return s
+}
+
+// Length will return the number of rules.
+func (r *Rules) Length() int {
+	return len(r.rules)
+}
@@ -0,0 +1,30 @@
+package encoder
+
+import (
+	"testing"
+)
+
+func TestRules(t *testing.T) {
+	rules := NewRules()
+	if err := rules.Add(`^ERR-\d+`, "error_code"); err != nil {
+		t.Fatalf("add error: %+v", err)
+	}
+	if err := rules.Add(`^ERR`, "error"); err != nil {
+		t.Fatalf("add error: %+v", err)
+	}
+	if err := rules.Add(`(`, "invalid"); err == nil {
+		t.Error("expected pattern error")
+	}
+
+	encoder := NewOrdinal(false, WithNormalizer("error_codes", rules.Apply))
+	code := encoder.Encode("ERR-1234")
+	if encoder.Encode("ERR-99") != code || encoder.Decode(code) != "error_code" {
+		t.Error("matching inputs did not share the rule's category")
+	}
+	if encoder.Decode(encoder.Encode("ERROR")) != "error" {
+		t.Error("rules were not checked in order")
+	}
+	if encoder.Decode(encoder.Encode("OK")) != "OK" {
+		t.Error("unmatched input was changed")
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+// Union will return a new encoder with every value of `a`
+// followed by every value of `b` not in `a`, along with
+// tables mapping the codes of `a` and of `b` to the codes of
+// the new encoder. Values of `a` keep their codes.
+func Union(a, b *Ordinal) (*Ordinal, map[uint64]uint64, map[uint64]uint64) {
+	e := NewOrdinal(false)
+	remapA := e.add(a.List(), nil)
+	remapB := e.add(b.List(), nil)
+
+	return e, remapA, remapB
+}
+
+// Intersect will return a new encoder with the values of `a`
+// that are also in `b`, in the order of their codes in `a`,
+// along with tables mapping the codes of `a` and of `b` to the
+// codes of the new encoder. If code 0 of `a` is the empty string
+// it stays at code 0.
+func Intersect(a, b *Ordinal) (*Ordinal, map[uint64]uint64, map[uint64]uint64) {
+	valuesA, valuesB := a.List(), b.List()
+	inB := vocabulary(valuesB)
+
+	e := NewOrdinal(false)
+	remapA := e.add(valuesA, func(code int, v string) bool {
+		return inB[v] || (code == 0 && v == "")
+	})
+	inA := vocabulary(e.decoder.strings())
+	remapB := e.add(valuesB, func(code int, v string) bool {
+		return inA[v]
+	})
+
+	return e, remapA, remapB
+}
+
+// Subtract will return a new encoder with the values of `a`
+// that are not in `b`, in the order of their codes in `a`,
+// along with a table mapping the codes of `a` to the codes of
+// the new encoder. If code 0 of `a` is the empty string it
+// stays at code 0.
+func Subtract(a, b *Ordinal) (*Ordinal, map[uint64]uint64) {
+	valuesA := a.List()
+	inB := vocabulary(b.List())
+
+	e := NewOrdinal(false)
+	remapA := e.add(valuesA, func(code int, v string) bool {
+		return !inB[v] || (code == 0 && v == "")
+	})
+
+	return e, remapA
+}
+
+// add will encode every value, indexed by its code in another
+// encoder, that `keep` accepts (or every value if `keep` is nil)
+// and return a table mapping the other encoder's codes to
+// the codes in this encoder.
+func (e *Ordinal) add(values []string, keep func(code int, v string) bool) map[uint64]uint64 {
+	remap := make(map[uint64]uint64)
+	for code, v := range values {
+		if keep != nil && !keep(code, v) {
+			continue
+		}
+		remap[uint64(code)] = e.Encode(v)
+	}
+
+	return remap
+}
+
+// vocabulary will return the set of values.
+func vocabulary(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+
+	return set
+}
@@ -0,0 +1,49 @@
+package encoder
+
+import "testing"
+
+func TestVocabularySetOperations(t *testing.T) {
+	a := NewOrdinal(true)
+	a.EncodeSlice([]string{"red", "green", "blue"})
+	b := NewOrdinal(true)
+	b.EncodeSlice([]string{"blue", "yellow", "red"})
+
+	union, remapA, remapB := Union(a, b)
+	if union.Length() != 5 {
+		t.Errorf("union has %d values and not 5", union.Length())
+	}
+	for code, v := range a.List() {
+		if remapA[uint64(code)] != uint64(code) || union.Decode(uint64(code)) != v {
+			t.Errorf("value %s of a changed code in the union", v)
+		}
+	}
+	for code, v := range b.List() {
+		if union.Decode(remapB[uint64(code)]) != v {
+			t.Errorf("value %s of b was remapped to the wrong code", v)
+		}
+	}
+
+	intersection, remapA, remapB := Intersect(a, b)
+	expected := []string{"", "red", "blue"}
+	list := intersection.List()
+	if len(list) != len(expected) {
+		t.Fatalf("intersection was %v and not %v", list, expected)
+	}
+	for i := range expected {
+		if list[i] != expected[i] {
+			t.Errorf("intersection was %v and not %v", list, expected)
+		}
+	}
+	if _, ok := remapA[2]; ok {
+		t.Error("green was remapped into the intersection")
+	}
+	if remapB[1] != 2 || remapB[3] != 1 {
+		t.Errorf("unexpected remapping of b: %v", remapB)
+	}
+
+	difference, remapA := Subtract(a, b)
+	list = difference.List()
+	if len(list) != 2 || list[0] != "" || list[1] != "green" || remapA[2] != 1 {
+		t.Errorf("unexpected difference %v with remapping %v", list, remapA)
+	}
+}
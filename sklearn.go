@@ -0,0 +1,135 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// sklearnArtifact is the JSON export of a fitted
+// scikit-learn encoder's learned attributes.
+type sklearnArtifact struct {
+	Classes    []json.RawMessage   `json:"classes_"`
+	Categories [][]json.RawMessage `json:"categories_"`
+	DropIdx    json.RawMessage     `json:"drop_idx_"`
+}
+
+// ImportSklearnLabelEncoder will create an Ordinal encoder from
+// the JSON export of a fitted `sklearn.preprocessing.LabelEncoder`
+// (`{"classes_": [...]}`). Every class is assigned the same code
+// as in scikit-learn: its index in `classes_`.
+// Numeric classes are encoded as their JSON text.
+func ImportSklearnLabelEncoder(data []byte) (*Ordinal, error) {
+	var artifact sklearnArtifact
+	err := json.Unmarshal(data, &artifact)
+	if err != nil {
+		return NewOrdinal(false), err
+	}
+
+	return sklearnOrdinal(artifact.Classes)
+}
+
+// ImportSklearnOrdinalEncoder will create one Ordinal encoder per
+// column from the JSON export of a fitted `sklearn.preprocessing.OrdinalEncoder`
+// (`{"categories_": [[...], ...]}`). Every category is assigned
+// the same code as in scikit-learn: its index in its column's categories.
+func ImportSklearnOrdinalEncoder(data []byte) ([]*Ordinal, error) {
+	var artifact sklearnArtifact
+	err := json.Unmarshal(data, &artifact)
+	if err != nil {
+		return []*Ordinal{}, err
+	}
+
+	encoders := make([]*Ordinal, len(artifact.Categories), len(artifact.Categories))
+	for i, categories := range artifact.Categories {
+		encoders[i], err = sklearnOrdinal(categories)
+		if err != nil {
+			return []*Ordinal{}, err
+		}
+	}
+
+	return encoders, nil
+}
+
+// ImportSklearnOneHotEncoder will create one OneHot encoder per
+// column from the JSON export of a fitted `sklearn.preprocessing.OneHotEncoder`
+// (`{"categories_": [[...], ...]}`).
+// The encoders drop the dimension of the empty string, so their
+// codewords have exactly the dimensions of the scikit-learn output,
+// in the same order. Encoders fitted with `drop` are not supported.
+func ImportSklearnOneHotEncoder(data []byte) ([]*OneHot, error) {
+	var artifact sklearnArtifact
+	err := json.Unmarshal(data, &artifact)
+	if err != nil {
+		return []*OneHot{}, err
+	}
+
+	if len(artifact.DropIdx) > 0 && !bytes.Equal(artifact.DropIdx, []byte("null")) {
+		return []*OneHot{}, ErrUnsupported
+	}
+
+	encoders := make([]*OneHot, len(artifact.Categories), len(artifact.Categories))
+	for i, categories := range artifact.Categories {
+		e := NewOneHot(WithDropFirst())
+		for _, raw := range categories {
+			category, err := sklearnValue(raw)
+			if err != nil {
+				return []*OneHot{}, err
+			}
+			if category == "" {
+				return []*OneHot{}, ErrReservedValue
+			}
+			if e.Contains(category) {
+				return []*OneHot{}, ErrDuplicateValue
+			}
+			e.Encode(category)
+		}
+		encoders[i] = e
+	}
+
+	return encoders, nil
+}
+
+func sklearnOrdinal(classes []json.RawMessage) (*Ordinal, error) {
+	e := NewOrdinal(false)
+	for _, raw := range classes {
+		class, err := sklearnValue(raw)
+		if err != nil {
+			return NewOrdinal(false), err
+		}
+		if e.Contains(class) {
+			return NewOrdinal(false), ErrDuplicateValue
+		}
+		e.Encode(class)
+	}
+
+	return e, nil
+}
+
+// sklearnValue will return a JSON string as its value
+// and any other JSON value as its literal text.
+func sklearnValue(raw json.RawMessage) (string, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '"' {
+		// JSON escapes, such as \/ and surrogate
+		// pairs, are not all valid in Go literals
+		var value string
+		err := json.Unmarshal(raw, &value)
+		return value, err
+	}
+
+	return string(raw), nil
+}
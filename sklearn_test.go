@@ -0,0 +1,57 @@
+package encoder
+
+import (
+	"testing"
+)
+
+func TestImportSklearnLabelEncoder(t *testing.T) {
+	encoder, err := ImportSklearnLabelEncoder([]byte(`{"classes_": ["blue", "green", "red"]}`))
+	if err != nil {
+		t.Fatalf("import error: %+v", err)
+	}
+
+	if code := encoder.Encode("red"); code != 2 {
+		t.Errorf("code was %d and not 2", code)
+	}
+
+	escaped, err := ImportSklearnLabelEncoder([]byte(`{"classes_": ["a\/b", "\ud83d\ude00"]}`))
+	if err != nil {
+		t.Fatalf("import error: %+v", err)
+	}
+	if escaped.Decode(0) != "a/b" || escaped.Decode(1) != "\U0001F600" {
+		t.Errorf("escaped classes were %q and %q", escaped.Decode(0), escaped.Decode(1))
+	}
+
+	_, err = ImportSklearnLabelEncoder([]byte(`{"classes_": ["red", "red"]}`))
+	if err != ErrDuplicateValue {
+		t.Error("expected duplicate value error")
+	}
+}
+
+func TestImportSklearnOrdinalEncoder(t *testing.T) {
+	encoders, err := ImportSklearnOrdinalEncoder([]byte(`{"categories_": [["a", "b"], [1, 2, 3]]}`))
+	if err != nil {
+		t.Fatalf("import error: %+v", err)
+	}
+
+	if len(encoders) != 2 || encoders[1].Encode("3") != 2 {
+		t.Error("numeric categories were not imported in order")
+	}
+}
+
+func TestImportSklearnOneHotEncoder(t *testing.T) {
+	encoders, err := ImportSklearnOneHotEncoder([]byte(`{"categories_": [["a", "b", "c"]], "drop_idx_": null}`))
+	if err != nil {
+		t.Fatalf("import error: %+v", err)
+	}
+
+	code := encoders[0].Encode("b")
+	if len(code) != 3 || code[1] != 1 {
+		t.Errorf("code was %v and not [0 1 0]", code)
+	}
+
+	_, err = ImportSklearnOneHotEncoder([]byte(`{"categories_": [["a", "b"]], "drop_idx_": [0]}`))
+	if err != ErrUnsupported {
+		t.Error("expected unsupported error")
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"database/sql/driver"
+)
+
+// The encoders implement sql.Scanner and driver.Valuer with
+// their JSON form, so they can be stored in a BLOB, TEXT or
+// JSONB column without conversion code.
+
+// Value ...
+func (e *Ordinal) Value() (driver.Value, error) {
+	return e.MarshalJSON()
+}
+
+// Scan ...
+func (e *Ordinal) Scan(src interface{}) error {
+	return scanJSON(src, e.UnmarshalJSON)
+}
+
+// Value ...
+func (e *OneHot) Value() (driver.Value, error) {
+	return e.MarshalJSON()
+}
+
+// Scan ...
+func (e *OneHot) Scan(src interface{}) error {
+	return scanJSON(src, e.UnmarshalJSON)
+}
+
+// Value ...
+func (e *TrieOrdinal) Value() (driver.Value, error) {
+	return e.MarshalJSON()
+}
+
+// Scan ...
+func (e *TrieOrdinal) Scan(src interface{}) error {
+	return scanJSON(src, e.UnmarshalJSON)
+}
+
+// Value ...
+func (f *BloomFilter) Value() (driver.Value, error) {
+	return f.MarshalJSON()
+}
+
+// Scan ...
+func (f *BloomFilter) Scan(src interface{}) error {
+	return scanJSON(src, f.UnmarshalJSON)
+}
+
+// scanJSON will pass the JSON held by a column value to
+// `unmarshal`, returning an `ErrFormat` error for column
+// values that are neither bytes nor a string.
+func scanJSON(src interface{}, unmarshal func([]byte) error) error {
+	switch v := src.(type) {
+	case []byte:
+		// the driver may reuse the slice after Scan returns
+		return unmarshal(append([]byte{}, v...))
+	case string:
+		return unmarshal([]byte(v))
+	}
+
+	return ErrFormat
+}
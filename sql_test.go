@@ -0,0 +1,42 @@
+package encoder
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+)
+
+var (
+	_ sql.Scanner   = &Ordinal{}
+	_ driver.Valuer = &Ordinal{}
+	_ sql.Scanner   = &OneHot{}
+	_ driver.Valuer = &OneHot{}
+	_ sql.Scanner   = &TrieOrdinal{}
+	_ driver.Valuer = &TrieOrdinal{}
+	_ sql.Scanner   = &BloomFilter{}
+	_ driver.Valuer = &BloomFilter{}
+)
+
+func TestOrdinalScan(t *testing.T) {
+	encoder := NewOrdinal(true)
+	encoder.Encode("red")
+
+	value, err := encoder.Value()
+	if err != nil {
+		t.Fatalf("value error: %+v", err)
+	}
+	if !driver.IsValue(value) {
+		t.Errorf("%T is not a driver value", value)
+	}
+
+	scanned := NewOrdinal(false)
+	if err := scanned.Scan(value); err != nil || scanned.Decode(1) != "red" {
+		t.Error("bytes did not scan")
+	}
+	if err := scanned.Scan(`["", "blue"]`); err != nil || scanned.Decode(1) != "blue" {
+		t.Error("string did not scan")
+	}
+	if err := scanned.Scan(int64(1)); err != ErrFormat {
+		t.Error("expected format error")
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"sort"
+	"time"
+	"unsafe"
+)
+
+// statsTopN is the number of most frequent
+// categories reported by Stats.
+const statsTopN = 10
+
+// Sizes used to estimate memory footprints.
+const (
+	stringHeaderBytes = int(unsafe.Sizeof(""))
+	intBytes          = int(unsafe.Sizeof(int(0)))
+	float64Bytes      = 8
+	mapEntryBytes     = 48
+)
+
+// Stats describes the state of an encoder
+// for monitoring and dashboards.
+type Stats struct {
+	// Cardinality is the number of distinct encoded values.
+	Cardinality int `json:"cardinality"`
+	// SizeBytes is an estimate of the memory held by the encoder.
+	SizeBytes int `json:"size_bytes"`
+	// Top holds the most frequent categories, most frequent
+	// first, for encoders that track category counts.
+	Top []CategoryCount `json:"top,omitempty"`
+	// Created is when the encoder was constructed.
+	Created time.Time `json:"created"`
+	// Updated is when a value was last added to the encoder.
+	Updated time.Time `json:"updated"`
+}
+
+// CategoryCount is the number of times
+// a category has been observed.
+type CategoryCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Stats will return the cardinality, estimated
+// memory footprint and timestamps of the encoder,
+// and its most frequent categories if it was
+// created with `WithCounts`.
+func (e *Ordinal) Stats() Stats {
+	e.RLock()
+	defer e.RUnlock()
+
+	var top []CategoryCount
+	if e.counts != nil {
+		top = e.topN(statsTopN)
+	}
+
+	return Stats{
+		Cardinality: e.decoder.len(),
+		SizeBytes:   e.sizeBytes(),
+		Top:         top,
+		Created:     e.created,
+		Updated:     e.updated,
+	}
+}
+
+// Stats will return the cardinality, estimated
+// memory footprint and timestamps of the encoder.
+func (e *OneHot) Stats() Stats {
+	return Stats{
+		Cardinality: len(e.decoder),
+		SizeBytes:   e.SizeBytes(),
+		Created:     e.created,
+		Updated:     e.updated,
+	}
+}
+
+// Stats will return the cardinality, estimated memory
+// footprint, most frequent categories and timestamps
+// of the encoder.
+func (e *Frequency) Stats() Stats {
+	return Stats{
+		Cardinality: len(e.encoder),
+		SizeBytes:   e.SizeBytes(),
+		Top:         topCounts(e.encoder, statsTopN),
+		Created:     e.created,
+		Updated:     e.created,
+	}
+}
+
+// SizeBytes will return an estimate of the
+// memory held by the encoder in bytes.
+func (e *Ordinal) SizeBytes() int {
+	e.RLock()
+	defer e.RUnlock()
+
+	return e.sizeBytes()
+}
+
+func (e *Ordinal) sizeBytes() int {
+	return len(e.encoder)*mapEntryBytes + e.decoder.sizeBytes()
+}
+
+// SizeBytes will return an estimate of the
+// memory held by the encoder in bytes.
+func (e *OneHot) SizeBytes() int {
+	size := cap(e.decoder) * stringHeaderBytes
+	for _, v := range e.decoder {
+		size += mapEntryBytes + len(v)
+	}
+
+	return size
+}
+
+// SizeBytes will return an estimate of the
+// memory held by the encoder in bytes.
+func (e *Frequency) SizeBytes() int {
+	var size int
+	for k := range e.encoder {
+		size += mapEntryBytes + len(k)
+	}
+
+	return size
+}
+
+// SizeBytes will return an estimate of the
+// memory held by the encoder in bytes.
+func (e *RollingFrequency) SizeBytes() int {
+	return cap(e.codes) * intBytes
+}
+
+// SizeBytes will return an estimate of the
+// memory held by the encoder in bytes.
+func (e *SessionFrequency) SizeBytes() int {
+	return cap(e.codes) * intBytes
+}
+
+// SizeBytes will return an estimate of the
+// memory held by the encoder in bytes.
+func (e *JamesSteinRegression) SizeBytes() int {
+	return mapSizeBytes(e.encoder)
+}
+
+// SizeBytes will return an estimate of the
+// memory held by the encoder in bytes.
+func (e *JamesSteinClassification) SizeBytes() int {
+	return cap(e.encodedValues) * float64Bytes
+}
+
+// SizeBytes will return an estimate of the
+// memory held by the encoder in bytes.
+func (e *GLMMRegression) SizeBytes() int {
+	return mapSizeBytes(e.encoder)
+}
+
+// SizeBytes will return an estimate of the
+// memory held by the encoder in bytes.
+func (e *GLMMClassification) SizeBytes() int {
+	return mapSizeBytes(e.encoder)
+}
+
+// SizeBytes will return an estimate of the
+// memory held by the encoder in bytes.
+func (e *ProbabilityRatio) SizeBytes() int {
+	return mapSizeBytes(e.encoder)
+}
+
+// SizeBytes will return an estimate of the
+// memory held by the encoder in bytes.
+func (e *LogOdds) SizeBytes() int {
+	return mapSizeBytes(e.encoder)
+}
+
+// SizeBytes will return an estimate of the
+// memory held by the encoder in bytes.
+func (e *Hash) SizeBytes() int {
+	return int(unsafe.Sizeof(*e))
+}
+
+// SizeBytes will return an estimate of the
+// memory held by the bloom filter in bytes.
+func (f *BloomFilter) SizeBytes() int {
+	return cap(f.bits) * 8
+}
+
+// SizeBytes will return an estimate of the
+// memory held by the estimator in bytes.
+func (c *CardinalityEstimator) SizeBytes() int {
+	return cap(c.registers)
+}
+
+// mapSizeBytes will estimate the memory held by
+// a map from strings to float64 codes.
+func mapSizeBytes(m map[string]float64) int {
+	var size int
+	for k := range m {
+		size += mapEntryBytes + len(k)
+	}
+
+	return size
+}
+
+// topCounts will return the n most frequent values,
+// most frequent first, breaking ties by value.
+func topCounts(counts map[string]int, n int) []CategoryCount {
+	top := make([]CategoryCount, 0, len(counts))
+	for v, c := range counts {
+		top = append(top, CategoryCount{Value: v, Count: c})
+	}
+
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return top[i].Value < top[j].Value
+	})
+
+	if len(top) > n {
+		top = top[:n]
+	}
+
+	return top
+}
@@ -0,0 +1,172 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package stats
+
+import (
+	"math"
+
+	"github.com/humilityai/encoder"
+)
+
+// ChiSquareTest is the result of Pearson's chi-square test
+// of independence between a categorical column and a target.
+type ChiSquareTest struct {
+	// Statistic is the chi-square statistic.
+	Statistic float64 `json:"statistic"`
+	// DegreesOfFreedom is (categories - 1) * (classes - 1).
+	DegreesOfFreedom int `json:"degrees_of_freedom"`
+	// PValue is the probability of a statistic at least as
+	// large if the column and the target were independent.
+	PValue float64 `json:"p_value"`
+}
+
+// ChiSquare will test the independence of the categorical
+// column and the class target, the same inputs as the
+// classification target encoders take. An
+// `encoder.ErrTargetLength` error is returned if the
+// target is not the same length as the column.
+func ChiSquare(values, target []string) (ChiSquareTest, error) {
+	if len(target) != len(values) {
+		return ChiSquareTest{}, encoder.ErrTargetLength
+	}
+
+	t := newContingency(values, target)
+	dof := (len(t.rows) - 1) * (len(t.cols) - 1)
+	statistic := t.chiSquare()
+
+	test := ChiSquareTest{
+		Statistic:        statistic,
+		DegreesOfFreedom: dof,
+		PValue:           1,
+	}
+	if dof > 0 {
+		test.PValue = upperGamma(float64(dof)/2, statistic/2)
+	}
+
+	return test, nil
+}
+
+// CramersV will return Cramér's V between the categorical
+// column and the class target: the strength of their
+// association, from 0 (independent) to 1 (the column
+// determines the target). An `encoder.ErrTargetLength`
+// error is returned if the target is not the same length
+// as the column.
+func CramersV(values, target []string) (float64, error) {
+	if len(target) != len(values) {
+		return 0, encoder.ErrTargetLength
+	}
+
+	t := newContingency(values, target)
+	k := len(t.rows) - 1
+	if len(t.cols)-1 < k {
+		k = len(t.cols) - 1
+	}
+	if k < 1 {
+		return 0, nil
+	}
+
+	return math.Sqrt(t.chiSquare() / (float64(t.n) * float64(k))), nil
+}
+
+// contingency is the table of the number of
+// observations of every category and class.
+type contingency struct {
+	cells map[[2]string]int
+	rows  map[string]int
+	cols  map[string]int
+	n     int
+}
+
+func newContingency(values, target []string) *contingency {
+	t := &contingency{
+		cells: make(map[[2]string]int),
+		rows:  make(map[string]int),
+		cols:  make(map[string]int),
+		n:     len(values),
+	}
+	for i, v := range values {
+		t.cells[[2]string{v, target[i]}]++
+		t.rows[v]++
+		t.cols[target[i]]++
+	}
+
+	return t
+}
+
+func (t *contingency) chiSquare() float64 {
+	var statistic float64
+	for row, r := range t.rows {
+		for col, c := range t.cols {
+			expected := float64(r) * float64(c) / float64(t.n)
+			d := float64(t.cells[[2]string{row, col}]) - expected
+			statistic += d * d / expected
+		}
+	}
+
+	return statistic
+}
+
+// upperGamma will return the regularized upper incomplete
+// gamma function Q(a, x), using its series expansion below
+// a+1 and its continued fraction above.
+func upperGamma(a, x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+
+	lnPrefix := a*math.Log(x) - x
+	lgamma, _ := math.Lgamma(a)
+
+	if x < a+1 {
+		sum := 1 / a
+		term := sum
+		for n := 1; n < 1000; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*1e-15 {
+				break
+			}
+		}
+
+		return 1 - sum*math.Exp(lnPrefix-lgamma)
+	}
+
+	// modified Lentz's method
+	const tiny = 1e-300
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < 1000; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+
+	return math.Exp(lnPrefix-lgamma) * h
+}
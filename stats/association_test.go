@@ -0,0 +1,55 @@
+package stats
+
+import (
+	"math"
+	"testing"
+
+	"github.com/humilityai/encoder"
+)
+
+func TestChiSquare(t *testing.T) {
+	values := []string{"a", "a", "a", "a", "b", "b", "b", "b"}
+	target := []string{"x", "x", "x", "y", "y", "y", "y", "x"}
+
+	test, err := ChiSquare(values, target)
+	if err != nil {
+		t.Fatalf("chi-square error: %+v", err)
+	}
+	if math.Abs(test.Statistic-2) > 1e-9 || test.DegreesOfFreedom != 1 {
+		t.Errorf("unexpected test %+v", test)
+	}
+	// P(X > 2) for one degree of freedom
+	if math.Abs(test.PValue-0.157299207050285) > 1e-9 {
+		t.Errorf("p-value was %f", test.PValue)
+	}
+
+	if _, err := ChiSquare(values, target[1:]); err != encoder.ErrTargetLength {
+		t.Error("expected target length error")
+	}
+}
+
+func TestUpperGamma(t *testing.T) {
+	// P(X > 20) for ten degrees of freedom, in the continued fraction
+	if q := upperGamma(5, 10); math.Abs(q-0.029252688076961) > 1e-9 {
+		t.Errorf("upper gamma was %f", q)
+	}
+}
+
+func TestCramersV(t *testing.T) {
+	values := []string{"a", "a", "b", "b", "c", "c"}
+
+	v, err := CramersV(values, []string{"x", "x", "y", "y", "z", "z"})
+	if err != nil || math.Abs(v-1) > 1e-9 {
+		t.Errorf("perfect association was %f", v)
+	}
+
+	v, _ = CramersV(values, []string{"x", "y", "x", "y", "x", "y"})
+	if math.Abs(v) > 1e-9 {
+		t.Errorf("independence was %f", v)
+	}
+
+	v, _ = CramersV(values, []string{"x", "x", "x", "x", "x", "x"})
+	if v != 0 {
+		t.Errorf("constant target was %f", v)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package stats
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/humilityai/encoder"
+)
+
+// FeatureScore is the mutual information
+// between a feature and the target.
+type FeatureScore struct {
+	Feature           string  `json:"feature"`
+	MutualInformation float64 `json:"mutual_information"`
+}
+
+// MutualInformation will return the mutual information, in bits,
+// between the categorical column and the class target: how much
+// knowing the value of the column reduces the uncertainty of the
+// target. An `encoder.ErrTargetLength` error is returned if the
+// target is not the same length as the column.
+func MutualInformation(values, target []string) (float64, error) {
+	if len(target) != len(values) {
+		return 0, encoder.ErrTargetLength
+	}
+
+	t := newContingency(values, target)
+
+	var mi float64
+	for cell, count := range t.cells {
+		joint := float64(count) / float64(t.n)
+		independent := float64(t.rows[cell[0]]) * float64(t.cols[cell[1]]) / float64(t.n*t.n)
+		mi += joint * math.Log2(joint/independent)
+	}
+
+	// rounding can leave independent columns slightly negative
+	return math.Max(mi, 0), nil
+}
+
+// Discretize will assign every value of a numeric target to
+// one of `bins` bins holding roughly the same number of values
+// (equal-frequency binning), so that numeric targets can be
+// scored with MutualInformation. Equal values share a bin.
+// Bins are named by their index.
+func Discretize(target []float64, bins int) []string {
+	labels := make([]string, len(target), len(target))
+	if bins < 1 || len(target) == 0 {
+		return labels
+	}
+
+	order := make([]int, len(target), len(target))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return target[order[i]] < target[order[j]] })
+
+	for rank, i := range order {
+		bin := rank * bins / len(target)
+		// keep ties in the bin of their first occurrence
+		if rank > 0 && target[i] == target[order[rank-1]] {
+			labels[i] = labels[order[rank-1]]
+			continue
+		}
+		labels[i] = strconv.Itoa(bin)
+	}
+
+	return labels
+}
+
+// RankFeatures will score every categorical column by its
+// mutual information with the class target, highest first,
+// so that candidate features can be chosen before deciding
+// how to encode them. An `encoder.ErrTargetLength` error is
+// returned if a column is not the same length as the target.
+func RankFeatures(columns map[string][]string, target []string) ([]FeatureScore, error) {
+	scores := make([]FeatureScore, 0, len(columns))
+	for feature, values := range columns {
+		mi, err := MutualInformation(values, target)
+		if err != nil {
+			return nil, err
+		}
+		scores = append(scores, FeatureScore{Feature: feature, MutualInformation: mi})
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].MutualInformation != scores[j].MutualInformation {
+			return scores[i].MutualInformation > scores[j].MutualInformation
+		}
+		return scores[i].Feature < scores[j].Feature
+	})
+
+	return scores, nil
+}
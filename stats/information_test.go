@@ -0,0 +1,48 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMutualInformation(t *testing.T) {
+	values := []string{"a", "a", "b", "b"}
+
+	mi, err := MutualInformation(values, []string{"x", "x", "y", "y"})
+	if err != nil || math.Abs(mi-1) > 1e-9 {
+		t.Errorf("determined target was %f bits and not 1", mi)
+	}
+
+	mi, _ = MutualInformation(values, []string{"x", "y", "x", "y"})
+	if mi != 0 {
+		t.Errorf("independent target was %f bits", mi)
+	}
+}
+
+func TestDiscretize(t *testing.T) {
+	labels := Discretize([]float64{5, 1, 3, 3, 9, 7}, 3)
+	expected := []string{"1", "0", "0", "0", "2", "2"}
+	for i := range expected {
+		if labels[i] != expected[i] {
+			t.Fatalf("labels were %v and not %v", labels, expected)
+		}
+	}
+}
+
+func TestRankFeatures(t *testing.T) {
+	target := []string{"x", "x", "y", "y"}
+	scores, err := RankFeatures(map[string][]string{
+		"noise":  {"a", "b", "a", "b"},
+		"signal": {"a", "a", "b", "b"},
+	}, target)
+	if err != nil {
+		t.Fatalf("rank error: %+v", err)
+	}
+	if scores[0].Feature != "signal" || scores[1].Feature != "noise" {
+		t.Errorf("unexpected ranking %v", scores)
+	}
+
+	if _, err := RankFeatures(map[string][]string{"short": {"a"}}, target); err == nil {
+		t.Error("expected target length error")
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// Package stats computes statistics of categorical columns
+// that help choose an encoder for every feature: low
+// cardinality columns suit one-hot encoding, while columns
+// whose cardinality approaches the number of rows are better
+// served by hashing or target-based encoders.
+package stats
+
+import (
+	"math"
+)
+
+// Column describes the distribution of
+// the values of a categorical column.
+type Column struct {
+	// Rows is the number of values in the column.
+	Rows int `json:"rows"`
+	// Cardinality is the number of distinct values.
+	Cardinality int `json:"cardinality"`
+	// CardinalityRatio is the cardinality divided by the rows.
+	CardinalityRatio float64 `json:"cardinality_ratio"`
+	// Entropy is the Shannon entropy of the values in bits.
+	Entropy float64 `json:"entropy"`
+	// NormalizedEntropy is the entropy divided by its maximum
+	// for the cardinality, between 0 and 1.
+	NormalizedEntropy float64 `json:"normalized_entropy"`
+	// Gini is the Gini impurity of the values.
+	Gini float64 `json:"gini"`
+}
+
+// Describe will compute every statistic of the column.
+func Describe(values []string) Column {
+	counts := Counts(values)
+
+	c := Column{
+		Rows:             len(values),
+		Cardinality:      len(counts),
+		CardinalityRatio: ratio(len(counts), len(values)),
+		Entropy:          entropy(counts, len(values)),
+		Gini:             gini(counts, len(values)),
+	}
+	if c.Cardinality > 1 {
+		c.NormalizedEntropy = c.Entropy / math.Log2(float64(c.Cardinality))
+	}
+
+	return c
+}
+
+// Counts will return the number of
+// occurrences of every value.
+func Counts(values []string) map[string]int {
+	counts := make(map[string]int)
+	for _, v := range values {
+		counts[v]++
+	}
+
+	return counts
+}
+
+// Entropy will return the Shannon entropy
+// of the values in bits.
+func Entropy(values []string) float64 {
+	return entropy(Counts(values), len(values))
+}
+
+// Gini will return the Gini impurity of the values: the
+// probability that two values drawn at random with
+// replacement are different.
+func Gini(values []string) float64 {
+	return gini(Counts(values), len(values))
+}
+
+// CardinalityRatio will return the number of distinct
+// values divided by the number of values, or 0 for an
+// empty column.
+func CardinalityRatio(values []string) float64 {
+	return ratio(len(Counts(values)), len(values))
+}
+
+func entropy(counts map[string]int, n int) float64 {
+	var h float64
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		h -= p * math.Log2(p)
+	}
+
+	return h
+}
+
+func gini(counts map[string]int, n int) float64 {
+	if n == 0 {
+		return 0
+	}
+
+	impurity := 1.0
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		impurity -= p * p
+	}
+
+	return impurity
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+
+	return float64(a) / float64(b)
+}
@@ -0,0 +1,31 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	c := Describe([]string{"a", "a", "b", "c"})
+	if c.Rows != 4 || c.Cardinality != 3 || c.CardinalityRatio != 0.75 {
+		t.Errorf("unexpected cardinality %+v", c)
+	}
+	if math.Abs(c.Entropy-1.5) > 1e-9 {
+		t.Errorf("entropy was %f and not 1.5", c.Entropy)
+	}
+	if math.Abs(c.Gini-0.625) > 1e-9 {
+		t.Errorf("gini was %f and not 0.625", c.Gini)
+	}
+	if math.Abs(c.NormalizedEntropy-1.5/math.Log2(3)) > 1e-9 {
+		t.Errorf("unexpected normalized entropy %f", c.NormalizedEntropy)
+	}
+
+	constant := Describe([]string{"a", "a"})
+	if constant.Entropy != 0 || constant.Gini != 0 || constant.NormalizedEntropy != 0 {
+		t.Errorf("constant column should have no entropy or impurity %+v", constant)
+	}
+
+	if empty := Describe(nil); empty.CardinalityRatio != 0 || empty.Gini != 0 {
+		t.Errorf("unexpected empty column statistics %+v", empty)
+	}
+}
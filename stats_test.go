@@ -0,0 +1,30 @@
+package encoder
+
+import (
+	"testing"
+)
+
+func TestOrdinalStats(t *testing.T) {
+	encoder := NewOrdinal(true)
+	encoder.Encode("hello world")
+
+	stats := encoder.Stats()
+	if stats.Cardinality != 2 {
+		t.Errorf("cardinality was %d and not 2", stats.Cardinality)
+	}
+	if stats.SizeBytes <= len("hello world") {
+		t.Errorf("size was %d bytes", stats.SizeBytes)
+	}
+	if stats.Updated.Before(stats.Created) {
+		t.Error("updated time was before created time")
+	}
+}
+
+func TestFrequencyStats(t *testing.T) {
+	encoder := NewFrequency([]string{"a", "b", "b", "c", "c", "c"})
+
+	top := encoder.Stats().Top
+	if len(top) != 3 || top[0].Value != "c" || top[0].Count != 3 {
+		t.Errorf("top categories were %v", top)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Storage reads and writes encoder snapshots at URLs of the
+// schemes it is registered for. Backends for object stores,
+// such as S3 or GCS, can be added with RegisterStorage so that
+// SaveURL and LoadURL accept their URLs.
+type Storage interface {
+	Put(ctx context.Context, u *url.URL, r io.Reader) error
+	Get(ctx context.Context, u *url.URL) (io.ReadCloser, error)
+}
+
+var (
+	storages = map[string]Storage{
+		"":     fileStorage{},
+		"file": fileStorage{},
+	}
+	storagesMu = &sync.RWMutex{}
+)
+
+// RegisterStorage will make URLs with the given scheme
+// (e.g. "s3" or "gs") loadable and savable through the
+// storage, replacing any storage registered for it.
+func RegisterStorage(scheme string, s Storage) {
+	storagesMu.Lock()
+	defer storagesMu.Unlock()
+
+	storages[scheme] = s
+}
+
+// SaveURL will write the gzip compressed gob snapshot of the
+// encoder to the URL, e.g. "file:///models/city.gob.gz" or
+// "s3://bucket/key" once a storage is registered for "s3".
+// URLs without a scheme are treated as file paths. An
+// `ErrStorage` error is returned if no storage is registered
+// for the scheme.
+func (e *Ordinal) SaveURL(ctx context.Context, rawurl string) error {
+	u, s, err := lookupStorage(rawurl)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	err = e.SaveCompressed(&buf, Gzip)
+	if err != nil {
+		return err
+	}
+
+	return s.Put(ctx, u, &buf)
+}
+
+// LoadURL will replace the contents of the encoder with the
+// snapshot read from the URL. See SaveURL and LoadCompressed.
+func (e *Ordinal) LoadURL(ctx context.Context, rawurl string) error {
+	u, s, err := lookupStorage(rawurl)
+	if err != nil {
+		return err
+	}
+
+	r, err := s.Get(ctx, u)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	return e.LoadCompressed(r)
+}
+
+func lookupStorage(rawurl string) (*url.URL, Storage, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	storagesMu.RLock()
+	s, ok := storages[u.Scheme]
+	storagesMu.RUnlock()
+	if !ok {
+		return nil, nil, ErrStorage
+	}
+
+	return u, s, nil
+}
+
+// fileStorage stores snapshots on the local file system.
+// Snapshots are written to a temporary file that is renamed
+// into place, so readers never see a partial snapshot.
+type fileStorage struct{}
+
+func (fileStorage) Put(ctx context.Context, u *url.URL, r io.Reader) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	path := filePath(u)
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, r)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+func (fileStorage) Get(ctx context.Context, u *url.URL) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return os.Open(filePath(u))
+}
+
+// filePath will return the local path of a "file" URL
+// or of a URL without a scheme.
+func filePath(u *url.URL) string {
+	if u.Scheme == "" || u.Host == "" || u.Host == "localhost" {
+		return filepath.FromSlash(u.Path)
+	}
+
+	// e.g. file://models/city.gob.gz
+	return filepath.FromSlash(u.Host + u.Path)
+}
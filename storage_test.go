@@ -0,0 +1,72 @@
+package encoder
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type memoryStorage map[string][]byte
+
+func (m memoryStorage) Put(ctx context.Context, u *url.URL, r io.Reader) error {
+	data, err := ioutil.ReadAll(r)
+	m[u.Host+u.Path] = data
+	return err
+}
+
+func (m memoryStorage) Get(ctx context.Context, u *url.URL) (io.ReadCloser, error) {
+	data, ok := m[u.Host+u.Path]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+func TestOrdinalSaveURL(t *testing.T) {
+	ctx := context.Background()
+	e := NewOrdinal(true)
+	e.EncodeSlice([]string{"paris", "tokyo", "lima"})
+
+	dir, err := ioutil.TempDir("", "encoder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	urls := []string{
+		"file://" + filepath.ToSlash(filepath.Join(dir, "a.gob.gz")),
+		filepath.Join(dir, "b.gob.gz"),
+		"mem://bucket/models/c.gob.gz",
+	}
+	RegisterStorage("mem", memoryStorage{})
+	for _, u := range urls {
+		err := e.SaveURL(ctx, u)
+		if err != nil {
+			t.Fatalf("save %s error: %+v", u, err)
+		}
+
+		loaded := NewOrdinal(false)
+		err = loaded.LoadURL(ctx, u)
+		if err != nil {
+			t.Fatalf("load %s error: %+v", u, err)
+		}
+		if loaded.Length() != e.Length() || loaded.Encode("lima") != e.Encode("lima") {
+			t.Errorf("%s loaded %v and not %v", u, loaded.List(), e.List())
+		}
+	}
+
+	if err := e.SaveURL(ctx, "ftp://host/key"); err != ErrStorage {
+		t.Errorf("expected storage error and not %v", err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := e.SaveURL(cancelled, urls[0]); err != context.Canceled {
+		t.Errorf("expected cancellation error and not %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"context"
+	"math"
+	"strconv"
+)
+
+// TransformStream will encode every row received from `in` into
+// features, one Transformer per column, and send them in order on
+// the returned channel, which is closed once `in` is closed or the
+// context is done. Columns without a Transformer (nil) are parsed
+// as numbers, or NaN if they are not, and columns missing from a
+// row are encoded as the empty string.
+// At most `buffer` encoded rows are held waiting to be received;
+// once the buffer is full no more rows are read from `in` until
+// the consumer catches up.
+func TransformStream(ctx context.Context, in <-chan []string, columns []Transformer, buffer int) <-chan []float64 {
+	if buffer < 0 {
+		buffer = 0
+	}
+	out := make(chan []float64, buffer)
+
+	go func() {
+		defer close(out)
+		for {
+			var row []string
+			var ok bool
+			select {
+			case row, ok = <-in:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case out <- transformRow(row, columns):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// transformRow will return the features of every
+// column of the row in the order of the columns.
+func transformRow(row []string, columns []Transformer) []float64 {
+	features := make([]float64, 0, len(columns))
+	for i, e := range columns {
+		var field string
+		if i < len(row) {
+			field = row[i]
+		}
+
+		if e != nil {
+			features = append(features, e.Transform(field)...)
+			continue
+		}
+
+		v, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			v = math.NaN()
+		}
+		features = append(features, v)
+	}
+
+	return features
+}
@@ -0,0 +1,50 @@
+package encoder
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestTransformStream(t *testing.T) {
+	colors := NewOrdinal(true)
+	in := make(chan []string)
+	out := TransformStream(context.Background(), in, []Transformer{colors, nil}, 2)
+
+	go func() {
+		in <- []string{"red", "1.5"}
+		in <- []string{"blue", "x"}
+		in <- []string{"red"}
+		close(in)
+	}()
+
+	var rows [][]float64
+	for row := range out {
+		rows = append(rows, row)
+	}
+
+	if len(rows) != 3 {
+		t.Fatalf("received %d rows and not 3", len(rows))
+	}
+	if rows[0][0] != 1 || rows[0][1] != 1.5 || rows[1][0] != 2 || !math.IsNaN(rows[1][1]) || rows[2][0] != 1 {
+		t.Errorf("unexpected rows %v", rows)
+	}
+}
+
+func TestTransformStreamCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan []string, 100)
+	for i := 0; i < 100; i++ {
+		in <- []string{"1"}
+	}
+
+	out := TransformStream(ctx, in, []Transformer{nil}, 1)
+	<-out
+	cancel()
+
+	for range out {
+	}
+	if len(in) == 0 {
+		t.Error("stream kept reading after cancellation")
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// StructEncoder will encode structs into feature vectors,
+// fitting an encoder for every field tagged with the kind of
+// encoder to apply, e.g.
+//
+//	type Visit struct {
+//		Country  string  `encoder:"onehot"`
+//		Browser  string  `encoder:"ordinal"`
+//		Duration float64 `encoder:"minmax"`
+//	}
+//
+// The kinds are those of ColumnKind. Untagged and unexported
+// fields, and fields tagged `encoder:"-"`, are ignored. Field
+// values are encoded as formatted by `fmt.Sprint`.
+type StructEncoder struct {
+	typ     reflect.Type
+	fields  []int
+	names   []string
+	columns []*column
+}
+
+// NewStructEncoder will fit an encoder for every tagged field of
+// the structs in `rows`, which must be a slice of structs or of
+// pointers to structs. An `ErrDType` error is returned for any
+// other type and an `ErrUnsupported` error for an unknown tag.
+func NewStructEncoder(rows interface{}) (*StructEncoder, error) {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice {
+		return &StructEncoder{}, ErrDType
+	}
+	typ := v.Type().Elem()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return &StructEncoder{}, ErrDType
+	}
+
+	e := &StructEncoder{
+		typ: typ,
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		tag := f.Tag.Get("encoder")
+		if tag == "" || tag == "-" || f.PkgPath != "" {
+			continue
+		}
+
+		values := make([]string, v.Len(), v.Len())
+		for r := range values {
+			row, ok := structValue(v.Index(r), typ)
+			if !ok {
+				return &StructEncoder{}, ErrDType
+			}
+			values[r] = fmt.Sprint(row.Field(i).Interface())
+		}
+
+		c, err := fitColumn(ColumnKind(tag), values)
+		if err != nil {
+			return &StructEncoder{}, err
+		}
+
+		e.fields = append(e.fields, i)
+		e.names = append(e.names, f.Name)
+		e.columns = append(e.columns, c)
+	}
+
+	return e, nil
+}
+
+// structValue will return the struct held by `v`, dereferencing
+// a pointer, and whether it is a struct of the given type.
+func structValue(v reflect.Value, typ reflect.Type) (reflect.Value, bool) {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return v, false
+		}
+		v = v.Elem()
+	}
+
+	return v, v.IsValid() && v.Type() == typ
+}
+
+// Transform will return the features of the struct, or of the
+// struct pointed to, in the order of its tagged fields. An
+// `ErrDType` error is returned if it is not of the struct type
+// the encoder was fitted to.
+func (e *StructEncoder) Transform(row interface{}) ([]float64, error) {
+	v, ok := structValue(reflect.ValueOf(row), e.typ)
+	if !ok {
+		return []float64{}, ErrDType
+	}
+
+	features := make([]float64, 0, e.Dimension())
+	for i, field := range e.fields {
+		features = e.columns[i].transform(features, fmt.Sprint(v.Field(field).Interface()))
+	}
+
+	return features, nil
+}
+
+// Dimension will return the number of features.
+func (e *StructEncoder) Dimension() int {
+	var dimension int
+	for _, c := range e.columns {
+		dimension += c.dimension()
+	}
+
+	return dimension
+}
+
+// FeatureNames will return a name for every feature, in order,
+// named after the fields: `Field=value` for every value of a
+// one-hot field and the name of the field otherwise.
+func (e *StructEncoder) FeatureNames() []string {
+	names := make([]string, 0, e.Dimension())
+	for i, c := range e.columns {
+		names = append(names, c.featureNames(e.names[i])...)
+	}
+
+	return names
+}
@@ -0,0 +1,67 @@
+package encoder
+
+import (
+	"math"
+	"testing"
+)
+
+type visit struct {
+	Country  string  `encoder:"onehot"`
+	Browser  string  `encoder:"ordinal"`
+	Duration float64 `encoder:"minmax"`
+	Session  string
+	Ignored  int `encoder:"-"`
+}
+
+func TestStructEncoder(t *testing.T) {
+	visits := []visit{
+		{Country: "US", Browser: "safari", Duration: 10},
+		{Country: "FR", Browser: "chrome", Duration: 30},
+		{Country: "US", Browser: "chrome", Duration: 20},
+	}
+
+	e, err := NewStructEncoder(visits)
+	if err != nil {
+		t.Fatalf("fit error: %+v", err)
+	}
+
+	names := e.FeatureNames()
+	expected := []string{"Country=", "Country=US", "Country=FR", "Browser", "Duration"}
+	if len(names) != len(expected) || e.Dimension() != len(expected) {
+		t.Fatalf("feature names were %v and not %v", names, expected)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("feature names were %v and not %v", names, expected)
+		}
+	}
+
+	features, err := e.Transform(&visit{Country: "FR", Browser: "chrome", Duration: 25})
+	if err != nil {
+		t.Fatalf("transform error: %+v", err)
+	}
+	want := []float64{0, 0, 1, 1, 0.75}
+	for i := range want {
+		if features[i] != want[i] {
+			t.Errorf("features were %v and not %v", features, want)
+		}
+	}
+
+	unseen, _ := e.Transform(visit{Country: "DE", Browser: "edge"})
+	if unseen[1] != 0 || unseen[2] != 0 || !math.IsNaN(unseen[3]) {
+		t.Errorf("unexpected features for unseen values %v", unseen)
+	}
+
+	if _, err := e.Transform(struct{}{}); err != ErrDType {
+		t.Error("expected dtype error")
+	}
+	if _, err := NewStructEncoder([]string{"a"}); err != ErrDType {
+		t.Error("expected dtype error")
+	}
+	type bad struct {
+		Name string `encoder:"fancy"`
+	}
+	if _, err := NewStructEncoder([]bad{{}}); err != ErrUnsupported {
+		t.Error("expected unsupported error")
+	}
+}
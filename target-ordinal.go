@@ -0,0 +1,58 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"sort"
+)
+
+// NewTargetOrdinal will create an ordinal encoder whose codes
+// are assigned to the categories in ascending order of their
+// mean target, ties broken by value, so that a single column
+// of codes is monotone in the target for tree models. Values
+// encoded after construction receive the next codes, which
+// are not ordered by target.
+func NewTargetOrdinal(values []string, target []float64, opts ...OrdinalOption) (*Ordinal, error) {
+	if len(target) != len(values) {
+		return NewOrdinal(false, opts...), ErrTargetLength
+	}
+
+	e := NewOrdinal(false, opts...)
+
+	sums := make(map[string]float64)
+	counts := make(map[string]float64)
+	for i, v := range values {
+		v = e.prepare(v)
+		sums[v] += target[i]
+		counts[v]++
+	}
+
+	categories := make([]string, 0, len(counts))
+	means := make(map[string]float64, len(counts))
+	for v, n := range counts {
+		categories = append(categories, v)
+		means[v] = sums[v] / n
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		a, b := categories[i], categories[j]
+		if means[a] != means[b] {
+			return means[a] < means[b]
+		}
+		return a < b
+	})
+
+	e.EncodeSlice(categories)
+
+	return e, nil
+}
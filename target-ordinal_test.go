@@ -0,0 +1,23 @@
+package encoder
+
+import (
+	"testing"
+)
+
+func TestTargetOrdinal(t *testing.T) {
+	values := []string{"a", "b", "c", "a", "b", "c", "NA"}
+	target := []float64{5, 1, 3, 7, 1, 3, 0}
+
+	encoder, err := NewTargetOrdinal(values, target, WithMissing(DefaultMissing))
+	if err != nil {
+		t.Fatalf("encoder error: %+v", err)
+	}
+
+	if encoder.Encode("NA") != MissingCode || encoder.Encode("b") != 1 || encoder.Encode("c") != 2 || encoder.Encode("a") != 3 {
+		t.Errorf("codes were not ordered by target: %v", encoder.List())
+	}
+
+	if _, err := NewTargetOrdinal(values, target[1:]); err != ErrTargetLength {
+		t.Error("expected target length error")
+	}
+}
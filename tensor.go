@@ -0,0 +1,167 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"github.com/humilityai/sam"
+)
+
+// Float64Tensor is a dense tensor of float64 elements stored
+// in row-major order. It has the layout of Gorgonia's
+// `tensor.Dense`, so it can be wrapped without a copy:
+// `tensor.New(tensor.WithShape(t.Shape...), tensor.WithBacking(t.Data))`.
+type Float64Tensor struct {
+	Shape []int
+	Data  []float64
+}
+
+// Float32Tensor is a dense tensor of float32
+// elements stored in row-major order.
+type Float32Tensor struct {
+	Shape []int
+	Data  []float32
+}
+
+// Uint8Tensor is a dense tensor of uint8
+// elements stored in row-major order.
+type Uint8Tensor struct {
+	Shape []int
+	Data  []uint8
+}
+
+// Vector is implemented by encoders that encode every
+// value as the same number of features, such as BaseN,
+// Thermometer and ReadOnlyOneHot.
+type Vector interface {
+	Transformer
+	Dimension() int
+}
+
+// Float64Tensor will return the codewords of every value
+// in the slice of strings as a `len(s)` x `Dimension()` tensor.
+func (e *OneHot) Float64Tensor(s sam.SliceString) *Float64Tensor {
+	t := &Float64Tensor{Shape: []int{len(s), e.Dimension()}}
+	t.Data = make([]float64, t.Shape[0]*t.Shape[1])
+
+	rows, cols := e.Indices(s)
+	for i := range rows {
+		t.Data[rows[i]*t.Shape[1]+cols[i]] = 1
+	}
+
+	return t
+}
+
+// Float32Tensor will return the codewords of every value
+// in the slice of strings as a `len(s)` x `Dimension()` tensor.
+func (e *OneHot) Float32Tensor(s sam.SliceString) *Float32Tensor {
+	t := &Float32Tensor{Shape: []int{len(s), e.Dimension()}}
+	t.Data = make([]float32, t.Shape[0]*t.Shape[1])
+
+	rows, cols := e.Indices(s)
+	for i := range rows {
+		t.Data[rows[i]*t.Shape[1]+cols[i]] = 1
+	}
+
+	return t
+}
+
+// Uint8Tensor will return the codewords of every value
+// in the slice of strings as a `len(s)` x `Dimension()` tensor.
+func (e *OneHot) Uint8Tensor(s sam.SliceString) *Uint8Tensor {
+	t := &Uint8Tensor{Shape: []int{len(s), e.Dimension()}}
+	t.Data = make([]uint8, t.Shape[0]*t.Shape[1])
+
+	rows, cols := e.Indices(s)
+	for i := range rows {
+		t.Data[rows[i]*t.Shape[1]+cols[i]] = 1
+	}
+
+	return t
+}
+
+// Float64Tensor will return the features of the rows as a
+// `len(rows)` x `Dimension()` tensor, written in place.
+// An `ErrLength` error is returned if a row does not have
+// one value per fitted column.
+func (d *Dataset) Float64Tensor(rows [][]string) (*Float64Tensor, error) {
+	t := &Float64Tensor{Shape: []int{len(rows), d.width}}
+	t.Data = make([]float64, t.Shape[0]*t.Shape[1])
+
+	for i, row := range rows {
+		_, err := d.AppendRow(t.Data[i*d.width:i*d.width:(i+1)*d.width], row)
+		if err != nil {
+			return &Float64Tensor{}, err
+		}
+	}
+
+	return t, nil
+}
+
+// Float32Tensor will return the features of the rows as a
+// `len(rows)` x `Dimension()` tensor. An `ErrLength` error is
+// returned if a row does not have one value per fitted column.
+func (d *Dataset) Float32Tensor(rows [][]string) (*Float32Tensor, error) {
+	t := &Float32Tensor{Shape: []int{len(rows), d.width}}
+	t.Data = make([]float32, t.Shape[0]*t.Shape[1])
+
+	features := make([]float64, 0, d.width)
+	for i, row := range rows {
+		var err error
+		features, err = d.AppendRow(features[:0], row)
+		if err != nil {
+			return &Float32Tensor{}, err
+		}
+
+		for j, v := range features {
+			t.Data[i*d.width+j] = float32(v)
+		}
+	}
+
+	return t, nil
+}
+
+// NewFloat64Tensor will return the features of every value in
+// the slice of strings as a `len(s)` x `Dimension()` tensor.
+// The dimension is taken before any value is encoded, so
+// encoders whose dimension grows with new values, such as
+// OneHot, should use their own tensor methods instead.
+func NewFloat64Tensor(e Vector, s sam.SliceString) *Float64Tensor {
+	t := &Float64Tensor{Shape: []int{len(s), e.Dimension()}}
+	t.Data = make([]float64, t.Shape[0]*t.Shape[1])
+
+	for i, v := range s {
+		copy(t.Data[i*t.Shape[1]:(i+1)*t.Shape[1]], e.Transform(v))
+	}
+
+	return t
+}
+
+// NewFloat32Tensor will return the features of every value in
+// the slice of strings as a `len(s)` x `Dimension()` tensor,
+// with the dimension taken as in NewFloat64Tensor.
+func NewFloat32Tensor(e Vector, s sam.SliceString) *Float32Tensor {
+	t := &Float32Tensor{Shape: []int{len(s), e.Dimension()}}
+	t.Data = make([]float32, t.Shape[0]*t.Shape[1])
+
+	for i, v := range s {
+		row := t.Data[i*t.Shape[1] : (i+1)*t.Shape[1]]
+		for j, f := range e.Transform(v) {
+			if j < len(row) {
+				row[j] = float32(f)
+			}
+		}
+	}
+
+	return t
+}
@@ -0,0 +1,63 @@
+package encoder
+
+import (
+	"testing"
+)
+
+func TestOneHotTensor(t *testing.T) {
+	encoder := NewOneHot()
+	encoder.Encode("red")
+
+	tensor := encoder.Float32Tensor([]string{"red", ""})
+	if tensor.Shape[0] != 2 || tensor.Shape[1] != 2 || tensor.Data[1] != 1 || tensor.Data[2] != 1 {
+		t.Errorf("tensor was %v with shape %v", tensor.Data, tensor.Shape)
+	}
+
+	bytes := encoder.Uint8Tensor([]string{"red"})
+	if len(bytes.Data) != 2 || bytes.Data[1] != 1 {
+		t.Errorf("tensor was %v with shape %v", bytes.Data, bytes.Shape)
+	}
+}
+
+func TestDatasetTensor(t *testing.T) {
+	var d Dataset
+	err := d.Fit([][]string{{"red", "1"}, {"blue", "3"}}, []ColumnKind{KindOneHot, KindMinMax})
+	if err != nil {
+		t.Fatalf("fit error: %+v", err)
+	}
+
+	tensor, err := d.Float64Tensor([][]string{{"blue", "2"}, {"red", "3"}})
+	if err != nil {
+		t.Fatalf("tensor error: %+v", err)
+	}
+
+	expected := []float64{0, 0, 1, 0.5, 0, 1, 0, 1}
+	if tensor.Shape[0] != 2 || tensor.Shape[1] != 4 {
+		t.Fatalf("tensor shape was %v and not [2 4]", tensor.Shape)
+	}
+	for i, v := range expected {
+		if tensor.Data[i] != v {
+			t.Fatalf("tensor was %v and not %v", tensor.Data, expected)
+		}
+	}
+
+	_, err = d.Float32Tensor([][]string{{"red"}})
+	if err != ErrLength {
+		t.Error("expected length error")
+	}
+}
+
+func TestVectorTensor(t *testing.T) {
+	encoder, err := NewThermometer([]string{"low", "medium", "high"})
+	if err != nil {
+		t.Fatalf("new thermometer error: %+v", err)
+	}
+
+	tensor := NewFloat32Tensor(encoder, []string{"high", "low"})
+	if tensor.Shape[1] != encoder.Dimension() {
+		t.Fatalf("tensor shape was %v", tensor.Shape)
+	}
+	if tensor.Data[0] != 1 || tensor.Data[1] != 1 || tensor.Data[tensor.Shape[1]] != 0 {
+		t.Errorf("tensor was %v", tensor.Data)
+	}
+}
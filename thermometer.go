@@ -0,0 +1,134 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import "encoding/json"
+
+// Thermometer will encode ordered categories, such as ratings,
+// into cumulative binary codewords: the k-th category (from 0)
+// is encoded as k leading 1s followed by 0s, so codewords of
+// nearby categories differ in few dimensions. Codewords have
+// one dimension less than the number of categories, the first
+// category being the all-zeros codeword.
+type Thermometer struct {
+	encoder map[string]int
+	decoder []string
+}
+
+// NewThermometer will create a Thermometer encoder for the
+// categories in the given order. An `ErrDuplicateValue` error
+// is returned if a category is repeated.
+func NewThermometer(order []string) (*Thermometer, error) {
+	e := &Thermometer{}
+	return e, e.load(order)
+}
+
+func (e *Thermometer) load(order []string) error {
+	encoder := make(map[string]int, len(order))
+	for i, v := range order {
+		if _, ok := encoder[v]; ok {
+			return ErrDuplicateValue
+		}
+		encoder[v] = i
+	}
+
+	e.encoder = encoder
+	e.decoder = append([]string{}, order...)
+	return nil
+}
+
+// Dimension will return the length of the codewords.
+func (e *Thermometer) Dimension() int {
+	if len(e.decoder) == 0 {
+		return 0
+	}
+
+	return len(e.decoder) - 1
+}
+
+// Categories will return the categories in order.
+func (e *Thermometer) Categories() []string {
+	return append([]string{}, e.decoder...)
+}
+
+// Level will return the position of the category
+// in the ordering and whether it is a category.
+func (e *Thermometer) Level(s string) (int, bool) {
+	level, ok := e.encoder[s]
+	return level, ok
+}
+
+// Encode will return the codeword of the category, or an
+// `ErrUnknownCategory` error if it is not a category.
+func (e *Thermometer) Encode(s string) ([]uint8, error) {
+	level, ok := e.encoder[s]
+	if !ok {
+		return []uint8{}, ErrUnknownCategory
+	}
+
+	code := make([]uint8, e.Dimension(), e.Dimension())
+	for i := 0; i < level; i++ {
+		code[i] = 1
+	}
+
+	return code, nil
+}
+
+// Decode will return the category of the codeword. An `ErrLength`
+// error is returned if the codeword is not `Dimension()` long and an
+// `ErrInvalidCodeword` error if its 1s are not all leading.
+func (e *Thermometer) Decode(code []uint8) (string, error) {
+	if len(code) != e.Dimension() || len(e.decoder) == 0 {
+		return "", ErrLength
+	}
+
+	level := 0
+	for level < len(code) && code[level] == 1 {
+		level++
+	}
+	if containsNonZero(code[level:]) {
+		return "", ErrInvalidCodeword
+	}
+
+	return e.decoder[level], nil
+}
+
+// Transform will return the codeword of the string as
+// features. Strings that are not categories are encoded
+// as the codeword of the first category.
+func (e *Thermometer) Transform(s string) []float64 {
+	features := make([]float64, e.Dimension(), e.Dimension())
+	for i := 0; i < e.encoder[s]; i++ {
+		features[i] = 1
+	}
+
+	return features
+}
+
+// MarshalJSON will encode the categories in order.
+func (e *Thermometer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.decoder)
+}
+
+// UnmarshalJSON will return an `ErrDuplicateValue`
+// error if a category is repeated.
+func (e *Thermometer) UnmarshalJSON(data []byte) error {
+	var order []string
+	err := json.Unmarshal(data, &order)
+	if err != nil {
+		return err
+	}
+
+	return e.load(order)
+}
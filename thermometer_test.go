@@ -0,0 +1,53 @@
+package encoder
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestThermometer(t *testing.T) {
+	e, err := NewThermometer([]string{"poor", "fair", "good", "great"})
+	if err != nil {
+		t.Fatalf("create error: %+v", err)
+	}
+
+	code, err := e.Encode("good")
+	if err != nil {
+		t.Fatalf("encode error: %+v", err)
+	}
+	if len(code) != 3 || code[0] != 1 || code[1] != 1 || code[2] != 0 {
+		t.Errorf("unexpected codeword %v", code)
+	}
+	for _, category := range e.Categories() {
+		code, _ := e.Encode(category)
+		v, err := e.Decode(code)
+		if err != nil || v != category {
+			t.Errorf("%s decoded to %s: %+v", category, v, err)
+		}
+	}
+
+	if _, err := e.Encode("awful"); err != ErrUnknownCategory {
+		t.Error("expected unknown category error")
+	}
+	if _, err := e.Decode([]uint8{1, 0, 1}); err != ErrInvalidCodeword {
+		t.Error("expected invalid codeword error")
+	}
+	if _, err := e.Decode([]uint8{1}); err != ErrLength {
+		t.Error("expected length error")
+	}
+	if _, err := NewThermometer([]string{"a", "a"}); err != ErrDuplicateValue {
+		t.Error("expected duplicate value error")
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	var loaded Thermometer
+	if err := json.Unmarshal(b, &loaded); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	if level, ok := loaded.Level("great"); !ok || level != 3 {
+		t.Error("ordering did not round trip")
+	}
+}
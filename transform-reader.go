@@ -0,0 +1,92 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// NewTransformReader will return a reader of the CSV read from
+// `r` with every column in the spec replaced by its encoded
+// features, as written by TransformCSV, so the encoders can be
+// slotted into io-based ETL code. Rows are encoded as they are
+// read. The reader must be closed if it is not read to the end.
+func NewTransformReader(r io.Reader, spec CSVSpec) io.ReadCloser {
+	return newTransformReader(func(w io.Writer) error {
+		return TransformCSV(context.Background(), r, w, spec)
+	})
+}
+
+// NewJSONLTransformReader will return a reader of the JSON Lines
+// read from `r`, one object per line, with the string value of
+// every field that has a Transformer replaced by the array of its
+// encoded features. Other fields are copied unchanged. The reader
+// must be closed if it is not read to the end.
+func NewJSONLTransformReader(r io.Reader, fields map[string]Transformer) io.ReadCloser {
+	return newTransformReader(func(w io.Writer) error {
+		return transformJSONL(r, w, fields)
+	})
+}
+
+// newTransformReader will return the reading end of a pipe
+// written to by `transform`. Closing the reader stops the
+// transform at its next write.
+func newTransformReader(transform func(w io.Writer) error) io.ReadCloser {
+	pr, pw := io.Pipe()
+
+	go func() {
+		pw.CloseWithError(transform(pw))
+	}()
+
+	return pr
+}
+
+func transformJSONL(r io.Reader, w io.Writer, fields map[string]Transformer) error {
+	dec := json.NewDecoder(bufio.NewReader(r))
+	b := bufio.NewWriter(w)
+	enc := json.NewEncoder(b)
+
+	for {
+		var row map[string]interface{}
+		err := dec.Decode(&row)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		for field, e := range fields {
+			if s, ok := row[field].(string); ok {
+				row[field] = e.Transform(s)
+			}
+		}
+
+		err = enc.Encode(row)
+		if err != nil {
+			return err
+		}
+		if b.Buffered() >= 4096 {
+			err = b.Flush()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return b.Flush()
+}
@@ -0,0 +1,57 @@
+package encoder
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestTransformReader(t *testing.T) {
+	colors := NewOrdinal(true)
+	r := NewTransformReader(strings.NewReader("id,color\n1,red\n2,blue\n3,red\n"), CSVSpec{
+		Columns: map[int]Transformer{1: colors},
+		Header:  true,
+	})
+	defer r.Close()
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read error: %+v", err)
+	}
+	if string(b) != "id,color\n1,1\n2,2\n3,1\n" {
+		t.Errorf("unexpected transformed CSV %q", b)
+	}
+}
+
+func TestJSONLTransformReader(t *testing.T) {
+	colors := NewOneHot()
+	r := NewJSONLTransformReader(strings.NewReader(`{"id":1,"color":"red"}
+{"id":2,"color":"blue"}
+`), map[string]Transformer{"color": colors})
+	defer r.Close()
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read error: %+v", err)
+	}
+	if string(b) != "{\"color\":[0,1],\"id\":1}\n{\"color\":[0,0,1],\"id\":2}\n" {
+		t.Errorf("unexpected transformed JSON Lines %q", b)
+	}
+
+	bad := NewJSONLTransformReader(strings.NewReader("{"), nil)
+	defer bad.Close()
+	if _, err := ioutil.ReadAll(bad); err == nil {
+		t.Error("expected syntax error")
+	}
+}
+
+func TestTransformReaderClose(t *testing.T) {
+	r := NewTransformReader(strings.NewReader(strings.Repeat("a\n", 100000)), CSVSpec{})
+	buf := make([]byte, 10)
+	if _, err := r.Read(buf); err != nil {
+		t.Fatalf("read error: %+v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Errorf("close error: %+v", err)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"math"
+)
+
+// Transformer is implemented by encoders that can encode
+// a single categorical value as one or more numeric features.
+type Transformer interface {
+	Transform(s string) []float64
+}
+
+// Transform will encode the string, adding it to the
+// encoder if needed, and return its code as a single feature.
+func (e *Ordinal) Transform(s string) []float64 {
+	return []float64{float64(e.Encode(s))}
+}
+
+// Transform will encode the string, adding it to the
+// encoder if needed, and return its codeword as features.
+func (e *OneHot) Transform(s string) []float64 {
+	code := e.Encode(s)
+
+	features := make([]float64, len(code), len(code))
+	for i, v := range code {
+		features[i] = float64(v)
+	}
+
+	return features
+}
+
+// Transform will return the hash-derived code
+// of the string as a single feature.
+func (e *Hash) Transform(s string) []float64 {
+	return []float64{float64(e.Encode(s))}
+}
+
+// Transform will return the frequency of the string
+// as a single feature. Unseen strings have frequency 0.
+// Encoders created with WithFrequencySmoothing return
+// the smoothed probability of the string instead.
+func (e *Frequency) Transform(s string) []float64 {
+	if e.smoothing > 0 {
+		return []float64{e.Probability(s)}
+	}
+	v, _ := e.Get(s)
+	return []float64{float64(v)}
+}
+
+// Transform will return the code of the string as a
+// single feature. Unseen strings are encoded as NaN.
+func (e *JamesSteinRegression) Transform(s string) []float64 {
+	v, ok := e.Get(s)
+	if !ok {
+		v = math.NaN()
+	}
+	return []float64{v}
+}
+
+// Transform will return the code of the string as a single
+// feature. Unseen strings are encoded as 0, the mean of the
+// random intercepts.
+func (e *GLMMRegression) Transform(s string) []float64 {
+	v, _ := e.Get(s)
+	return []float64{v}
+}
+
+// Transform will return the code of the string as a single
+// feature. Unseen strings are encoded as 0, the mean of the
+// random intercepts.
+func (e *GLMMClassification) Transform(s string) []float64 {
+	v, _ := e.Get(s)
+	return []float64{v}
+}
+
+// Transform will return the code of the string as a single
+// feature. Unseen strings are encoded as the ratio of a
+// category without observations.
+func (e *ProbabilityRatio) Transform(s string) []float64 {
+	v, ok := e.Get(s)
+	if !ok {
+		v = probabilityRatio(0, 0, e.smoothing)
+	}
+	return []float64{v}
+}
+
+// Transform will return the code of the string as a single
+// feature. Unseen strings are encoded as the log-odds of a
+// category without observations.
+func (e *LogOdds) Transform(s string) []float64 {
+	v, ok := e.Get(s)
+	if !ok {
+		v = math.Log(probabilityRatio(0, 0, e.smoothing))
+	}
+	return []float64{v}
+}
@@ -0,0 +1,368 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// OrdinalEncoder is implemented by every encoder that
+// assigns strings unique integer codes, so that the
+// storage behind the codes can be chosen per vocabulary.
+type OrdinalEncoder interface {
+	Encode(s string) uint64
+	Decode(i uint64) string
+	Contains(s string) bool
+	Length() int
+}
+
+// TrieOrdinal will encode string values into a unique
+// integer value like Ordinal, but stores its vocabulary
+// in a radix tree so that values sharing a prefix, such
+// as URLs and paths, share the memory of that prefix.
+// Values can be iterated by prefix in lexical order.
+// Lookups walk the tree, so they are slower than those
+// of Ordinal for vocabularies without shared prefixes.
+type TrieOrdinal struct {
+	root  *radixNode
+	nodes []*radixNode
+	*sync.RWMutex
+}
+
+// radixNode is a node of a radix tree whose edges are
+// labelled with strings. The value of a node is the
+// concatenation of the labels from the root to it.
+type radixNode struct {
+	label    string
+	parent   *radixNode
+	children []*radixNode
+	code     uint64
+	terminal bool
+}
+
+// NewTrieOrdinal will create a new trie-backed ordinal
+// encoder. If the `init` boolean is specified as true,
+// then the encoder will intialize with the empty
+// string `""` encoded as the `0` value.
+func NewTrieOrdinal(init bool) *TrieOrdinal {
+	e := &TrieOrdinal{
+		root:    &radixNode{},
+		nodes:   make([]*radixNode, 0),
+		RWMutex: &sync.RWMutex{},
+	}
+
+	if init {
+		e.Encode("")
+	}
+
+	return e
+}
+
+// Encode will return the code of the given string,
+// assigning it the next code if it has none.
+func (e *TrieOrdinal) Encode(s string) uint64 {
+	e.Lock()
+	defer e.Unlock()
+
+	return e.encode(s)
+}
+
+func (e *TrieOrdinal) encode(s string) uint64 {
+	node := e.insert(s)
+	if !node.terminal {
+		node.terminal = true
+		node.code = uint64(len(e.nodes))
+		e.nodes = append(e.nodes, node)
+	}
+
+	return node.code
+}
+
+// insert will return the node of the string,
+// adding it to the tree if needed.
+func (e *TrieOrdinal) insert(s string) *radixNode {
+	node := e.root
+	for len(s) > 0 {
+		i := node.index(s[0])
+		if i == len(node.children) || node.children[i].label[0] != s[0] {
+			// copy the label so the tree does
+			// not hold on to the caller's string
+			leaf := &radixNode{label: string([]byte(s)), parent: node}
+			node.children = append(node.children, nil)
+			copy(node.children[i+1:], node.children[i:])
+			node.children[i] = leaf
+			node = leaf
+			break
+		}
+
+		child := node.children[i]
+		common := commonPrefixLength(child.label, s)
+		if common < len(child.label) {
+			mid := &radixNode{
+				label:    child.label[:common],
+				parent:   node,
+				children: []*radixNode{child},
+			}
+			child.label = child.label[common:]
+			child.parent = mid
+			node.children[i] = mid
+			child = mid
+		}
+
+		node = child
+		s = s[common:]
+	}
+
+	return node
+}
+
+// EncodeSlice will encode all the values in the slice of strings
+// provided as an argument.
+func (e *TrieOrdinal) EncodeSlice(s []string) []uint64 {
+	e.Lock()
+	defer e.Unlock()
+
+	codes := make([]uint64, len(s), len(s))
+	for i, v := range s {
+		codes[i] = e.encode(v)
+	}
+
+	return codes
+}
+
+// Decode will return an empty string if supplied integer
+// argument is not a valid code.
+func (e *TrieOrdinal) Decode(i uint64) string {
+	value, _ := e.DecodeChecked(i)
+	return value
+}
+
+// DecodeChecked will return the string for the given code,
+// or an `ErrBounds` error if the code is not a valid code.
+func (e *TrieOrdinal) DecodeChecked(i uint64) (string, error) {
+	e.RLock()
+	defer e.RUnlock()
+
+	if i >= uint64(len(e.nodes)) {
+		return "", ErrBounds
+	}
+
+	return e.nodes[i].value(), nil
+}
+
+// Contains will return whether or not a string
+// has been assigned an ordinal code or not.
+func (e *TrieOrdinal) Contains(s string) bool {
+	e.RLock()
+	defer e.RUnlock()
+
+	node := e.root
+	for len(s) > 0 {
+		i := node.index(s[0])
+		if i == len(node.children) || !strings.HasPrefix(s, node.children[i].label) {
+			return false
+		}
+
+		node = node.children[i]
+		s = s[len(node.label):]
+	}
+
+	return node.terminal
+}
+
+// Length will return the number of encoded values.
+func (e *TrieOrdinal) Length() int {
+	e.RLock()
+	defer e.RUnlock()
+
+	return len(e.nodes)
+}
+
+// List will return a copy of every encoded
+// value, indexed by code.
+func (e *TrieOrdinal) List() []string {
+	e.RLock()
+	defer e.RUnlock()
+
+	values := make([]string, len(e.nodes), len(e.nodes))
+	for code, node := range e.nodes {
+		values[code] = node.value()
+	}
+
+	return values
+}
+
+// RangePrefix will call `f` with the code and value of every
+// encoded value beginning with the prefix, in lexical order
+// of the values, until `f` returns false. The encoder is
+// read-locked while ranging, so `f` must not encode new values.
+func (e *TrieOrdinal) RangePrefix(p string, f func(code uint64, value string) bool) {
+	e.RLock()
+	defer e.RUnlock()
+
+	node := e.root
+	rest := p
+	for len(rest) > 0 {
+		i := node.index(rest[0])
+		if i == len(node.children) {
+			return
+		}
+
+		child := node.children[i]
+		switch {
+		case strings.HasPrefix(rest, child.label):
+			rest = rest[len(child.label):]
+		case strings.HasPrefix(child.label, rest):
+			rest = ""
+		default:
+			return
+		}
+		node = child
+	}
+
+	node.walk([]byte(node.value()), f)
+}
+
+// FindPrefix will return the codes of every encoded
+// value that begins with the given prefix, in
+// ascending code order.
+func (e *TrieOrdinal) FindPrefix(p string) []uint64 {
+	codes := make([]uint64, 0)
+	e.RangePrefix(p, func(code uint64, value string) bool {
+		codes = append(codes, code)
+		return true
+	})
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+
+	return codes
+}
+
+// SizeBytes will return an estimate of the
+// memory held by the encoder in bytes.
+func (e *TrieOrdinal) SizeBytes() int {
+	e.RLock()
+	defer e.RUnlock()
+
+	size := cap(e.nodes) * int(unsafe.Sizeof(e.root))
+	var count func(n *radixNode)
+	count = func(n *radixNode) {
+		size += int(unsafe.Sizeof(*n)) + len(n.label) + cap(n.children)*int(unsafe.Sizeof(n))
+		for _, child := range n.children {
+			count(child)
+		}
+	}
+	count(e.root)
+
+	return size
+}
+
+// MarshalJSON will encode the values as an array
+// indexed by code, the format used by Ordinal.
+func (e *TrieOrdinal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.List())
+}
+
+// UnmarshalJSON will replace the contents of the encoder
+// with the values of the array, in code order. Every
+// position keeps its code, as with Ordinal: a repeated
+// value encodes to its first code, and the codes of its
+// repeats decode to it.
+func (e *TrieOrdinal) UnmarshalJSON(data []byte) error {
+	s := make([]string, 0)
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+
+	e.load(s)
+	return nil
+}
+
+// load will replace the contents of the encoder
+// with the values, giving every value its position
+// as its code.
+func (e *TrieOrdinal) load(values []string) {
+	if e.RWMutex == nil {
+		e.RWMutex = &sync.RWMutex{}
+	}
+
+	e.Lock()
+	defer e.Unlock()
+
+	e.root = &radixNode{}
+	e.nodes = make([]*radixNode, 0, len(values))
+	for _, v := range values {
+		node := e.insert(v)
+		if !node.terminal {
+			node.terminal = true
+			node.code = uint64(len(e.nodes))
+		}
+		e.nodes = append(e.nodes, node)
+	}
+}
+
+// index will return the position of the child whose
+// label begins with b, or where it would be inserted.
+func (n *radixNode) index(b byte) int {
+	return sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].label[0] >= b
+	})
+}
+
+// value will return the concatenation of the
+// labels from the root of the tree to the node.
+func (n *radixNode) value() string {
+	var length int
+	for node := n; node != nil; node = node.parent {
+		length += len(node.label)
+	}
+
+	b := make([]byte, length, length)
+	for node := n; node != nil; node = node.parent {
+		length -= len(node.label)
+		copy(b[length:], node.label)
+	}
+
+	return string(b)
+}
+
+// walk will call `f` with every terminal node below the node,
+// in lexical order, where `path` is the value of the node.
+// It returns false once `f` does.
+func (n *radixNode) walk(path []byte, f func(code uint64, value string) bool) bool {
+	if n.terminal && !f(n.code, string(path)) {
+		return false
+	}
+
+	for _, child := range n.children {
+		if !child.walk(append(path, child.label...), f) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func commonPrefixLength(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+
+	return i
+}
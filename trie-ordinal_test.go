@@ -0,0 +1,82 @@
+package encoder
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+var (
+	_ OrdinalEncoder = &Ordinal{}
+	_ OrdinalEncoder = &BoundedOrdinal{}
+	_ OrdinalEncoder = &TrieOrdinal{}
+)
+
+func TestTrieOrdinal(t *testing.T) {
+	encoder := NewTrieOrdinal(true)
+	values := []string{"/api/users", "/api/users/1", "/api", "/static/app.js", "/api/orders"}
+	codes := encoder.EncodeSlice(values)
+
+	for i, v := range values {
+		if codes[i] != uint64(i+1) || encoder.Decode(codes[i]) != v {
+			t.Errorf("%q was encoded as %d and decoded as %q", v, codes[i], encoder.Decode(codes[i]))
+		}
+	}
+	if encoder.Encode("/api/users") != 1 || encoder.Length() != 6 {
+		t.Error("encoding a known value assigned a new code")
+	}
+	if encoder.Contains("/api/use") || !encoder.Contains("") {
+		t.Error("contains did not match whole values")
+	}
+
+	var found []string
+	encoder.RangePrefix("/api/", func(code uint64, value string) bool {
+		found = append(found, value)
+		return true
+	})
+	if len(found) != 3 || found[0] != "/api/orders" || found[1] != "/api/users" || found[2] != "/api/users/1" {
+		t.Errorf("unexpected prefix range %v", found)
+	}
+	if codes := encoder.FindPrefix("/ap"); len(codes) != 4 || codes[0] != 1 {
+		t.Errorf("unexpected prefix codes %v", codes)
+	}
+
+	b, err := json.Marshal(encoder)
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	ordinal := NewOrdinal(false)
+	if err := json.Unmarshal(b, ordinal); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	if ordinal.Encode("/api") != 3 {
+		t.Error("trie encoder json is not compatible with ordinal")
+	}
+
+	loaded := NewTrieOrdinal(false)
+	if err := json.Unmarshal(b, loaded); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	if loaded.Decode(4) != "/static/app.js" || loaded.Length() != 6 {
+		t.Error("trie encoder did not round trip")
+	}
+}
+
+func TestTrieOrdinalRepeatedValues(t *testing.T) {
+	data := []byte(`["", "red", "", "green"]`)
+
+	encoder := NewTrieOrdinal(false)
+	if err := json.Unmarshal(data, encoder); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	if encoder.Encode("green") != 3 || encoder.Encode("") != 0 || encoder.Length() != 4 {
+		t.Error("repeated value shifted the codes after it")
+	}
+
+	b, err := json.Marshal(encoder)
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	if string(b) != `["","red","","green"]` {
+		t.Errorf("round trip was %s", b)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import "sort"
+
+// trie is a byte-wise prefix tree mapping
+// encoded values to their codes.
+type trie struct {
+	root *trieNode
+}
+
+type trieNode struct {
+	children map[byte]*trieNode
+	code     uint64
+	terminal bool
+}
+
+func newTrie() *trie {
+	return &trie{
+		root: &trieNode{},
+	}
+}
+
+// insert will add the value with the given code to the trie.
+func (t *trie) insert(s string, code uint64) {
+	node := t.root
+	for i := 0; i < len(s); i++ {
+		if node.children == nil {
+			node.children = make(map[byte]*trieNode)
+		}
+
+		child, ok := node.children[s[i]]
+		if !ok {
+			child = &trieNode{}
+			node.children[s[i]] = child
+		}
+		node = child
+	}
+
+	node.code = code
+	node.terminal = true
+}
+
+// prefix will return the codes of every value in
+// the trie beginning with p, in ascending order.
+func (t *trie) prefix(p string) []uint64 {
+	codes := make([]uint64, 0)
+
+	node := t.root
+	for i := 0; i < len(p); i++ {
+		child, ok := node.children[p[i]]
+		if !ok {
+			return codes
+		}
+		node = child
+	}
+
+	codes = node.collect(codes)
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+
+	return codes
+}
+
+func (n *trieNode) collect(codes []uint64) []uint64 {
+	if n.terminal {
+		codes = append(codes, n.code)
+	}
+
+	for _, child := range n.children {
+		codes = child.collect(codes)
+	}
+
+	return codes
+}
+
+// remove will remove the value from the trie.
+func (t *trie) remove(s string) {
+	node := t.root
+	for i := 0; i < len(s); i++ {
+		child, ok := node.children[s[i]]
+		if !ok {
+			return
+		}
+		node = child
+	}
+
+	node.terminal = false
+}
@@ -0,0 +1,111 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"sort"
+	"time"
+)
+
+// WithTTL will track when every value was last encoded,
+// so that values not encoded for longer than the ttl can
+// be expired with Sweep.
+func WithTTL(ttl time.Duration) OrdinalOption {
+	return func(e *Ordinal) {
+		e.ttl = ttl
+		e.lastSeen = make(map[uint64]time.Time)
+		e.expired = make(map[uint64]bool)
+	}
+}
+
+// Sweep will expire every value that has not been encoded
+// within the encoder's ttl and return their codes in
+// ascending order.
+// Expired codes are never reassigned: an expired value that
+// is encoded again receives a new code, and expired codes
+// decode to the empty string (DecodeChecked returns an
+// `ErrExpired` error). The memory held by expired values is
+// released. Every expired code is a change that advances the
+// encoder's Version. Sweep does nothing unless the encoder was
+// created with `WithTTL`.
+func (e *Ordinal) Sweep() []uint64 {
+	e.lock()
+	defer e.Unlock()
+
+	codes := make([]uint64, 0)
+	if e.lastSeen == nil {
+		return codes
+	}
+
+	cutoff := time.Now().Add(-e.ttl)
+	removed := make(map[uint64]bool)
+	for code, seen := range e.lastSeen {
+		if e.missing != nil && code == MissingCode {
+			continue
+		}
+
+		if seen.Before(cutoff) {
+			removed[code] = true
+			codes = append(codes, code)
+		}
+	}
+
+	e.expire(removed)
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	for _, code := range codes {
+		e.changed(code)
+	}
+
+	return codes
+}
+
+// expire will release the values of the codes, and their
+// aliases, so that the codes decode as expired. Reserved
+// codes among them are no longer reserved.
+func (e *Ordinal) expire(codes map[uint64]bool) {
+	if len(codes) == 0 {
+		return
+	}
+	if e.expired == nil {
+		e.expired = make(map[uint64]bool)
+	}
+
+	e.dropAliases(codes)
+	for code := range codes {
+		value := e.decoder.get(int(code))
+		if c, ok := e.encoder[hashString(value)]; ok && c == code {
+			delete(e.encoder, hashString(value))
+			if e.trie != nil {
+				e.trie.remove(value)
+			}
+		}
+		delete(e.reserved, code)
+		delete(e.lastSeen, code)
+		delete(e.counts, code)
+		e.expired[code] = true
+	}
+	e.decoder = e.decoder.clear(codes)
+}
+
+// restoreExpired will replace the expired codes of
+// the encoder with those of a loaded vocabulary.
+func (e *Ordinal) restoreExpired(codes []uint64) {
+	if len(codes) == 0 && e.lastSeen == nil {
+		e.expired = nil
+		return
+	}
+
+	e.expired = codeSet(codes)
+}
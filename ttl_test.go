@@ -0,0 +1,86 @@
+package encoder
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestOrdinalSweep(t *testing.T) {
+	encoder := NewOrdinal(false, WithTTL(20*time.Millisecond))
+	stale := encoder.Encode("session-1")
+	time.Sleep(30 * time.Millisecond)
+	fresh := encoder.Encode("session-2")
+	version := encoder.Version()
+
+	expired := encoder.Sweep()
+	if len(expired) != 1 || expired[0] != stale {
+		t.Fatalf("expired codes were %v and not [%d]", expired, stale)
+	}
+	if encoder.Version() != version+1 {
+		t.Errorf("version was %d and not %d after sweep", encoder.Version(), version+1)
+	}
+
+	if encoder.Contains("session-1") || !encoder.Contains("session-2") {
+		t.Error("sweep did not expire only the stale value")
+	}
+	if _, err := encoder.DecodeChecked(stale); err != ErrExpired {
+		t.Errorf("error was %+v and not an expired error", err)
+	}
+	if encoder.Decode(fresh) != "session-2" {
+		t.Error("fresh value did not decode after sweep")
+	}
+
+	if code := encoder.Encode("session-1"); code == stale {
+		t.Error("expired code was reassigned")
+	}
+}
+
+func TestOrdinalSweepSerialized(t *testing.T) {
+	encoder := NewOrdinal(false, WithTTL(20*time.Millisecond))
+	encoder.Encode("session-1")
+	encoder.Encode("session-2")
+	encoder.Encode("session-3")
+	time.Sleep(30 * time.Millisecond)
+	encoder.Encode("session-2")
+
+	expired := encoder.Sweep()
+	if len(expired) != 2 || expired[0] != 0 || expired[1] != 2 {
+		t.Fatalf("expired codes were %v and not [0 2]", expired)
+	}
+
+	data, err := json.Marshal(encoder)
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	restored := NewOrdinal(false)
+	err = json.Unmarshal(data, restored)
+	if err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	if restored.Contains("") {
+		t.Error("expired codes were loaded as the empty string")
+	}
+	if _, err := restored.DecodeChecked(2); err != ErrExpired {
+		t.Errorf("error was %+v and not an expired error", err)
+	}
+	if restored.Version() != encoder.Version() {
+		t.Errorf("version was %d and not %d", restored.Version(), encoder.Version())
+	}
+
+	data, err = encoder.GobEncode()
+	if err != nil {
+		t.Fatalf("gob encode error: %+v", err)
+	}
+	restored = NewOrdinal(false)
+	err = restored.GobDecode(data)
+	if err != nil {
+		t.Fatalf("gob decode error: %+v", err)
+	}
+	if _, err := restored.DecodeChecked(0); err != ErrExpired {
+		t.Errorf("error was %+v and not an expired error", err)
+	}
+	if restored.Version() != 5 {
+		t.Errorf("version was %d and not 5", restored.Version())
+	}
+}
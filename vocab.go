@@ -0,0 +1,113 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// WriteVocab will write the encoder to `w` as a plain-text
+// vocabulary file: one value per line, where the line number
+// (from 0) is the code of the value. This is the vocabulary
+// format read by TensorFlow and Keras lookup layers and many
+// tokenizers. Aliases are not written. An `ErrFormat` error is
+// returned if a value contains a line break.
+func (e *Ordinal) WriteVocab(w io.Writer) error {
+	e.RLock()
+	defer e.RUnlock()
+
+	return writeVocab(w, e.decoder.strings())
+}
+
+// ReadVocab will replace the contents of the encoder with the
+// values of a plain-text vocabulary file read from `r`, where
+// the line number (from 0) of every value is its code. An
+// `ErrDuplicateValue` error is returned if a value is repeated.
+func (e *Ordinal) ReadVocab(r io.Reader) error {
+	values, err := readVocab(r)
+	if err != nil {
+		return err
+	}
+
+	codes := make([]uint64, len(values), len(values))
+	for i := range codes {
+		codes[i] = uint64(i)
+	}
+
+	e.Lock()
+	defer e.Unlock()
+
+	return e.loadRows(values, codes)
+}
+
+// WriteVocab will write the values of the encoder to `w` as a
+// plain-text vocabulary file, one value per line in the order
+// of their dimensions.
+func (e *OneHot) WriteVocab(w io.Writer) error {
+	return writeVocab(w, e.decoder)
+}
+
+// ReadVocab will replace the values of the encoder with the
+// values of a plain-text vocabulary file read from `r`, in the
+// order of their dimensions. An `ErrDuplicateValue` error is
+// returned if a value is repeated.
+func (e *OneHot) ReadVocab(r io.Reader) error {
+	values, err := readVocab(r)
+	if err != nil {
+		return err
+	}
+	e.load(values)
+
+	return nil
+}
+
+func writeVocab(w io.Writer, values []string) error {
+	b := bufio.NewWriter(w)
+	for _, v := range values {
+		if strings.ContainsAny(v, "\r\n") {
+			return ErrFormat
+		}
+
+		_, err := b.WriteString(v)
+		if err != nil {
+			return err
+		}
+		err = b.WriteByte('\n')
+		if err != nil {
+			return err
+		}
+	}
+
+	return b.Flush()
+}
+
+func readVocab(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+	values := make([]string, 0)
+	seen := make(map[string]bool)
+	for scanner.Scan() {
+		v := strings.TrimSuffix(scanner.Text(), "\r")
+		if seen[v] {
+			return nil, ErrDuplicateValue
+		}
+		seen[v] = true
+		values = append(values, v)
+	}
+
+	return values, scanner.Err()
+}
@@ -0,0 +1,59 @@
+package encoder
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestOrdinalVocab(t *testing.T) {
+	e := NewOrdinal(true)
+	e.EncodeSlice([]string{"red", "green", "blue"})
+
+	var buf bytes.Buffer
+	if err := e.WriteVocab(&buf); err != nil {
+		t.Fatalf("write error: %+v", err)
+	}
+	if buf.String() != "\nred\ngreen\nblue\n" {
+		t.Errorf("unexpected vocab file %q", buf.String())
+	}
+
+	loaded := NewOrdinal(false)
+	if err := loaded.ReadVocab(&buf); err != nil {
+		t.Fatalf("read error: %+v", err)
+	}
+	if err := Compatible(e, loaded); err != nil || loaded.Length() != 4 {
+		t.Error("vocab did not round trip")
+	}
+
+	if err := loaded.ReadVocab(strings.NewReader("a\r\nb\r\n")); err != nil || loaded.Decode(1) != "b" {
+		t.Error("CRLF vocab was not read")
+	}
+	if err := loaded.ReadVocab(strings.NewReader("a\nb\na\n")); err != ErrDuplicateValue {
+		t.Error("expected duplicate value error")
+	}
+
+	e.Encode("multi\nline")
+	if err := e.WriteVocab(&buf); err != ErrFormat {
+		t.Error("expected format error")
+	}
+}
+
+func TestOneHotVocab(t *testing.T) {
+	e := NewOneHot()
+	e.Encode("red")
+	e.Encode("green")
+
+	var buf bytes.Buffer
+	if err := e.WriteVocab(&buf); err != nil {
+		t.Fatalf("write error: %+v", err)
+	}
+
+	loaded := NewOneHot()
+	if err := loaded.ReadVocab(&buf); err != nil {
+		t.Fatalf("read error: %+v", err)
+	}
+	if loaded.Dimension() != e.Dimension() || loaded.Encode("green")[2] != 1 {
+		t.Error("vocab did not round trip")
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoder
+
+import (
+	"bufio"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+)
+
+// SetWAL will append a record to `w` for every value the
+// encoder assigns a new code to, before Encode returns, so
+// that the codes can be restored with ReplayWAL after a crash.
+// If `w` has a `Sync() error` method, such as an `*os.File`,
+// it is called after every record.
+// A nil writer stops logging.
+//
+// A log is typically replayed, then appended to:
+//
+//	e := NewOrdinal(true)
+//	err := e.ReplayWAL(f)
+//	...
+//	e.SetWAL(f)
+func (e *Ordinal) SetWAL(w io.Writer) {
+	e.Lock()
+	defer e.Unlock()
+
+	e.wal = w
+	e.walErr = nil
+}
+
+// WALError will return the last error that occurred while
+// writing to the write-ahead log, or nil. Values whose record
+// could not be written are still encoded, but their codes will
+// not survive a crash.
+func (e *Ordinal) WALError() error {
+	e.RLock()
+	defer e.RUnlock()
+
+	return e.walErr
+}
+
+// ReplayWAL will encode every value recorded in the log read
+// from `r`, restoring the codes they were assigned. Records for
+// codes the encoder already has are checked rather than encoded,
+// so a log can be replayed into an encoder initialized with the
+// empty string. Codes assigned with AssignCode are assigned
+// again, reserving any codes they skip over. A final record cut
+// short by a crash is ignored. Any other damaged record, or a
+// record whose value would not receive its logged code, such as
+// a duplicated record or one replayed onto a different base
+// snapshot, returns an `ErrCorruptSnapshot` error.
+func (e *Ordinal) ReplayWAL(r io.Reader) error {
+	br := bufio.NewReader(r)
+
+	e.Lock()
+	defer e.Unlock()
+
+	for {
+		code, value, err := readWALRecord(br)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		version := uint64(e.decoder.len())
+		switch {
+		case code < version && !e.reserved[code]:
+			if e.decoder.get(int(code)) != value {
+				return ErrCorruptSnapshot
+			}
+		case code == version:
+			if e.encode(value) != code {
+				return ErrCorruptSnapshot
+			}
+		default:
+			err = e.assign(value, code)
+			if err != nil {
+				return ErrCorruptSnapshot
+			}
+		}
+	}
+}
+
+// logWAL will write the record for a newly encoded value.
+func (e *Ordinal) logWAL(s string, code uint64) error {
+	_, err := e.wal.Write(walRecord(s, code))
+	if err == nil {
+		if syncer, ok := e.wal.(interface{ Sync() error }); ok {
+			err = syncer.Sync()
+		}
+	}
+
+	if err != nil {
+		e.walErr = err
+	}
+
+	return err
+}
+
+// maxWALValue is the length of the longest value a record
+// can hold, so that a damaged length cannot make replaying
+// the log allocate an arbitrary amount of memory.
+const maxWALValue = 1 << 26
+
+// walRecord will return the record for a value: its code and
+// length as uvarints, the value, then the CRC-32 of all three.
+func walRecord(s string, code uint64) []byte {
+	record := make([]byte, 0, 2*binary.MaxVarintLen64+len(s)+4)
+	record = appendUvarint(record, code)
+	record = appendUvarint(record, uint64(len(s)))
+	record = append(record, s...)
+
+	var checksum [4]byte
+	binary.LittleEndian.PutUint32(checksum[:], crc32.ChecksumIEEE(record))
+	return append(record, checksum[:]...)
+}
+
+func readWALRecord(r *bufio.Reader) (uint64, string, error) {
+	code, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, "", err
+	}
+
+	length, err := binary.ReadUvarint(r)
+	if err == io.EOF {
+		return 0, "", io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	if length > maxWALValue {
+		return 0, "", ErrCorruptSnapshot
+	}
+
+	rest := make([]byte, length+4)
+	_, err = io.ReadFull(r, rest)
+	if err != nil {
+		return 0, "", io.ErrUnexpectedEOF
+	}
+
+	value := string(rest[:length])
+	expected := walRecord(value, code)
+	if binary.LittleEndian.Uint32(rest[length:]) != binary.LittleEndian.Uint32(expected[len(expected)-4:]) {
+		return 0, "", ErrCorruptSnapshot
+	}
+
+	return code, value, nil
+}
@@ -0,0 +1,51 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestOrdinalWAL(t *testing.T) {
+	var log bytes.Buffer
+
+	encoder := NewOrdinal(true)
+	encoder.SetWAL(&log)
+	encoder.Encode("red")
+	encoder.Encode("green")
+	encoder.Encode("red")
+
+	// a record cut short by a crash
+	torn := walRecord("blue", 3)
+	log.Write(torn[:len(torn)-2])
+
+	restored := NewOrdinal(true)
+	err := restored.ReplayWAL(bytes.NewReader(log.Bytes()))
+	if err != nil {
+		t.Fatalf("replay error: %+v", err)
+	}
+	if restored.Fingerprint() != encoder.Fingerprint() {
+		t.Error("restored encoder did not match original encoder")
+	}
+
+	corrupt := bytes.Replace(log.Bytes(), []byte("green"), []byte("grean"), 1)
+	err = NewOrdinal(true).ReplayWAL(bytes.NewReader(corrupt))
+	if err != ErrCorruptSnapshot {
+		t.Errorf("error was %+v and not a corrupt snapshot error", err)
+	}
+
+	// a value logged twice under different codes
+	duplicated := append(walRecord("red", 1), walRecord("red", 2)...)
+	err = NewOrdinal(true).ReplayWAL(bytes.NewReader(duplicated))
+	if err != ErrCorruptSnapshot {
+		t.Errorf("error was %+v and not a corrupt snapshot error", err)
+	}
+
+	// a damaged length larger than any value
+	huge := appendUvarint(nil, 1)
+	huge = appendUvarint(huge, 1<<62)
+	err = NewOrdinal(true).ReplayWAL(bytes.NewReader(append(huge, make([]byte, binary.MaxVarintLen64)...)))
+	if err != ErrCorruptSnapshot {
+		t.Errorf("error was %+v and not a corrupt snapshot error", err)
+	}
+}
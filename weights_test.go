@@ -0,0 +1,66 @@
+package encoder
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWeightedTargetEncoders(t *testing.T) {
+	values := []string{"a", "a", "b"}
+	weights := []float64{3, 1, 0}
+
+	js, err := NewJamesSteinRegressionWeighted(values, []float64{1, 5, 7}, weights)
+	if err != nil {
+		t.Fatalf("encoder error: %+v", err)
+	}
+	if v, ok := js.Get("a"); !ok || math.Abs(v-2) > 1e-9 {
+		t.Errorf("weighted mean was %f and not 2", v)
+	}
+	if _, ok := js.Get("b"); ok {
+		t.Error("category without weight should be unseen")
+	}
+
+	ratio, err := NewProbabilityRatioWeighted(values, []bool{true, false, true}, weights, 0)
+	if err != nil {
+		t.Fatalf("encoder error: %+v", err)
+	}
+	if v, _ := ratio.Get("a"); math.Abs(v-3) > 1e-9 {
+		t.Errorf("weighted ratio was %f and not 3", v)
+	}
+
+	logOdds, _ := NewLogOddsWeighted(values, []bool{true, false, true}, weights, 0)
+	if v, _ := logOdds.Get("a"); math.Abs(v-math.Log(3)) > 1e-9 {
+		t.Errorf("weighted log odds was %f", v)
+	}
+
+	multiclass, err := NewMulticlassTargetWeighted(values, []string{"x", "y", "y"}, weights, 0)
+	if err != nil {
+		t.Fatalf("encoder error: %+v", err)
+	}
+	if p, _ := multiclass.Get("a"); math.Abs(p[0]-0.75) > 1e-9 {
+		t.Errorf("weighted class probabilities were %v", p)
+	}
+
+	if _, ok := ratio.Get("b"); ok {
+		t.Error("category without weight should be unseen")
+	}
+	if _, ok := logOdds.Get("b"); ok {
+		t.Error("category without weight should be unseen")
+	}
+	if _, ok := multiclass.Get("b"); ok {
+		t.Error("category without weight should be unseen")
+	}
+
+	if _, err := NewProbabilityRatioWeighted(values, []bool{true, false, true}, weights[1:], 0); err != ErrWeightLength {
+		t.Error("expected weight length error")
+	}
+	for _, w := range []float64{-1, math.NaN(), math.Inf(1)} {
+		bad := []float64{1, w, 1}
+		if _, err := NewJamesSteinRegressionWeighted(values, []float64{1, 5, 7}, bad); err != ErrWeight {
+			t.Errorf("error for weight %f was %+v and not a weight error", w, err)
+		}
+		if _, err := NewLogOddsWeighted(values, []bool{true, false, true}, bad, 0); err != ErrWeight {
+			t.Errorf("error for weight %f was %+v and not a weight error", w, err)
+		}
+	}
+}
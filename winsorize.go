@@ -0,0 +1,110 @@
+// Copyright 2020 Humility AI Incorporated, All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encoder
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+)
+
+// Winsorizer clips numeric values to bounds fitted at a
+// lower and upper quantile of the data (e.g. the 1st and
+// 99th percentiles) so outliers are tamed before scaling.
+type Winsorizer struct {
+	lower, upper float64
+}
+
+// NewWinsorizer will fit clipping bounds at the `lower` and `upper`
+// quantiles of the values, which are given between 0 and 1.
+// An `ErrQuantile` error is returned if lower is greater than
+// upper or either is out of range, and an `ErrNoData` error
+// if there are no values.
+func NewWinsorizer(values []float64, lower, upper float64) (*Winsorizer, error) {
+	if lower < 0 || upper > 1 || lower > upper {
+		return &Winsorizer{}, ErrQuantile
+	}
+	if len(values) == 0 {
+		return &Winsorizer{}, ErrNoData
+	}
+
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+
+	return &Winsorizer{
+		lower: quantile(sorted, lower),
+		upper: quantile(sorted, upper),
+	}, nil
+}
+
+// NewClipper will return a Winsorizer with fixed bounds.
+func NewClipper(lower, upper float64) (*Winsorizer, error) {
+	if lower > upper {
+		return &Winsorizer{}, ErrBounds
+	}
+
+	return &Winsorizer{
+		lower: lower,
+		upper: upper,
+	}, nil
+}
+
+// Bounds will return the lower and upper clipping bounds.
+func (w *Winsorizer) Bounds() (lower, upper float64) {
+	return w.lower, w.upper
+}
+
+// Transform will return the value clipped to the bounds.
+func (w *Winsorizer) Transform(x float64) float64 {
+	return math.Min(math.Max(x, w.lower), w.upper)
+}
+
+// TransformSlice will return the values clipped to the bounds.
+func (w *Winsorizer) TransformSlice(values []float64) []float64 {
+	clipped := make([]float64, len(values), len(values))
+	for i, v := range values {
+		clipped[i] = w.Transform(v)
+	}
+
+	return clipped
+}
+
+type winsorizerJSON struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+// MarshalJSON will encode the fitted bounds.
+func (w *Winsorizer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(winsorizerJSON{
+		Lower: w.lower,
+		Upper: w.upper,
+	})
+}
+
+// UnmarshalJSON will return an `ErrBounds` error
+// if the lower bound is greater than the upper.
+func (w *Winsorizer) UnmarshalJSON(data []byte) error {
+	var bounds winsorizerJSON
+	err := json.Unmarshal(data, &bounds)
+	if err != nil {
+		return err
+	}
+	if bounds.Lower > bounds.Upper {
+		return ErrBounds
+	}
+
+	w.lower, w.upper = bounds.Lower, bounds.Upper
+	return nil
+}
@@ -0,0 +1,47 @@
+package encoder
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestWinsorizer(t *testing.T) {
+	values := []float64{-1000, 1, 2, 3, 4, 5, 6, 7, 8, 1000}
+	w, err := NewWinsorizer(values, 0.1, 0.9)
+	if err != nil {
+		t.Fatalf("fit error: %+v", err)
+	}
+
+	lower, upper := w.Bounds()
+	if lower <= -1000 || upper >= 1000 {
+		t.Errorf("bounds %f, %f include the outliers", lower, upper)
+	}
+	clipped := w.TransformSlice(values)
+	if clipped[0] != lower || clipped[9] != upper || clipped[4] != 4 {
+		t.Errorf("unexpected clipping: %v", clipped)
+	}
+
+	b, err := json.Marshal(w)
+	if err != nil {
+		t.Fatalf("marshal error: %+v", err)
+	}
+	var loaded Winsorizer
+	if err := json.Unmarshal(b, &loaded); err != nil {
+		t.Fatalf("unmarshal error: %+v", err)
+	}
+	if loaded != *w {
+		t.Error("bounds did not round trip")
+	}
+
+	if _, err := NewWinsorizer(values, 0.9, 0.1); err != ErrQuantile {
+		t.Error("expected quantile error")
+	}
+	if _, err := NewWinsorizer(nil, 0.1, 0.9); err != ErrNoData {
+		t.Error("expected no data error")
+	}
+
+	c, err := NewClipper(0, 1)
+	if err != nil || c.Transform(2) != 1 || c.Transform(-2) != 0 {
+		t.Error("fixed bounds did not clip")
+	}
+}